@@ -0,0 +1,216 @@
+package autoscaler
+
+import (
+	"os"
+	"time"
+
+	"github.com/cloudfoundry-incubator/bbs"
+	"github.com/cloudfoundry-incubator/bbs/models"
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/store"
+	"github.com/pivotal-golang/clock"
+	"github.com/pivotal-golang/lager"
+	"github.com/tedsuo/ifrit"
+)
+
+const (
+	DefaultInterval = 30 * time.Second
+	DefaultCooldown = 3 * time.Minute
+)
+
+type controller struct {
+	bbs         bbs.Client
+	policies    PolicyStore
+	stoppedLRPs store.StoppedLRPStore
+	metrics     MetricsSource
+	clock       clock.Clock
+	interval    time.Duration
+	cooldown    time.Duration
+	logger      lager.Logger
+
+	lastScaled map[string]time.Time
+}
+
+// NewController returns a runner which, every interval, scales each
+// DesiredLRP with an autoscaling policy according to that policy. Stopped
+// DesiredLRPs are left alone, and so are DesiredLRPs scaled less than cooldown
+// ago, giving their new instances time to affect the metrics.
+//
+// Rules adjust the current instance count, so two controllers would each
+// apply the same adjustment. Only one receptor may run a controller at a
+// time.
+func NewController(
+	bbs bbs.Client,
+	policies PolicyStore,
+	stoppedLRPs store.StoppedLRPStore,
+	metrics MetricsSource,
+	clock clock.Clock,
+	interval time.Duration,
+	cooldown time.Duration,
+	logger lager.Logger,
+) ifrit.Runner {
+	return &controller{
+		bbs:         bbs,
+		policies:    policies,
+		stoppedLRPs: stoppedLRPs,
+		metrics:     metrics,
+		clock:       clock,
+		interval:    interval,
+		cooldown:    cooldown,
+		logger:      logger.Session("autoscaler"),
+		lastScaled:  make(map[string]time.Time),
+	}
+}
+
+func (c *controller) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	close(ready)
+
+	for {
+		timer := c.clock.NewTimer(c.interval)
+		select {
+		case <-signals:
+			timer.Stop()
+			return nil
+		case <-timer.C():
+			c.reconcile()
+		}
+	}
+}
+
+func (c *controller) reconcile() {
+	logger := c.logger.Session("reconcile")
+
+	policies, err := c.policies.List()
+	if err != nil {
+		logger.Error("failed-to-fetch-policies", err)
+		return
+	}
+	if len(policies) == 0 {
+		return
+	}
+
+	desiredLRPs, err := c.bbs.DesiredLRPs(models.DesiredLRPFilter{})
+	if err != nil {
+		logger.Error("failed-to-fetch-desired-lrps", err)
+		return
+	}
+
+	byProcessGuid := make(map[string]*models.DesiredLRP, len(desiredLRPs))
+	for _, desiredLRP := range desiredLRPs {
+		byProcessGuid[desiredLRP.ProcessGuid] = desiredLRP
+	}
+
+	now := c.clock.Now()
+	for _, policy := range policies {
+		desiredLRP, ok := byProcessGuid[policy.ProcessGuid]
+		if !ok || c.stopped(logger, desiredLRP) {
+			continue
+		}
+
+		if lastScaled, ok := c.lastScaled[policy.ProcessGuid]; ok && now.Sub(lastScaled) < c.cooldown {
+			continue
+		}
+
+		current := int(desiredLRP.Instances)
+		target := c.target(logger, policy, current)
+		if target == current {
+			continue
+		}
+
+		instances := int32(target)
+		err = c.bbs.UpdateDesiredLRP(desiredLRP.ProcessGuid, &models.DesiredLRPUpdate{Instances: &instances})
+		if err != nil {
+			logger.Error("failed-to-scale", err, lager.Data{"process-guid": desiredLRP.ProcessGuid})
+			continue
+		}
+
+		c.lastScaled[policy.ProcessGuid] = now
+		logger.Info("scaled", lager.Data{
+			"process-guid": desiredLRP.ProcessGuid,
+			"from":         current,
+			"to":           target,
+		})
+	}
+
+	for processGuid, lastScaled := range c.lastScaled {
+		if now.Sub(lastScaled) >= c.cooldown {
+			delete(c.lastScaled, processGuid)
+		}
+	}
+}
+
+// target applies the first triggered rule of policy to the current instance
+// count, keeping the result within the bounds of the policy.
+func (c *controller) target(logger lager.Logger, policy receptor.AutoscalingPolicy, current int) int {
+	target := current
+	for _, rule := range policy.Rules {
+		value, err := c.metric(policy.ProcessGuid, rule.Metric)
+		if err != nil {
+			logger.Error("failed-to-fetch-metric", err, lager.Data{
+				"process-guid": policy.ProcessGuid,
+				"metric":       rule.Metric,
+			})
+			continue
+		}
+
+		if triggered(rule, value) {
+			target = current + rule.Adjustment
+			break
+		}
+	}
+
+	if target < policy.MinInstances {
+		target = policy.MinInstances
+	}
+	if target > policy.MaxInstances {
+		target = policy.MaxInstances
+	}
+	return target
+}
+
+func (c *controller) metric(processGuid, name string) (float64, error) {
+	if name != receptor.AutoscalingMetricCrashRate {
+		return c.metrics.Metric(processGuid, name)
+	}
+
+	groups, err := c.bbs.ActualLRPGroupsByProcessGuid(processGuid)
+	if err != nil {
+		return 0, err
+	}
+	if len(groups) == 0 {
+		return 0, nil
+	}
+
+	crashed := 0
+	for _, group := range groups {
+		actualLRP, _ := group.Resolve()
+		if actualLRP.State == models.ActualLRPStateCrashed {
+			crashed++
+		}
+	}
+	return float64(crashed) / float64(len(groups)), nil
+}
+
+func triggered(rule receptor.AutoscalingRule, value float64) bool {
+	switch rule.Comparison {
+	case receptor.AutoscalingComparisonAbove:
+		return value > rule.Threshold
+	case receptor.AutoscalingComparisonBelow:
+		return value < rule.Threshold
+	default:
+		return false
+	}
+}
+
+// stopped reports whether desiredLRP was stopped. When that is unknown, the
+// DesiredLRP is left alone as if it were.
+func (c *controller) stopped(logger lager.Logger, desiredLRP *models.DesiredLRP) bool {
+	_, err := c.stoppedLRPs.Instances(desiredLRP)
+	if err == store.ErrNotStopped {
+		return false
+	}
+	if err != nil {
+		logger.Error("failed-to-fetch-stopped-instances", err, lager.Data{"process-guid": desiredLRP.ProcessGuid})
+	}
+	return true
+}
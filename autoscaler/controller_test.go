@@ -0,0 +1,277 @@
+package autoscaler_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/bbs/fake_bbs"
+	"github.com/cloudfoundry-incubator/bbs/models"
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/autoscaler"
+	"github.com/cloudfoundry-incubator/receptor/autoscaler/fake_autoscaler"
+	"github.com/cloudfoundry-incubator/receptor/store"
+	"github.com/cloudfoundry-incubator/receptor/store/fake_store"
+	"github.com/pivotal-golang/clock/fakeclock"
+	"github.com/pivotal-golang/lager/lagertest"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/ginkgomon"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Controller", func() {
+	const (
+		interval = 30 * time.Second
+		cooldown = 2 * interval
+	)
+
+	var (
+		fakeBBS         *fake_bbs.FakeClient
+		fakePolicies    *fake_autoscaler.FakePolicyStore
+		fakeStoppedLRPs *fake_store.FakeStoppedLRPStore
+		fakeMetrics     *fake_autoscaler.FakeMetricsSource
+		fakeClock       *fakeclock.FakeClock
+
+		policy     receptor.AutoscalingPolicy
+		desiredLRP *models.DesiredLRP
+		crashed    int
+		running    int
+
+		process ifrit.Process
+	)
+
+	reconcile := func() {
+		Eventually(fakeClock.WatcherCount).Should(Equal(1))
+		calls := fakePolicies.ListCallCount()
+		fakeClock.Increment(interval)
+		Eventually(fakePolicies.ListCallCount).Should(Equal(calls + 1))
+		Eventually(fakeClock.WatcherCount).Should(Equal(1))
+	}
+
+	scaledTo := func() []int32 {
+		result := []int32{}
+		for i := 0; i < fakeBBS.UpdateDesiredLRPCallCount(); i++ {
+			_, update := fakeBBS.UpdateDesiredLRPArgsForCall(i)
+			result = append(result, *update.Instances)
+		}
+		return result
+	}
+
+	BeforeEach(func() {
+		fakeBBS = new(fake_bbs.FakeClient)
+		fakePolicies = new(fake_autoscaler.FakePolicyStore)
+		fakeStoppedLRPs = new(fake_store.FakeStoppedLRPStore)
+		fakeStoppedLRPs.InstancesReturns(0, store.ErrNotStopped)
+		fakeMetrics = new(fake_autoscaler.FakeMetricsSource)
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+
+		policy = receptor.AutoscalingPolicy{
+			ProcessGuid:  "process-guid",
+			MinInstances: 1,
+			MaxInstances: 4,
+			Rules: []receptor.AutoscalingRule{{
+				Metric:     receptor.AutoscalingMetricCrashRate,
+				Comparison: receptor.AutoscalingComparisonAbove,
+				Threshold:  0.5,
+				Adjustment: -1,
+			}},
+		}
+		desiredLRP = &models.DesiredLRP{ProcessGuid: "process-guid", Instances: 3}
+		crashed, running = 0, 3
+
+		fakeBBS.DesiredLRPsStub = func(models.DesiredLRPFilter) ([]*models.DesiredLRP, error) {
+			return []*models.DesiredLRP{desiredLRP}, nil
+		}
+		fakeBBS.ActualLRPGroupsByProcessGuidStub = func(string) ([]*models.ActualLRPGroup, error) {
+			groups := []*models.ActualLRPGroup{}
+			for i := 0; i < crashed; i++ {
+				groups = append(groups, &models.ActualLRPGroup{Instance: &models.ActualLRP{State: models.ActualLRPStateCrashed}})
+			}
+			for i := 0; i < running; i++ {
+				groups = append(groups, &models.ActualLRPGroup{Instance: &models.ActualLRP{State: models.ActualLRPStateRunning}})
+			}
+			return groups, nil
+		}
+	})
+
+	JustBeforeEach(func() {
+		fakePolicies.ListReturns([]receptor.AutoscalingPolicy{policy}, nil)
+		process = ginkgomon.Invoke(autoscaler.NewController(fakeBBS, fakePolicies, fakeStoppedLRPs, fakeMetrics, fakeClock, interval, cooldown, lagertest.NewTestLogger("test")))
+	})
+
+	AfterEach(func() {
+		ginkgomon.Interrupt(process)
+	})
+
+	It("does nothing until the interval has passed", func() {
+		Consistently(fakePolicies.ListCallCount).Should(Equal(0))
+	})
+
+	It("leaves the desired LRP alone when no rule is triggered", func() {
+		reconcile()
+		Expect(fakeBBS.UpdateDesiredLRPCallCount()).To(Equal(0))
+	})
+
+	Context("when a rule is triggered", func() {
+		BeforeEach(func() {
+			crashed, running = 2, 1
+		})
+
+		It("adjusts the instance count", func() {
+			reconcile()
+			Expect(scaledTo()).To(Equal([]int32{2}))
+
+			processGuid, _ := fakeBBS.UpdateDesiredLRPArgsForCall(0)
+			Expect(processGuid).To(Equal("process-guid"))
+		})
+
+		It("waits for the cooldown to pass before adjusting it again", func() {
+			reconcile()
+			Expect(scaledTo()).To(Equal([]int32{2}))
+
+			reconcile()
+			Expect(scaledTo()).To(Equal([]int32{2}))
+
+			reconcile()
+			Expect(scaledTo()).To(Equal([]int32{2, 2}))
+		})
+
+		Context("when scaling fails", func() {
+			BeforeEach(func() {
+				fakeBBS.UpdateDesiredLRPReturns(errors.New("oops"))
+			})
+
+			It("tries again at the next interval", func() {
+				reconcile()
+				reconcile()
+				Expect(fakeBBS.UpdateDesiredLRPCallCount()).To(Equal(2))
+			})
+		})
+
+		Context("when the adjustment would leave the bounds of the policy", func() {
+			BeforeEach(func() {
+				desiredLRP.Instances = 1
+			})
+
+			It("keeps the instance count within them", func() {
+				reconcile()
+				Expect(fakeBBS.UpdateDesiredLRPCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Context("when the instance count is outside the bounds of the policy", func() {
+		BeforeEach(func() {
+			desiredLRP.Instances = 7
+		})
+
+		It("brings it within them", func() {
+			reconcile()
+			Expect(scaledTo()).To(Equal([]int32{4}))
+		})
+	})
+
+	Context("when a rule is based on the metrics source", func() {
+		BeforeEach(func() {
+			policy.Rules = []receptor.AutoscalingRule{
+				{Metric: "cpu", Comparison: receptor.AutoscalingComparisonBelow, Threshold: 10, Adjustment: -2},
+				{Metric: "cpu", Comparison: receptor.AutoscalingComparisonAbove, Threshold: 80, Adjustment: 1},
+			}
+			fakeMetrics.MetricReturns(95, nil)
+		})
+
+		It("applies the first rule which is triggered", func() {
+			reconcile()
+			Expect(scaledTo()).To(Equal([]int32{4}))
+
+			processGuid, name := fakeMetrics.MetricArgsForCall(0)
+			Expect(processGuid).To(Equal("process-guid"))
+			Expect(name).To(Equal("cpu"))
+		})
+
+		Context("when the metric is unavailable", func() {
+			BeforeEach(func() {
+				fakeMetrics.MetricReturns(0, autoscaler.ErrMetricUnavailable)
+			})
+
+			It("skips the rules based on it", func() {
+				reconcile()
+				Expect(fakeBBS.UpdateDesiredLRPCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Context("when the desired LRP is stopped", func() {
+		BeforeEach(func() {
+			fakeStoppedLRPs.InstancesReturns(3, nil)
+			desiredLRP.Instances = 0
+		})
+
+		It("leaves it stopped", func() {
+			reconcile()
+			Expect(fakeBBS.UpdateDesiredLRPCallCount()).To(Equal(0))
+			Expect(fakeStoppedLRPs.InstancesArgsForCall(0)).To(Equal(desiredLRP))
+		})
+	})
+
+	Context("when it is unknown whether the desired LRP is stopped", func() {
+		BeforeEach(func() {
+			fakeStoppedLRPs.InstancesReturns(0, errors.New("oops"))
+			desiredLRP.Instances = 0
+		})
+
+		It("leaves it alone", func() {
+			reconcile()
+			Expect(fakeBBS.UpdateDesiredLRPCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the desired LRP of a policy does not exist", func() {
+		BeforeEach(func() {
+			desiredLRP.ProcessGuid = "other-guid"
+			desiredLRP.Instances = 7
+		})
+
+		It("skips the policy", func() {
+			reconcile()
+			Expect(fakeBBS.UpdateDesiredLRPCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when there are no policies", func() {
+		JustBeforeEach(func() {
+			fakePolicies.ListReturns([]receptor.AutoscalingPolicy{}, nil)
+		})
+
+		It("does not fetch the desired LRPs", func() {
+			reconcile()
+			Expect(fakeBBS.DesiredLRPsCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when fetching the policies fails", func() {
+		JustBeforeEach(func() {
+			fakePolicies.ListReturns(nil, errors.New("oops"))
+		})
+
+		It("tries again at the next interval", func() {
+			reconcile()
+			reconcile()
+			Expect(fakeBBS.UpdateDesiredLRPCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when fetching the desired LRPs fails", func() {
+		BeforeEach(func() {
+			fakeBBS.DesiredLRPsStub = nil
+			fakeBBS.DesiredLRPsReturns(nil, errors.New("oops"))
+		})
+
+		It("tries again at the next interval", func() {
+			reconcile()
+			reconcile()
+			Expect(fakeBBS.UpdateDesiredLRPCallCount()).To(Equal(0))
+		})
+	})
+})
@@ -0,0 +1,57 @@
+// This file was generated by counterfeiter
+package fake_autoscaler
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry-incubator/receptor/autoscaler"
+)
+
+type FakeMetricsSource struct {
+	MetricStub        func(processGuid string, name string) (float64, error)
+	metricMutex       sync.RWMutex
+	metricArgsForCall []struct {
+		processGuid string
+		name        string
+	}
+	metricReturns struct {
+		result1 float64
+		result2 error
+	}
+}
+
+func (fake *FakeMetricsSource) Metric(processGuid string, name string) (float64, error) {
+	fake.metricMutex.Lock()
+	fake.metricArgsForCall = append(fake.metricArgsForCall, struct {
+		processGuid string
+		name        string
+	}{processGuid, name})
+	fake.metricMutex.Unlock()
+	if fake.MetricStub != nil {
+		return fake.MetricStub(processGuid, name)
+	} else {
+		return fake.metricReturns.result1, fake.metricReturns.result2
+	}
+}
+
+func (fake *FakeMetricsSource) MetricCallCount() int {
+	fake.metricMutex.RLock()
+	defer fake.metricMutex.RUnlock()
+	return len(fake.metricArgsForCall)
+}
+
+func (fake *FakeMetricsSource) MetricArgsForCall(i int) (string, string) {
+	fake.metricMutex.RLock()
+	defer fake.metricMutex.RUnlock()
+	return fake.metricArgsForCall[i].processGuid, fake.metricArgsForCall[i].name
+}
+
+func (fake *FakeMetricsSource) MetricReturns(result1 float64, result2 error) {
+	fake.MetricStub = nil
+	fake.metricReturns = struct {
+		result1 float64
+		result2 error
+	}{result1, result2}
+}
+
+var _ autoscaler.MetricsSource = new(FakeMetricsSource)
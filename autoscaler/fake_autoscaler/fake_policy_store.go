@@ -0,0 +1,168 @@
+// This file was generated by counterfeiter
+package fake_autoscaler
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/autoscaler"
+)
+
+type FakePolicyStore struct {
+	GetStub        func(processGuid string) (receptor.AutoscalingPolicy, error)
+	getMutex       sync.RWMutex
+	getArgsForCall []struct {
+		processGuid string
+	}
+	getReturns struct {
+		result1 receptor.AutoscalingPolicy
+		result2 error
+	}
+	SetStub        func(policy receptor.AutoscalingPolicy) error
+	setMutex       sync.RWMutex
+	setArgsForCall []struct {
+		policy receptor.AutoscalingPolicy
+	}
+	setReturns struct {
+		result1 error
+	}
+	RemoveStub        func(processGuid string) error
+	removeMutex       sync.RWMutex
+	removeArgsForCall []struct {
+		processGuid string
+	}
+	removeReturns struct {
+		result1 error
+	}
+	ListStub        func() ([]receptor.AutoscalingPolicy, error)
+	listMutex       sync.RWMutex
+	listArgsForCall []struct{}
+	listReturns     struct {
+		result1 []receptor.AutoscalingPolicy
+		result2 error
+	}
+}
+
+func (fake *FakePolicyStore) Get(processGuid string) (receptor.AutoscalingPolicy, error) {
+	fake.getMutex.Lock()
+	fake.getArgsForCall = append(fake.getArgsForCall, struct {
+		processGuid string
+	}{processGuid})
+	fake.getMutex.Unlock()
+	if fake.GetStub != nil {
+		return fake.GetStub(processGuid)
+	} else {
+		return fake.getReturns.result1, fake.getReturns.result2
+	}
+}
+
+func (fake *FakePolicyStore) GetCallCount() int {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	return len(fake.getArgsForCall)
+}
+
+func (fake *FakePolicyStore) GetArgsForCall(i int) string {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	return fake.getArgsForCall[i].processGuid
+}
+
+func (fake *FakePolicyStore) GetReturns(result1 receptor.AutoscalingPolicy, result2 error) {
+	fake.GetStub = nil
+	fake.getReturns = struct {
+		result1 receptor.AutoscalingPolicy
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePolicyStore) Set(policy receptor.AutoscalingPolicy) error {
+	fake.setMutex.Lock()
+	fake.setArgsForCall = append(fake.setArgsForCall, struct {
+		policy receptor.AutoscalingPolicy
+	}{policy})
+	fake.setMutex.Unlock()
+	if fake.SetStub != nil {
+		return fake.SetStub(policy)
+	} else {
+		return fake.setReturns.result1
+	}
+}
+
+func (fake *FakePolicyStore) SetCallCount() int {
+	fake.setMutex.RLock()
+	defer fake.setMutex.RUnlock()
+	return len(fake.setArgsForCall)
+}
+
+func (fake *FakePolicyStore) SetArgsForCall(i int) receptor.AutoscalingPolicy {
+	fake.setMutex.RLock()
+	defer fake.setMutex.RUnlock()
+	return fake.setArgsForCall[i].policy
+}
+
+func (fake *FakePolicyStore) SetReturns(result1 error) {
+	fake.SetStub = nil
+	fake.setReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePolicyStore) Remove(processGuid string) error {
+	fake.removeMutex.Lock()
+	fake.removeArgsForCall = append(fake.removeArgsForCall, struct {
+		processGuid string
+	}{processGuid})
+	fake.removeMutex.Unlock()
+	if fake.RemoveStub != nil {
+		return fake.RemoveStub(processGuid)
+	} else {
+		return fake.removeReturns.result1
+	}
+}
+
+func (fake *FakePolicyStore) RemoveCallCount() int {
+	fake.removeMutex.RLock()
+	defer fake.removeMutex.RUnlock()
+	return len(fake.removeArgsForCall)
+}
+
+func (fake *FakePolicyStore) RemoveArgsForCall(i int) string {
+	fake.removeMutex.RLock()
+	defer fake.removeMutex.RUnlock()
+	return fake.removeArgsForCall[i].processGuid
+}
+
+func (fake *FakePolicyStore) RemoveReturns(result1 error) {
+	fake.RemoveStub = nil
+	fake.removeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePolicyStore) List() ([]receptor.AutoscalingPolicy, error) {
+	fake.listMutex.Lock()
+	fake.listArgsForCall = append(fake.listArgsForCall, struct{}{})
+	fake.listMutex.Unlock()
+	if fake.ListStub != nil {
+		return fake.ListStub()
+	} else {
+		return fake.listReturns.result1, fake.listReturns.result2
+	}
+}
+
+func (fake *FakePolicyStore) ListCallCount() int {
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	return len(fake.listArgsForCall)
+}
+
+func (fake *FakePolicyStore) ListReturns(result1 []receptor.AutoscalingPolicy, result2 error) {
+	fake.ListStub = nil
+	fake.listReturns = struct {
+		result1 []receptor.AutoscalingPolicy
+		result2 error
+	}{result1, result2}
+}
+
+var _ autoscaler.PolicyStore = new(FakePolicyStore)
@@ -0,0 +1,23 @@
+package autoscaler
+
+import "errors"
+
+var ErrMetricUnavailable = errors.New("metric unavailable")
+
+//go:generate counterfeiter -o fake_autoscaler/fake_metrics_source.go . MetricsSource
+
+// MetricsSource provides the metrics, other than crash_rate, on which
+// autoscaling rules are based. Rules whose metric is unavailable are skipped.
+type MetricsSource interface {
+	Metric(processGuid, name string) (float64, error)
+}
+
+type noMetrics struct{}
+
+// NoMetrics is a MetricsSource without any metrics, leaving crash_rate as the
+// only metric rules can be based on.
+var NoMetrics MetricsSource = noMetrics{}
+
+func (noMetrics) Metric(processGuid, name string) (float64, error) {
+	return 0, ErrMetricUnavailable
+}
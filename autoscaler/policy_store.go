@@ -0,0 +1,142 @@
+package autoscaler
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/cloudfoundry-incubator/bbs"
+	"github.com/cloudfoundry-incubator/bbs/models"
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/store"
+)
+
+const policiesPrefix = "autoscaling-policies/"
+
+var ErrPolicyNotFound = errors.New("desired LRP has no autoscaling policy")
+
+//go:generate counterfeiter -o fake_autoscaler/fake_policy_store.go . PolicyStore
+
+// PolicyStore keeps the autoscaling policy of each DesiredLRP in a store
+// shared by every receptor, apart from the DesiredLRP so that updating it
+// leaves the policy alone. A policy only applies to the DesiredLRP it was set
+// on: once that DesiredLRP is removed, its policy is ignored.
+type PolicyStore interface {
+	Get(processGuid string) (receptor.AutoscalingPolicy, error)
+	Set(policy receptor.AutoscalingPolicy) error
+	Remove(processGuid string) error
+	List() ([]receptor.AutoscalingPolicy, error)
+}
+
+type policyRecord struct {
+	Epoch  string                     `json:"epoch"`
+	Policy receptor.AutoscalingPolicy `json:"policy"`
+}
+
+type policyStore struct {
+	bbs   bbs.Client
+	store store.Store
+}
+
+func NewPolicyStore(bbs bbs.Client, store store.Store) PolicyStore {
+	return &policyStore{
+		bbs:   bbs,
+		store: store,
+	}
+}
+
+func (s *policyStore) Get(processGuid string) (receptor.AutoscalingPolicy, error) {
+	desiredLRP, err := s.bbs.DesiredLRPByProcessGuid(processGuid)
+	if err != nil {
+		return receptor.AutoscalingPolicy{}, err
+	}
+
+	record, err := s.get(processGuid)
+	if err != nil {
+		return receptor.AutoscalingPolicy{}, err
+	}
+	if record.Epoch != store.Epoch(desiredLRP) {
+		return receptor.AutoscalingPolicy{}, ErrPolicyNotFound
+	}
+	return record.Policy, nil
+}
+
+func (s *policyStore) Set(policy receptor.AutoscalingPolicy) error {
+	desiredLRP, err := s.bbs.DesiredLRPByProcessGuid(policy.ProcessGuid)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(policyRecord{
+		Epoch:  store.Epoch(desiredLRP),
+		Policy: policy,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.store.Put(policyKey(policy.ProcessGuid), payload)
+}
+
+func (s *policyStore) Remove(processGuid string) error {
+	_, err := s.Get(processGuid)
+	if err != nil {
+		return err
+	}
+
+	return s.store.Delete(policyKey(processGuid))
+}
+
+func (s *policyStore) List() ([]receptor.AutoscalingPolicy, error) {
+	entries, err := s.store.List(policiesPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := []receptor.AutoscalingPolicy{}
+	if len(entries) == 0 {
+		return policies, nil
+	}
+
+	desiredLRPs, err := s.bbs.DesiredLRPs(models.DesiredLRPFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	epochs := make(map[string]string, len(desiredLRPs))
+	for _, desiredLRP := range desiredLRPs {
+		epochs[desiredLRP.ProcessGuid] = store.Epoch(desiredLRP)
+	}
+
+	for _, entry := range entries {
+		var record policyRecord
+		err := json.Unmarshal(entry.Value, &record)
+		if err != nil {
+			continue
+		}
+
+		epoch, ok := epochs[record.Policy.ProcessGuid]
+		if !ok || epoch != record.Epoch {
+			continue
+		}
+		policies = append(policies, record.Policy)
+	}
+	return policies, nil
+}
+
+func (s *policyStore) get(processGuid string) (policyRecord, error) {
+	entry, err := s.store.Get(policyKey(processGuid))
+	if err == store.ErrNotFound {
+		return policyRecord{}, ErrPolicyNotFound
+	}
+	if err != nil {
+		return policyRecord{}, err
+	}
+
+	var record policyRecord
+	err = json.Unmarshal(entry.Value, &record)
+	return record, err
+}
+
+func policyKey(processGuid string) string {
+	return policiesPrefix + processGuid
+}
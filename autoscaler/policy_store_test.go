@@ -0,0 +1,168 @@
+package autoscaler_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/bbs/fake_bbs"
+	"github.com/cloudfoundry-incubator/bbs/models"
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/autoscaler"
+	"github.com/cloudfoundry-incubator/receptor/store"
+	"github.com/cloudfoundry-incubator/receptor/store/fake_store"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PolicyStore", func() {
+	var (
+		fakeBBS   *fake_bbs.FakeClient
+		fakeStore *fake_store.FakeStore
+		policies  autoscaler.PolicyStore
+
+		policy     receptor.AutoscalingPolicy
+		desiredLRP *models.DesiredLRP
+		recordJSON string
+	)
+
+	BeforeEach(func() {
+		fakeBBS = new(fake_bbs.FakeClient)
+		fakeStore = new(fake_store.FakeStore)
+		policies = autoscaler.NewPolicyStore(fakeBBS, fakeStore)
+
+		policy = receptor.AutoscalingPolicy{
+			ProcessGuid:  "process-guid",
+			MinInstances: 1,
+			MaxInstances: 5,
+			Rules: []receptor.AutoscalingRule{{
+				Metric:     receptor.AutoscalingMetricCrashRate,
+				Comparison: receptor.AutoscalingComparisonAbove,
+				Threshold:  0.5,
+				Adjustment: -1,
+			}},
+		}
+		recordJSON = `{
+			"epoch": "epoch",
+			"policy": {
+				"process_guid": "process-guid",
+				"min_instances": 1,
+				"max_instances": 5,
+				"rules": [{"metric": "crash_rate", "comparison": "above", "threshold": 0.5, "adjustment": -1}]
+			}
+		}`
+
+		desiredLRP = &models.DesiredLRP{
+			ProcessGuid:     "process-guid",
+			ModificationTag: &models.ModificationTag{Epoch: "epoch"},
+		}
+		fakeBBS.DesiredLRPByProcessGuidReturns(desiredLRP, nil)
+		fakeStore.GetReturns(store.Entry{Key: "autoscaling-policies/process-guid", Value: []byte(recordJSON)}, nil)
+	})
+
+	Describe("Set", func() {
+		It("stores the policy along with the epoch of the desired LRP", func() {
+			err := policies.Set(policy)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeStore.PutCallCount()).To(Equal(1))
+			key, value := fakeStore.PutArgsForCall(0)
+			Expect(key).To(Equal("autoscaling-policies/process-guid"))
+			Expect(value).To(MatchJSON(recordJSON))
+		})
+
+		It("leaves the desired LRP alone", func() {
+			err := policies.Set(policy)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeBBS.UpdateDesiredLRPCallCount()).To(Equal(0))
+		})
+
+		Context("when the desired LRP does not exist", func() {
+			BeforeEach(func() {
+				fakeBBS.DesiredLRPByProcessGuidReturns(nil, models.ErrResourceNotFound)
+			})
+
+			It("returns the error", func() {
+				Expect(policies.Set(policy)).To(Equal(models.ErrResourceNotFound))
+				Expect(fakeStore.PutCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("Get", func() {
+		It("returns the stored policy", func() {
+			Expect(policies.Get("process-guid")).To(Equal(policy))
+			Expect(fakeStore.GetArgsForCall(0)).To(Equal("autoscaling-policies/process-guid"))
+		})
+
+		It("returns ErrPolicyNotFound when the desired LRP has no policy", func() {
+			fakeStore.GetReturns(store.Entry{}, store.ErrNotFound)
+
+			_, err := policies.Get("process-guid")
+			Expect(err).To(Equal(autoscaler.ErrPolicyNotFound))
+		})
+
+		It("returns ErrPolicyNotFound when the policy was set on a removed desired LRP", func() {
+			desiredLRP.ModificationTag.Epoch = "new-epoch"
+
+			_, err := policies.Get("process-guid")
+			Expect(err).To(Equal(autoscaler.ErrPolicyNotFound))
+		})
+
+		It("returns the error when the desired LRP does not exist", func() {
+			fakeBBS.DesiredLRPByProcessGuidReturns(nil, models.ErrResourceNotFound)
+
+			_, err := policies.Get("process-guid")
+			Expect(err).To(Equal(models.ErrResourceNotFound))
+		})
+	})
+
+	Describe("Remove", func() {
+		It("deletes the stored policy", func() {
+			err := policies.Remove("process-guid")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeStore.DeleteCallCount()).To(Equal(1))
+			Expect(fakeStore.DeleteArgsForCall(0)).To(Equal("autoscaling-policies/process-guid"))
+		})
+
+		It("returns ErrPolicyNotFound when the desired LRP has no policy", func() {
+			fakeStore.GetReturns(store.Entry{}, store.ErrNotFound)
+
+			Expect(policies.Remove("process-guid")).To(Equal(autoscaler.ErrPolicyNotFound))
+			Expect(fakeStore.DeleteCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("List", func() {
+		BeforeEach(func() {
+			fakeStore.ListReturns([]store.Entry{
+				{Key: "autoscaling-policies/process-guid", Value: []byte(recordJSON)},
+				{Key: "autoscaling-policies/removed-guid", Value: []byte(`{"epoch":"epoch","policy":{"process_guid":"removed-guid"}}`)},
+				{Key: "autoscaling-policies/recreated-guid", Value: []byte(`{"epoch":"old-epoch","policy":{"process_guid":"recreated-guid"}}`)},
+			}, nil)
+			fakeBBS.DesiredLRPsReturns([]*models.DesiredLRP{
+				desiredLRP,
+				{ProcessGuid: "recreated-guid", ModificationTag: &models.ModificationTag{Epoch: "epoch"}},
+			}, nil)
+		})
+
+		It("returns the policies of the existing desired LRPs", func() {
+			Expect(policies.List()).To(Equal([]receptor.AutoscalingPolicy{policy}))
+			Expect(fakeStore.ListArgsForCall(0)).To(Equal("autoscaling-policies/"))
+		})
+
+		It("returns the error when the desired LRPs can not be fetched", func() {
+			fakeBBS.DesiredLRPsReturns(nil, errors.New("oops"))
+
+			_, err := policies.List()
+			Expect(err).To(MatchError("oops"))
+		})
+
+		It("returns the error when the policies can not be listed", func() {
+			fakeStore.ListReturns(nil, errors.New("oops"))
+
+			_, err := policies.List()
+			Expect(err).To(MatchError("oops"))
+		})
+	})
+})
@@ -0,0 +1,329 @@
+package callback
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/cloudfoundry-incubator/bbs"
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/event"
+	"github.com/cloudfoundry-incubator/receptor/serialization"
+	"github.com/pivotal-golang/clock"
+	"github.com/pivotal-golang/lager"
+	"github.com/tedsuo/ifrit"
+)
+
+const (
+	DefaultInitialBackoff = time.Second
+	DefaultMaxBackoff     = time.Minute
+	DefaultMaxAttempts    = 10
+
+	pruneInterval = time.Hour
+)
+
+type Config struct {
+	// Secret is the HMAC key used to sign callback bodies.
+	Secret string
+
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	MaxAttempts    int
+}
+
+type dispatcher struct {
+	bbs        bbs.Client
+	hub        event.Hub
+	statuses   StatusStore
+	httpClient *http.Client
+	clock      clock.Clock
+	config     Config
+	logger     lager.Logger
+
+	inFlightLock sync.Mutex
+	inFlight     map[string]struct{}
+}
+
+// NewDispatcher returns a runner which watches the hub for tasks that complete
+// with a completion callback URL, and POSTs the signed TaskResponse to it. A
+// failed delivery is retried with exponential backoff, up to MaxAttempts
+// times, and the outcome is recorded in the status store.
+//
+// When it starts, and whenever events may have been missed, the dispatcher
+// also delivers the callbacks of the completed tasks in the BBS which have
+// not been delivered yet. A delivery cut short by the dispatcher stopping is
+// attempted again from the start. Only one receptor may run a dispatcher at a
+// time, or callbacks are delivered once by each of them.
+func NewDispatcher(bbs bbs.Client, hub event.Hub, statuses StatusStore, httpClient *http.Client, clock clock.Clock, config Config, logger lager.Logger) ifrit.Runner {
+	return &dispatcher{
+		bbs:        bbs,
+		hub:        hub,
+		statuses:   statuses,
+		httpClient: httpClient,
+		clock:      clock,
+		config:     config,
+		logger:     logger.Session("callback-dispatcher"),
+		inFlight:   make(map[string]struct{}),
+	}
+}
+
+func (d *dispatcher) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	stop := make(chan struct{})
+	deliveries := &sync.WaitGroup{}
+	defer func() {
+		close(stop)
+		deliveries.Wait()
+	}()
+
+	source, err := d.hub.Subscribe()
+	if err != nil {
+		return err
+	}
+
+	close(ready)
+
+	prunes := d.clock.NewTicker(pruneInterval)
+	defer prunes.Stop()
+
+	d.resync(stop, deliveries)
+
+	var lastEventID uint64
+	for {
+		lastEventID, err = d.dispatch(source, lastEventID, signals, prunes.C(), stop, deliveries)
+		if err == nil {
+			return nil
+		}
+		d.logger.Error("lost-event-stream", err)
+
+		if lastEventID == 0 {
+			source, err = d.hub.Subscribe()
+			if err == nil {
+				d.resync(stop, deliveries)
+			}
+		} else {
+			source, err = d.hub.SubscribeSince(lastEventID)
+		}
+		if err != nil {
+			d.logger.Error("failed-to-resubscribe", err)
+			return err
+		}
+	}
+}
+
+// dispatch delivers callbacks for the events from source until the runner is
+// signalled or the source fails. It returns the ID of the last event it
+// received, along with the error of the source if it failed.
+func (d *dispatcher) dispatch(
+	source event.Source,
+	lastEventID uint64,
+	signals <-chan os.Signal,
+	prunes <-chan time.Time,
+	stop <-chan struct{},
+	deliveries *sync.WaitGroup,
+) (uint64, error) {
+	defer source.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+
+	messages := make(chan event.Message)
+	errs := make(chan error, 1)
+	go func() {
+		for {
+			message, err := source.Next()
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			select {
+			case messages <- message:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-signals:
+			return lastEventID, nil
+
+		case err := <-errs:
+			return lastEventID, err
+
+		case <-prunes:
+			err := d.statuses.Prune()
+			if err != nil {
+				d.logger.Error("failed-to-prune-statuses", err)
+			}
+
+		case message := <-messages:
+			lastEventID = message.ID
+
+			switch e := message.Event.(type) {
+			case receptor.ResyncRequiredEvent:
+				d.resync(stop, deliveries)
+			case receptor.TaskChangedEvent:
+				if completedWithCallback(e) {
+					d.start(e.After, stop, deliveries)
+				}
+			}
+		}
+	}
+}
+
+func completedWithCallback(e receptor.TaskChangedEvent) bool {
+	return e.After.CompletionCallbackURL != "" &&
+		e.After.State == receptor.TaskStateCompleted &&
+		e.Before.State != receptor.TaskStateCompleted
+}
+
+// resync delivers the callbacks of the completed tasks in the BBS which have
+// not been delivered yet, in case their completion was missed.
+func (d *dispatcher) resync(stop <-chan struct{}, deliveries *sync.WaitGroup) {
+	logger := d.logger.Session("resync")
+
+	tasks, err := d.bbs.Tasks()
+	if err != nil {
+		logger.Error("failed-to-fetch-tasks", err)
+		return
+	}
+
+	for _, task := range tasks {
+		response := serialization.TaskToResponse(task)
+		if response.CompletionCallbackURL == "" || response.State != receptor.TaskStateCompleted {
+			continue
+		}
+		d.start(response, stop, deliveries)
+	}
+}
+
+// start delivers the callback of task in the background, unless it is being
+// delivered already or its delivery has finished.
+func (d *dispatcher) start(task receptor.TaskResponse, stop <-chan struct{}, deliveries *sync.WaitGroup) {
+	if !d.claim(task.TaskGuid) {
+		return
+	}
+
+	status, err := d.statuses.Get(task.TaskGuid)
+	if err == nil && status.State != receptor.CallbackDeliveryPending {
+		d.release(task.TaskGuid)
+		return
+	}
+	if err != nil && err != ErrStatusNotFound {
+		d.logger.Error("failed-to-fetch-status", err, lager.Data{"task-guid": task.TaskGuid})
+	}
+
+	deliveries.Add(1)
+	go func() {
+		defer deliveries.Done()
+		defer d.release(task.TaskGuid)
+		d.deliver(task, stop)
+	}()
+}
+
+func (d *dispatcher) claim(taskGuid string) bool {
+	d.inFlightLock.Lock()
+	defer d.inFlightLock.Unlock()
+
+	if _, ok := d.inFlight[taskGuid]; ok {
+		return false
+	}
+	d.inFlight[taskGuid] = struct{}{}
+	return true
+}
+
+func (d *dispatcher) release(taskGuid string) {
+	d.inFlightLock.Lock()
+	defer d.inFlightLock.Unlock()
+
+	delete(d.inFlight, taskGuid)
+}
+
+func (d *dispatcher) deliver(task receptor.TaskResponse, stop <-chan struct{}) {
+	logger := d.logger.Session("deliver", lager.Data{"task-guid": task.TaskGuid})
+
+	body, err := json.Marshal(task)
+	if err != nil {
+		logger.Error("failed-to-marshal-task", err)
+		return
+	}
+
+	status := receptor.CallbackDeliveryResponse{
+		TaskGuid: task.TaskGuid,
+		URL:      task.CompletionCallbackURL,
+		State:    receptor.CallbackDeliveryPending,
+	}
+	d.setStatus(logger, status)
+
+	backoff := d.config.InitialBackoff
+	for {
+		err := d.post(task.CompletionCallbackURL, body)
+
+		status.Attempts++
+		status.LastAttemptAt = d.clock.Now().UnixNano()
+		if err == nil {
+			logger.Info("delivered", lager.Data{"attempts": status.Attempts})
+			status.State = receptor.CallbackDeliveryDelivered
+			status.LastError = ""
+			d.setStatus(logger, status)
+			return
+		}
+
+		logger.Error("failed-to-deliver", err, lager.Data{"attempts": status.Attempts})
+		status.LastError = err.Error()
+		if status.Attempts >= d.config.MaxAttempts {
+			status.State = receptor.CallbackDeliveryFailed
+			d.setStatus(logger, status)
+			return
+		}
+		d.setStatus(logger, status)
+
+		timer := d.clock.NewTimer(backoff)
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-timer.C():
+		}
+
+		backoff *= 2
+		if backoff > d.config.MaxBackoff {
+			backoff = d.config.MaxBackoff
+		}
+	}
+}
+
+func (d *dispatcher) setStatus(logger lager.Logger, status receptor.CallbackDeliveryResponse) {
+	err := d.statuses.Set(status)
+	if err != nil {
+		logger.Error("failed-to-record-status", err, lager.Data{"state": status.State})
+	}
+}
+
+func (d *dispatcher) post(url string, body []byte) error {
+	request, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", receptor.JSONContentType)
+	request.Header.Set(receptor.CallbackSignatureHeader, receptor.CallbackSignature(d.config.Secret, body))
+
+	response, err := d.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+
+	if response.StatusCode > 299 {
+		return fmt.Errorf("callback responded with status code %d", response.StatusCode)
+	}
+
+	return nil
+}
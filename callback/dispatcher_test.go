@@ -0,0 +1,306 @@
+package callback_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cloudfoundry-incubator/bbs/fake_bbs"
+	"github.com/cloudfoundry-incubator/bbs/models"
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/callback"
+	"github.com/cloudfoundry-incubator/receptor/callback/fake_callback"
+	"github.com/cloudfoundry-incubator/receptor/event"
+	"github.com/cloudfoundry-incubator/receptor/event/eventfakes"
+	"github.com/onsi/gomega/ghttp"
+	"github.com/pivotal-golang/clock/fakeclock"
+	"github.com/pivotal-golang/lager/lagertest"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/ginkgomon"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Dispatcher", func() {
+	var (
+		fakeBBS        *fake_bbs.FakeClient
+		hub            event.Hub
+		statuses       *fake_callback.FakeStatusStore
+		fakeClock      *fakeclock.FakeClock
+		callbackServer *ghttp.Server
+		config         callback.Config
+
+		runningTask, completedTask receptor.TaskResponse
+
+		process ifrit.Process
+	)
+
+	BeforeEach(func() {
+		fakeBBS = new(fake_bbs.FakeClient)
+		hub = event.NewHub(10)
+		statuses = newStatusStore()
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+		callbackServer = ghttp.NewServer()
+
+		config = callback.Config{
+			Secret:         "some-secret",
+			InitialBackoff: time.Second,
+			MaxBackoff:     time.Minute,
+			MaxAttempts:    2,
+		}
+
+		runningTask = receptor.TaskResponse{
+			TaskGuid:              "some-task-guid",
+			CompletionCallbackURL: callbackServer.URL() + "/callback",
+			State:                 receptor.TaskStateRunning,
+		}
+		completedTask = runningTask
+		completedTask.State = receptor.TaskStateCompleted
+		completedTask.Result = "some-result"
+	})
+
+	JustBeforeEach(func() {
+		process = ginkgomon.Invoke(callback.NewDispatcher(fakeBBS, hub, statuses, http.DefaultClient, fakeClock, config, lagertest.NewTestLogger("test")))
+	})
+
+	AfterEach(func() {
+		ginkgomon.Interrupt(process)
+		callbackServer.Close()
+	})
+
+	getStatus := func() receptor.CallbackDeliveryResponse {
+		status, _ := statuses.Get("some-task-guid")
+		return status
+	}
+
+	Context("when a task with a callback url completes", func() {
+		Context("and the callback succeeds", func() {
+			BeforeEach(func() {
+				callbackServer.AppendHandlers(ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/callback"),
+					func(w http.ResponseWriter, req *http.Request) {
+						body, err := ioutil.ReadAll(req.Body)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(req.Header.Get(receptor.CallbackSignatureHeader)).To(Equal(receptor.CallbackSignature("some-secret", body)))
+
+						var task receptor.TaskResponse
+						err = json.Unmarshal(body, &task)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(task).To(Equal(completedTask))
+					},
+				))
+			})
+
+			It("posts the signed task to the callback url and records the delivery", func() {
+				hub.Emit(receptor.NewTaskChangedEvent(runningTask, completedTask))
+
+				Eventually(func() string { return getStatus().State }).Should(Equal(receptor.CallbackDeliveryDelivered))
+				Expect(getStatus().Attempts).To(Equal(1))
+				Expect(callbackServer.ReceivedRequests()).To(HaveLen(1))
+			})
+		})
+
+		Context("and the callback fails", func() {
+			BeforeEach(func() {
+				callbackServer.AppendHandlers(
+					ghttp.RespondWith(http.StatusServiceUnavailable, nil),
+					ghttp.RespondWith(http.StatusOK, nil),
+				)
+			})
+
+			It("retries after backing off", func() {
+				hub.Emit(receptor.NewTaskChangedEvent(runningTask, completedTask))
+
+				Eventually(func() int { return getStatus().Attempts }).Should(Equal(1))
+				Expect(getStatus().State).To(Equal(receptor.CallbackDeliveryPending))
+				Expect(getStatus().LastError).To(ContainSubstring("503"))
+
+				Eventually(func() string {
+					fakeClock.Increment(time.Second)
+					return getStatus().State
+				}).Should(Equal(receptor.CallbackDeliveryDelivered))
+				Expect(getStatus().Attempts).To(Equal(2))
+			})
+		})
+
+		Context("and every attempt fails", func() {
+			BeforeEach(func() {
+				callbackServer.AppendHandlers(
+					ghttp.RespondWith(http.StatusServiceUnavailable, nil),
+					ghttp.RespondWith(http.StatusServiceUnavailable, nil),
+				)
+			})
+
+			It("gives up after the maximum number of attempts", func() {
+				hub.Emit(receptor.NewTaskChangedEvent(runningTask, completedTask))
+
+				Eventually(func() string {
+					fakeClock.Increment(time.Second)
+					return getStatus().State
+				}).Should(Equal(receptor.CallbackDeliveryFailed))
+				Expect(getStatus().Attempts).To(Equal(2))
+			})
+		})
+	})
+
+	Context("when a task without a callback url completes", func() {
+		BeforeEach(func() {
+			runningTask.CompletionCallbackURL = ""
+			completedTask.CompletionCallbackURL = ""
+		})
+
+		It("does not record a delivery", func() {
+			hub.Emit(receptor.NewTaskChangedEvent(runningTask, completedTask))
+
+			Consistently(func() error {
+				_, err := statuses.Get("some-task-guid")
+				return err
+			}).Should(Equal(callback.ErrStatusNotFound))
+		})
+	})
+
+	Context("when the BBS holds completed tasks with a callback url", func() {
+		BeforeEach(func() {
+			fakeBBS.TasksReturns([]*models.Task{
+				{
+					TaskGuid:       "some-task-guid",
+					State:          models.Task_Completed,
+					TaskDefinition: &models.TaskDefinition{CompletionCallbackUrl: callbackServer.URL() + "/callback"},
+				},
+				{
+					TaskGuid:       "delivered-task-guid",
+					State:          models.Task_Completed,
+					TaskDefinition: &models.TaskDefinition{CompletionCallbackUrl: callbackServer.URL() + "/callback"},
+				},
+				{
+					TaskGuid:       "running-task-guid",
+					State:          models.Task_Running,
+					TaskDefinition: &models.TaskDefinition{CompletionCallbackUrl: callbackServer.URL() + "/callback"},
+				},
+			}, nil)
+
+			statuses.Set(receptor.CallbackDeliveryResponse{
+				TaskGuid: "delivered-task-guid",
+				State:    receptor.CallbackDeliveryDelivered,
+			})
+
+			callbackServer.AppendHandlers(
+				ghttp.RespondWith(http.StatusOK, nil),
+				ghttp.RespondWith(http.StatusOK, nil),
+			)
+		})
+
+		It("delivers the callbacks which were not delivered yet when it starts", func() {
+			Eventually(func() string { return getStatus().State }).Should(Equal(receptor.CallbackDeliveryDelivered))
+			Consistently(callbackServer.ReceivedRequests).Should(HaveLen(1))
+		})
+
+		It("delivers them again when a resync is required", func() {
+			Eventually(fakeBBS.TasksCallCount).Should(Equal(1))
+			Eventually(func() string { return getStatus().State }).Should(Equal(receptor.CallbackDeliveryDelivered))
+
+			statuses.Set(receptor.CallbackDeliveryResponse{
+				TaskGuid: "some-task-guid",
+				State:    receptor.CallbackDeliveryPending,
+			})
+			hub.Emit(receptor.NewResyncRequiredEvent())
+
+			Eventually(fakeBBS.TasksCallCount).Should(Equal(2))
+			Eventually(callbackServer.ReceivedRequests).Should(HaveLen(2))
+		})
+	})
+
+	Context("when the event stream fails", func() {
+		var (
+			fakeHub      *eventfakes.FakeHub
+			failedSource *eventfakes.FakeSource
+		)
+
+		BeforeEach(func() {
+			fakeHub = new(eventfakes.FakeHub)
+			hub = fakeHub
+
+			failedSource = new(eventfakes.FakeSource)
+			calls := 0
+			failedSource.NextStub = func() (event.Message, error) {
+				calls++
+				if calls == 1 {
+					return event.Message{ID: 5, Event: receptor.NewTaskChangedEvent(runningTask, runningTask)}, nil
+				}
+				return event.Message{}, errors.New("slow consumer")
+			}
+			fakeHub.SubscribeReturns(failedSource, nil)
+		})
+
+		Context("and resubscribing fails", func() {
+			BeforeEach(func() {
+				fakeHub.SubscribeSinceReturns(nil, errors.New("hub closed"))
+			})
+
+			It("resubscribes after the last event it received, and exits with the error", func() {
+				Eventually(process.Wait()).Should(Receive(MatchError("hub closed")))
+
+				Expect(fakeHub.SubscribeSinceCallCount()).To(Equal(1))
+				Expect(fakeHub.SubscribeSinceArgsForCall(0)).To(BeEquivalentTo(5))
+			})
+		})
+
+		Context("and resubscribing succeeds", func() {
+			BeforeEach(func() {
+				callbackServer.AppendHandlers(ghttp.RespondWith(http.StatusOK, nil))
+
+				replay := new(eventfakes.FakeSource)
+				closed := make(chan struct{})
+				replay.CloseStub = func() error {
+					close(closed)
+					return nil
+				}
+				replayed := false
+				replay.NextStub = func() (event.Message, error) {
+					if !replayed {
+						replayed = true
+						return event.Message{ID: 6, Event: receptor.NewTaskChangedEvent(runningTask, completedTask)}, nil
+					}
+					<-closed
+					return event.Message{}, errors.New("closed")
+				}
+				fakeHub.SubscribeSinceReturns(replay, nil)
+			})
+
+			It("delivers the callbacks of the replayed events", func() {
+				Eventually(func() string { return getStatus().State }).Should(Equal(receptor.CallbackDeliveryDelivered))
+			})
+		})
+	})
+})
+
+// newStatusStore returns a fake status store which keeps statuses in memory.
+func newStatusStore() *fake_callback.FakeStatusStore {
+	lock := &sync.Mutex{}
+	stored := map[string]receptor.CallbackDeliveryResponse{}
+
+	statuses := new(fake_callback.FakeStatusStore)
+	statuses.GetStub = func(taskGuid string) (receptor.CallbackDeliveryResponse, error) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		status, ok := stored[taskGuid]
+		if !ok {
+			return receptor.CallbackDeliveryResponse{}, callback.ErrStatusNotFound
+		}
+		return status, nil
+	}
+	statuses.SetStub = func(status receptor.CallbackDeliveryResponse) error {
+		lock.Lock()
+		defer lock.Unlock()
+
+		stored[status.TaskGuid] = status
+		return nil
+	}
+	return statuses
+}
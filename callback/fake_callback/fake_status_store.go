@@ -0,0 +1,126 @@
+// This file was generated by counterfeiter
+package fake_callback
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/callback"
+)
+
+type FakeStatusStore struct {
+	GetStub        func(taskGuid string) (receptor.CallbackDeliveryResponse, error)
+	getMutex       sync.RWMutex
+	getArgsForCall []struct {
+		taskGuid string
+	}
+	getReturns struct {
+		result1 receptor.CallbackDeliveryResponse
+		result2 error
+	}
+	SetStub        func(status receptor.CallbackDeliveryResponse) error
+	setMutex       sync.RWMutex
+	setArgsForCall []struct {
+		status receptor.CallbackDeliveryResponse
+	}
+	setReturns struct {
+		result1 error
+	}
+	PruneStub        func() error
+	pruneMutex       sync.RWMutex
+	pruneArgsForCall []struct{}
+	pruneReturns     struct {
+		result1 error
+	}
+}
+
+func (fake *FakeStatusStore) Get(taskGuid string) (receptor.CallbackDeliveryResponse, error) {
+	fake.getMutex.Lock()
+	fake.getArgsForCall = append(fake.getArgsForCall, struct {
+		taskGuid string
+	}{taskGuid})
+	fake.getMutex.Unlock()
+	if fake.GetStub != nil {
+		return fake.GetStub(taskGuid)
+	} else {
+		return fake.getReturns.result1, fake.getReturns.result2
+	}
+}
+
+func (fake *FakeStatusStore) GetCallCount() int {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	return len(fake.getArgsForCall)
+}
+
+func (fake *FakeStatusStore) GetArgsForCall(i int) string {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	return fake.getArgsForCall[i].taskGuid
+}
+
+func (fake *FakeStatusStore) GetReturns(result1 receptor.CallbackDeliveryResponse, result2 error) {
+	fake.GetStub = nil
+	fake.getReturns = struct {
+		result1 receptor.CallbackDeliveryResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStatusStore) Set(status receptor.CallbackDeliveryResponse) error {
+	fake.setMutex.Lock()
+	fake.setArgsForCall = append(fake.setArgsForCall, struct {
+		status receptor.CallbackDeliveryResponse
+	}{status})
+	fake.setMutex.Unlock()
+	if fake.SetStub != nil {
+		return fake.SetStub(status)
+	} else {
+		return fake.setReturns.result1
+	}
+}
+
+func (fake *FakeStatusStore) SetCallCount() int {
+	fake.setMutex.RLock()
+	defer fake.setMutex.RUnlock()
+	return len(fake.setArgsForCall)
+}
+
+func (fake *FakeStatusStore) SetArgsForCall(i int) receptor.CallbackDeliveryResponse {
+	fake.setMutex.RLock()
+	defer fake.setMutex.RUnlock()
+	return fake.setArgsForCall[i].status
+}
+
+func (fake *FakeStatusStore) SetReturns(result1 error) {
+	fake.SetStub = nil
+	fake.setReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStatusStore) Prune() error {
+	fake.pruneMutex.Lock()
+	fake.pruneArgsForCall = append(fake.pruneArgsForCall, struct{}{})
+	fake.pruneMutex.Unlock()
+	if fake.PruneStub != nil {
+		return fake.PruneStub()
+	} else {
+		return fake.pruneReturns.result1
+	}
+}
+
+func (fake *FakeStatusStore) PruneCallCount() int {
+	fake.pruneMutex.RLock()
+	defer fake.pruneMutex.RUnlock()
+	return len(fake.pruneArgsForCall)
+}
+
+func (fake *FakeStatusStore) PruneReturns(result1 error) {
+	fake.PruneStub = nil
+	fake.pruneReturns = struct {
+		result1 error
+	}{result1}
+}
+
+var _ callback.StatusStore = new(FakeStatusStore)
@@ -0,0 +1,110 @@
+package callback
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/store"
+	"github.com/pivotal-golang/clock"
+)
+
+const (
+	DefaultStatusRetention = 24 * time.Hour
+
+	statusesPrefix = "callback-deliveries/"
+)
+
+var ErrStatusNotFound = errors.New("task has no callback delivery status")
+
+//go:generate counterfeiter -o fake_callback/fake_status_store.go . StatusStore
+
+// StatusStore records the callback delivery status of completed tasks in a
+// store shared by every receptor, so that any of them can report it. A status
+// is kept for the retention period after it was last set.
+type StatusStore interface {
+	Get(taskGuid string) (receptor.CallbackDeliveryResponse, error)
+	Set(status receptor.CallbackDeliveryResponse) error
+
+	// Prune deletes the statuses which have outlived the retention period.
+	Prune() error
+}
+
+type statusRecord struct {
+	Status receptor.CallbackDeliveryResponse `json:"status"`
+	SetAt  int64                             `json:"set_at"`
+}
+
+type statusStore struct {
+	store     store.Store
+	clock     clock.Clock
+	retention time.Duration
+}
+
+func NewStatusStore(store store.Store, clock clock.Clock, retention time.Duration) StatusStore {
+	return &statusStore{
+		store:     store,
+		clock:     clock,
+		retention: retention,
+	}
+}
+
+func (s *statusStore) Get(taskGuid string) (receptor.CallbackDeliveryResponse, error) {
+	entry, err := s.store.Get(statusesPrefix + taskGuid)
+	if err == store.ErrNotFound {
+		return receptor.CallbackDeliveryResponse{}, ErrStatusNotFound
+	}
+	if err != nil {
+		return receptor.CallbackDeliveryResponse{}, err
+	}
+
+	var record statusRecord
+	err = json.Unmarshal(entry.Value, &record)
+	if err != nil {
+		return receptor.CallbackDeliveryResponse{}, err
+	}
+
+	if s.expired(record) {
+		return receptor.CallbackDeliveryResponse{}, ErrStatusNotFound
+	}
+	return record.Status, nil
+}
+
+func (s *statusStore) Set(status receptor.CallbackDeliveryResponse) error {
+	payload, err := json.Marshal(statusRecord{
+		Status: status,
+		SetAt:  s.clock.Now().UnixNano(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.store.Put(statusesPrefix+status.TaskGuid, payload)
+}
+
+func (s *statusStore) Prune() error {
+	entries, err := s.store.List(statusesPrefix)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		var record statusRecord
+		err := json.Unmarshal(entry.Value, &record)
+		if err == nil && !s.expired(record) {
+			continue
+		}
+
+		// a status set since it was listed is kept
+		err = s.store.CompareAndDelete(entry.Key, entry.Index)
+		if err != nil && err != store.ErrConflict {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *statusStore) expired(record statusRecord) bool {
+	return s.clock.Now().Sub(time.Unix(0, record.SetAt)) >= s.retention
+}
@@ -0,0 +1,177 @@
+package callback_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/callback"
+	"github.com/cloudfoundry-incubator/receptor/store"
+	"github.com/cloudfoundry-incubator/receptor/store/fake_store"
+	"github.com/pivotal-golang/clock/fakeclock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("StatusStore", func() {
+	var (
+		fakeStore *fake_store.FakeStore
+		fakeClock *fakeclock.FakeClock
+		statuses  callback.StatusStore
+
+		status     receptor.CallbackDeliveryResponse
+		recordJSON string
+	)
+
+	BeforeEach(func() {
+		fakeStore = new(fake_store.FakeStore)
+		fakeClock = fakeclock.NewFakeClock(time.Unix(0, 1000))
+		statuses = callback.NewStatusStore(fakeStore, fakeClock, time.Hour)
+
+		status = receptor.CallbackDeliveryResponse{
+			TaskGuid: "task-guid",
+			URL:      "http://example.com/callback",
+			State:    receptor.CallbackDeliveryDelivered,
+			Attempts: 1,
+		}
+		recordJSON = `{
+			"status": {
+				"task_guid": "task-guid",
+				"url": "http://example.com/callback",
+				"state": "DELIVERED",
+				"attempts": 1,
+				"last_attempt_at": 0
+			},
+			"set_at": 1000
+		}`
+	})
+
+	Describe("Set", func() {
+		It("stores the status along with when it was set", func() {
+			err := statuses.Set(status)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeStore.PutCallCount()).To(Equal(1))
+			key, value := fakeStore.PutArgsForCall(0)
+			Expect(key).To(Equal("callback-deliveries/task-guid"))
+			Expect(value).To(MatchJSON(recordJSON))
+		})
+
+		Context("when the store fails", func() {
+			BeforeEach(func() {
+				fakeStore.PutReturns(errors.New("oops"))
+			})
+
+			It("returns the error", func() {
+				err := statuses.Set(status)
+				Expect(err).To(MatchError("oops"))
+			})
+		})
+	})
+
+	Describe("Get", func() {
+		BeforeEach(func() {
+			fakeStore.GetReturns(store.Entry{Key: "callback-deliveries/task-guid", Value: []byte(recordJSON)}, nil)
+		})
+
+		It("returns the stored status", func() {
+			actual, err := statuses.Get("task-guid")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actual).To(Equal(status))
+
+			Expect(fakeStore.GetArgsForCall(0)).To(Equal("callback-deliveries/task-guid"))
+		})
+
+		Context("when the status has outlived the retention period", func() {
+			BeforeEach(func() {
+				fakeClock.Increment(time.Hour)
+			})
+
+			It("returns ErrStatusNotFound", func() {
+				_, err := statuses.Get("task-guid")
+				Expect(err).To(Equal(callback.ErrStatusNotFound))
+			})
+		})
+
+		Context("when there is no status", func() {
+			BeforeEach(func() {
+				fakeStore.GetReturns(store.Entry{}, store.ErrNotFound)
+			})
+
+			It("returns ErrStatusNotFound", func() {
+				_, err := statuses.Get("task-guid")
+				Expect(err).To(Equal(callback.ErrStatusNotFound))
+			})
+		})
+
+		Context("when the store fails", func() {
+			BeforeEach(func() {
+				fakeStore.GetReturns(store.Entry{}, errors.New("oops"))
+			})
+
+			It("returns the error", func() {
+				_, err := statuses.Get("task-guid")
+				Expect(err).To(MatchError("oops"))
+			})
+		})
+	})
+
+	Describe("Prune", func() {
+		BeforeEach(func() {
+			fakeStore.ListReturns([]store.Entry{
+				{Key: "callback-deliveries/task-guid", Value: []byte(recordJSON), Index: 3},
+				{Key: "callback-deliveries/invalid", Value: []byte("{"), Index: 4},
+			}, nil)
+		})
+
+		It("deletes invalid statuses", func() {
+			err := statuses.Prune()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeStore.ListArgsForCall(0)).To(Equal("callback-deliveries/"))
+			Expect(fakeStore.CompareAndDeleteCallCount()).To(Equal(1))
+			key, index := fakeStore.CompareAndDeleteArgsForCall(0)
+			Expect(key).To(Equal("callback-deliveries/invalid"))
+			Expect(index).To(BeEquivalentTo(4))
+		})
+
+		Context("when a status has outlived the retention period", func() {
+			BeforeEach(func() {
+				fakeClock.Increment(time.Hour)
+			})
+
+			It("deletes it at the index it was listed at", func() {
+				err := statuses.Prune()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeStore.CompareAndDeleteCallCount()).To(Equal(2))
+				key, index := fakeStore.CompareAndDeleteArgsForCall(0)
+				Expect(key).To(Equal("callback-deliveries/task-guid"))
+				Expect(index).To(BeEquivalentTo(3))
+			})
+		})
+
+		Context("when a status was set since it was listed", func() {
+			BeforeEach(func() {
+				fakeStore.CompareAndDeleteReturns(store.ErrConflict)
+			})
+
+			It("keeps it", func() {
+				err := statuses.Prune()
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		Context("when deleting fails", func() {
+			BeforeEach(func() {
+				fakeStore.CompareAndDeleteReturns(errors.New("oops"))
+			})
+
+			It("returns the error", func() {
+				err := statuses.Prune()
+				Expect(err).To(MatchError("oops"))
+			})
+		})
+	})
+})
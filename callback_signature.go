@@ -0,0 +1,19 @@
+package receptor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+const CallbackSignatureHeader = "X-Receptor-Signature"
+
+// CallbackSignature returns the value of the CallbackSignatureHeader sent
+// along with a completion callback body. Receivers sharing the secret can
+// recompute it, comparing with hmac.Equal, to verify that a callback came from
+// the receptor.
+func CallbackSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
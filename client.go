@@ -5,10 +5,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"mime"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cloudfoundry-incubator/cf_http"
@@ -34,26 +36,63 @@ const (
 
 type Client interface {
 	CreateTask(TaskCreateRequest) error
+	CreateTasks([]TaskCreateRequest) ([]TaskCreateResult, error)
 	Tasks() ([]TaskResponse, error)
 	TasksByDomain(domain string) ([]TaskResponse, error)
+	TasksWithFilter(filter TaskFilter) ([]TaskResponse, error)
+	PagedTasks(domain string, pageSize int) ([]TaskResponse, error)
 	GetTask(taskId string) (TaskResponse, error)
+	GetTaskResult(taskId string) ([]byte, error)
+	WaitForTask(taskId string, timeout time.Duration) (TaskResponse, error)
 	DeleteTask(taskId string) error
+	DeleteTasks(filter TaskFilter) (TasksDeleteResponse, error)
 	CancelTask(taskId string) error
+	RetryTask(taskId string, request TaskRetryRequest) (TaskRetryResponse, error)
+	TaskCallbackDelivery(taskId string) (CallbackDeliveryResponse, error)
 
 	CreateDesiredLRP(DesiredLRPCreateRequest) error
 	GetDesiredLRP(processGuid string) (DesiredLRPResponse, error)
 	UpdateDesiredLRP(processGuid string, update DesiredLRPUpdateRequest) error
+	UpdateDesiredLRPIfMatch(processGuid string, tag ModificationTag, update DesiredLRPUpdateRequest) error
 	DeleteDesiredLRP(processGuid string) error
+	StopDesiredLRP(processGuid string) error
+	StartDesiredLRP(processGuid string) error
+	ScaleDesiredLRPsByDomain(domain string, instances, maxInFlight int) (DesiredLRPsBulkResponse, error)
+	RestartDesiredLRPsByDomain(domain string, maxInFlight int) (DesiredLRPsBulkResponse, error)
+	DeleteDesiredLRPsByDomain(domain string, maxInFlight int) (DesiredLRPsBulkResponse, error)
+
+	AutoscalingPolicies() ([]AutoscalingPolicy, error)
+	GetAutoscalingPolicy(processGuid string) (AutoscalingPolicy, error)
+	SetAutoscalingPolicy(policy AutoscalingPolicy) error
+	DeleteAutoscalingPolicy(processGuid string) error
 	DesiredLRPs() ([]DesiredLRPResponse, error)
 	DesiredLRPsByDomain(domain string) ([]DesiredLRPResponse, error)
+	PagedDesiredLRPs(domain string, pageSize int) ([]DesiredLRPResponse, error)
+	DesiredLRPSummaries() ([]DesiredLRPSummaryResponse, error)
+	DesiredLRPSummariesByDomain(domain string) ([]DesiredLRPSummaryResponse, error)
+
+	StartRollingUpdate(processGuid string, request RollingUpdateRequest) (RollingUpdateResponse, error)
+	GetRollingUpdate(processGuid string) (RollingUpdateResponse, error)
+	AbortRollingUpdate(processGuid string) (RollingUpdateResponse, error)
 
 	ActualLRPs() ([]ActualLRPResponse, error)
 	ActualLRPsByDomain(domain string) ([]ActualLRPResponse, error)
+	ActualLRPsWithFilter(filter ActualLRPFilter) ([]ActualLRPResponse, error)
+	PagedActualLRPs(domain string, pageSize int) ([]ActualLRPResponse, error)
 	ActualLRPsByProcessGuid(processGuid string) ([]ActualLRPResponse, error)
 	ActualLRPByProcessGuidAndIndex(processGuid string, index int) (ActualLRPResponse, error)
+	ActualLRPGroups() ([]ActualLRPGroupResponse, error)
+	ActualLRPGroupsByDomain(domain string) ([]ActualLRPGroupResponse, error)
+	ActualLRPGroupsByProcessGuid(processGuid string) ([]ActualLRPGroupResponse, error)
+	ActualLRPGroupByProcessGuidAndIndex(processGuid string, index int) (ActualLRPGroupResponse, error)
 	KillActualLRPByProcessGuidAndIndex(processGuid string, index int) error
+	KillActualLRPsByProcessGuid(processGuid string, maxInFlight int) (ActualLRPsKillResponse, error)
+	KillActualLRPByInstanceGuid(processGuid, instanceGuid string) error
+	ActualLRPCrashes(processGuid string, index int) ([]ActualLRPCrashResponse, error)
+	CrashingActualLRPs() ([]CrashingActualLRPResponse, error)
 
 	SubscribeToEvents() (EventSource, error)
+	SubscribeToEventsWithFilter(filter EventFilter) (EventSource, error)
 
 	Cells() ([]CellResponse, error)
 
@@ -72,6 +111,7 @@ func NewClient(url string) Client {
 		streamingHTTPClient: cf_http.NewStreamingClient(),
 
 		reqGen: rata.NewRequestGenerator(url, Routes),
+		cache:  newResponseCache(),
 	}
 }
 
@@ -80,6 +120,7 @@ type client struct {
 	streamingHTTPClient *http.Client
 
 	reqGen *rata.RequestGenerator
+	cache  *responseCache
 }
 
 func (c *client) GetClient() *http.Client {
@@ -100,6 +141,12 @@ func (c *client) CreateTask(request TaskCreateRequest) error {
 	return c.doRequest(CreateTaskRoute, nil, nil, request, nil)
 }
 
+func (c *client) CreateTasks(requests []TaskCreateRequest) ([]TaskCreateResult, error) {
+	results := []TaskCreateResult{}
+	err := c.doRequest(CreateTasksBulkRoute, nil, nil, requests, &results)
+	return results, err
+}
+
 func (c *client) Tasks() ([]TaskResponse, error) {
 	tasks := []TaskResponse{}
 	err := c.doRequest(TasksRoute, nil, nil, nil, &tasks)
@@ -112,20 +159,115 @@ func (c *client) TasksByDomain(domain string) ([]TaskResponse, error) {
 	return tasks, err
 }
 
+func (c *client) TasksWithFilter(filter TaskFilter) ([]TaskResponse, error) {
+	tasks := []TaskResponse{}
+	err := c.doRequest(TasksRoute, nil, taskFilterQuery(filter), nil, &tasks)
+	return tasks, err
+}
+
+func (c *client) PagedTasks(domain string, pageSize int) ([]TaskResponse, error) {
+	var tasks []TaskResponse
+	err := c.doPagedRequest(TasksRoute, domainQuery(domain), pageSize, func(page json.RawMessage) error {
+		var pageTasks []TaskResponse
+		err := json.Unmarshal(page, &pageTasks)
+		tasks = append(tasks, pageTasks...)
+		return err
+	})
+	return tasks, err
+}
+
 func (c *client) GetTask(taskId string) (TaskResponse, error) {
 	task := TaskResponse{}
 	err := c.doRequest(GetTaskRoute, rata.Params{"task_guid": taskId}, nil, nil, &task)
 	return task, err
 }
 
+func (c *client) GetTaskResult(taskId string) ([]byte, error) {
+	req, err := c.createRequest(GetTaskResultRoute, rata.Params{"task_guid": taskId}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if routerError, ok := res.Header[XCfRouterErrorHeader]; ok {
+		return nil, Error{Type: RouterError, Message: routerError[0]}
+	}
+
+	if res.StatusCode > 299 {
+		var parsedContentType string
+		if contentType, ok := res.Header[ContentTypeHeader]; ok {
+			parsedContentType, _, _ = mime.ParseMediaType(contentType[0])
+		}
+
+		if parsedContentType == JSONContentType {
+			return nil, handleJSONResponse(res, nil)
+		}
+		return nil, handleNonJSONResponse(res)
+	}
+
+	return ioutil.ReadAll(res.Body)
+}
+
+// WaitForTask returns the task once it is COMPLETED or RESOLVING, or as it
+// stands once the timeout expires.
+func (c *client) WaitForTask(taskId string, timeout time.Duration) (TaskResponse, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		wait := deadline.Sub(time.Now())
+		if wait < 0 {
+			wait = 0
+		}
+
+		req, err := c.createRequest(GetTaskRoute, rata.Params{"task_guid": taskId}, url.Values{"wait": []string{wait.String()}}, nil)
+		if err != nil {
+			return TaskResponse{}, err
+		}
+
+		task := TaskResponse{}
+		_, err = c.doWithClient(c.streamingHTTPClient, req, &task)
+		if err != nil {
+			return TaskResponse{}, err
+		}
+
+		// the receptor caps how long a single request waits, so keep waiting
+		// until our own deadline
+		if task.State == TaskStateCompleted || task.State == TaskStateResolving || !time.Now().Before(deadline) {
+			return task, nil
+		}
+	}
+}
+
 func (c *client) DeleteTask(taskId string) error {
 	return c.doRequest(DeleteTaskRoute, rata.Params{"task_guid": taskId}, nil, nil, nil)
 }
 
+func (c *client) DeleteTasks(filter TaskFilter) (TasksDeleteResponse, error) {
+	response := TasksDeleteResponse{}
+	err := c.doRequest(DeleteTasksRoute, nil, taskFilterQuery(filter), nil, &response)
+	return response, err
+}
+
+func (c *client) RetryTask(taskId string, request TaskRetryRequest) (TaskRetryResponse, error) {
+	response := TaskRetryResponse{}
+	err := c.doRequest(RetryTaskRoute, rata.Params{"task_guid": taskId}, nil, request, &response)
+	return response, err
+}
+
 func (c *client) CancelTask(taskId string) error {
 	return c.doRequest(CancelTaskRoute, rata.Params{"task_guid": taskId}, nil, nil, nil)
 }
 
+func (c *client) TaskCallbackDelivery(taskId string) (CallbackDeliveryResponse, error) {
+	status := CallbackDeliveryResponse{}
+	err := c.doRequest(TaskCallbackDeliveryRoute, rata.Params{"task_guid": taskId}, nil, nil, &status)
+	return status, err
+}
+
 func (c *client) CreateDesiredLRP(req DesiredLRPCreateRequest) error {
 	return c.doRequest(CreateDesiredLRPRoute, nil, nil, req, nil)
 }
@@ -140,10 +282,84 @@ func (c *client) UpdateDesiredLRP(processGuid string, req DesiredLRPUpdateReques
 	return c.doRequest(UpdateDesiredLRPRoute, rata.Params{"process_guid": processGuid}, nil, req, nil)
 }
 
+func (c *client) UpdateDesiredLRPIfMatch(processGuid string, tag ModificationTag, update DesiredLRPUpdateRequest) error {
+	req, err := c.createRequest(UpdateDesiredLRPRoute, rata.Params{"process_guid": processGuid}, nil, update)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("If-Match", tag.ETag())
+	return c.do(req, nil)
+}
+
 func (c *client) DeleteDesiredLRP(processGuid string) error {
 	return c.doRequest(DeleteDesiredLRPRoute, rata.Params{"process_guid": processGuid}, nil, nil, nil)
 }
 
+func (c *client) StopDesiredLRP(processGuid string) error {
+	return c.doRequest(StopDesiredLRPRoute, rata.Params{"process_guid": processGuid}, nil, nil, nil)
+}
+
+func (c *client) StartDesiredLRP(processGuid string) error {
+	return c.doRequest(StartDesiredLRPRoute, rata.Params{"process_guid": processGuid}, nil, nil, nil)
+}
+
+func (c *client) ScaleDesiredLRPsByDomain(domain string, instances, maxInFlight int) (DesiredLRPsBulkResponse, error) {
+	response := DesiredLRPsBulkResponse{}
+	request := DesiredLRPsScaleRequest{Instances: instances}
+	err := c.doRequest(ScaleDesiredLRPsByDomainRoute, rata.Params{"domain": domain}, maxInFlightQuery(maxInFlight), request, &response)
+	return response, err
+}
+
+func (c *client) RestartDesiredLRPsByDomain(domain string, maxInFlight int) (DesiredLRPsBulkResponse, error) {
+	req, err := c.createRequest(RestartDesiredLRPsByDomainRoute, rata.Params{"domain": domain}, maxInFlightQuery(maxInFlight), nil)
+	if err != nil {
+		return DesiredLRPsBulkResponse{}, err
+	}
+
+	// the receptor stops restarting once the client disconnects, so do not
+	// give up on the request after the usual timeout
+	response := DesiredLRPsBulkResponse{}
+	_, err = c.doWithClient(c.streamingHTTPClient, req, &response)
+	return response, err
+}
+
+func (c *client) DeleteDesiredLRPsByDomain(domain string, maxInFlight int) (DesiredLRPsBulkResponse, error) {
+	response := DesiredLRPsBulkResponse{}
+	err := c.doRequest(DeleteDesiredLRPsByDomainRoute, rata.Params{"domain": domain}, maxInFlightQuery(maxInFlight), nil, &response)
+	return response, err
+}
+
+func (c *client) AutoscalingPolicies() ([]AutoscalingPolicy, error) {
+	policies := []AutoscalingPolicy{}
+	err := c.doRequest(AutoscalingPoliciesRoute, nil, nil, nil, &policies)
+	return policies, err
+}
+
+func (c *client) GetAutoscalingPolicy(processGuid string) (AutoscalingPolicy, error) {
+	policy := AutoscalingPolicy{}
+	err := c.doRequest(GetAutoscalingPolicyRoute, rata.Params{"process_guid": processGuid}, nil, nil, &policy)
+	return policy, err
+}
+
+func (c *client) SetAutoscalingPolicy(policy AutoscalingPolicy) error {
+	return c.doRequest(SetAutoscalingPolicyRoute, rata.Params{"process_guid": policy.ProcessGuid}, nil, policy, nil)
+}
+
+func (c *client) DeleteAutoscalingPolicy(processGuid string) error {
+	return c.doRequest(DeleteAutoscalingPolicyRoute, rata.Params{"process_guid": processGuid}, nil, nil, nil)
+}
+
+// maxInFlightQuery leaves the concurrency of a bulk action to the server
+// unless maxInFlight is positive.
+func maxInFlightQuery(maxInFlight int) url.Values {
+	query := url.Values{}
+	if maxInFlight > 0 {
+		query.Set("max_in_flight", strconv.Itoa(maxInFlight))
+	}
+	return query
+}
+
 func (c *client) DesiredLRPs() ([]DesiredLRPResponse, error) {
 	var desiredLRPs []DesiredLRPResponse
 	err := c.doRequest(DesiredLRPsRoute, nil, nil, nil, &desiredLRPs)
@@ -156,6 +372,47 @@ func (c *client) DesiredLRPsByDomain(domain string) ([]DesiredLRPResponse, error
 	return desiredLRPs, err
 }
 
+func (c *client) DesiredLRPSummaries() ([]DesiredLRPSummaryResponse, error) {
+	var summaries []DesiredLRPSummaryResponse
+	err := c.doRequest(DesiredLRPsRoute, nil, url.Values{"view": []string{"summary"}}, nil, &summaries)
+	return summaries, err
+}
+
+func (c *client) DesiredLRPSummariesByDomain(domain string) ([]DesiredLRPSummaryResponse, error) {
+	var summaries []DesiredLRPSummaryResponse
+	err := c.doRequest(DesiredLRPsRoute, nil, url.Values{"domain": []string{domain}, "view": []string{"summary"}}, nil, &summaries)
+	return summaries, err
+}
+
+func (c *client) StartRollingUpdate(processGuid string, request RollingUpdateRequest) (RollingUpdateResponse, error) {
+	status := RollingUpdateResponse{}
+	err := c.doRequest(StartRollingUpdateRoute, rata.Params{"process_guid": processGuid}, nil, request, &status)
+	return status, err
+}
+
+func (c *client) GetRollingUpdate(processGuid string) (RollingUpdateResponse, error) {
+	status := RollingUpdateResponse{}
+	err := c.doRequest(GetRollingUpdateRoute, rata.Params{"process_guid": processGuid}, nil, nil, &status)
+	return status, err
+}
+
+func (c *client) AbortRollingUpdate(processGuid string) (RollingUpdateResponse, error) {
+	status := RollingUpdateResponse{}
+	err := c.doRequest(AbortRollingUpdateRoute, rata.Params{"process_guid": processGuid}, nil, nil, &status)
+	return status, err
+}
+
+func (c *client) PagedDesiredLRPs(domain string, pageSize int) ([]DesiredLRPResponse, error) {
+	var desiredLRPs []DesiredLRPResponse
+	err := c.doPagedRequest(DesiredLRPsRoute, domainQuery(domain), pageSize, func(page json.RawMessage) error {
+		var pageDesiredLRPs []DesiredLRPResponse
+		err := json.Unmarshal(page, &pageDesiredLRPs)
+		desiredLRPs = append(desiredLRPs, pageDesiredLRPs...)
+		return err
+	})
+	return desiredLRPs, err
+}
+
 func (c *client) ActualLRPs() ([]ActualLRPResponse, error) {
 	var actualLRPs []ActualLRPResponse
 	err := c.doRequest(ActualLRPsRoute, nil, nil, nil, &actualLRPs)
@@ -168,6 +425,23 @@ func (c *client) ActualLRPsByDomain(domain string) ([]ActualLRPResponse, error)
 	return actualLRPs, err
 }
 
+func (c *client) ActualLRPsWithFilter(filter ActualLRPFilter) ([]ActualLRPResponse, error) {
+	var actualLRPs []ActualLRPResponse
+	err := c.doRequest(ActualLRPsRoute, nil, actualLRPFilterQuery(filter), nil, &actualLRPs)
+	return actualLRPs, err
+}
+
+func (c *client) PagedActualLRPs(domain string, pageSize int) ([]ActualLRPResponse, error) {
+	var actualLRPs []ActualLRPResponse
+	err := c.doPagedRequest(ActualLRPsRoute, domainQuery(domain), pageSize, func(page json.RawMessage) error {
+		var pageActualLRPs []ActualLRPResponse
+		err := json.Unmarshal(page, &pageActualLRPs)
+		actualLRPs = append(actualLRPs, pageActualLRPs...)
+		return err
+	})
+	return actualLRPs, err
+}
+
 func (c *client) ActualLRPsByProcessGuid(processGuid string) ([]ActualLRPResponse, error) {
 	var actualLRPs []ActualLRPResponse
 	err := c.doRequest(ActualLRPsByProcessGuidRoute, rata.Params{"process_guid": processGuid}, nil, nil, &actualLRPs)
@@ -180,25 +454,109 @@ func (c *client) ActualLRPByProcessGuidAndIndex(processGuid string, index int) (
 	return actualLRP, err
 }
 
+func (c *client) ActualLRPGroups() ([]ActualLRPGroupResponse, error) {
+	var groups []ActualLRPGroupResponse
+	err := c.doRequest(ActualLRPsRoute, nil, groupsQuery(), nil, &groups)
+	return groups, err
+}
+
+func (c *client) ActualLRPGroupsByDomain(domain string) ([]ActualLRPGroupResponse, error) {
+	var groups []ActualLRPGroupResponse
+	query := groupsQuery()
+	query.Set("domain", domain)
+	err := c.doRequest(ActualLRPsRoute, nil, query, nil, &groups)
+	return groups, err
+}
+
+func (c *client) ActualLRPGroupsByProcessGuid(processGuid string) ([]ActualLRPGroupResponse, error) {
+	var groups []ActualLRPGroupResponse
+	err := c.doRequest(ActualLRPsByProcessGuidRoute, rata.Params{"process_guid": processGuid}, groupsQuery(), nil, &groups)
+	return groups, err
+}
+
+func (c *client) ActualLRPGroupByProcessGuidAndIndex(processGuid string, index int) (ActualLRPGroupResponse, error) {
+	var group ActualLRPGroupResponse
+	err := c.doRequest(ActualLRPByProcessGuidAndIndexRoute, rata.Params{"process_guid": processGuid, "index": strconv.Itoa(index)}, groupsQuery(), nil, &group)
+	return group, err
+}
+
+func groupsQuery() url.Values {
+	return url.Values{"groups": []string{"true"}}
+}
+
 func (c *client) KillActualLRPByProcessGuidAndIndex(processGuid string, index int) error {
 	err := c.doRequest(KillActualLRPByProcessGuidAndIndexRoute, rata.Params{"process_guid": processGuid, "index": strconv.Itoa(index)}, nil, nil, nil)
 	return err
 }
 
+func (c *client) KillActualLRPsByProcessGuid(processGuid string, maxInFlight int) (ActualLRPsKillResponse, error) {
+	response := ActualLRPsKillResponse{}
+	err := c.doRequest(KillActualLRPsByProcessGuidRoute, rata.Params{"process_guid": processGuid}, maxInFlightQuery(maxInFlight), nil, &response)
+	return response, err
+}
+
+func (c *client) KillActualLRPByInstanceGuid(processGuid, instanceGuid string) error {
+	return c.doRequest(KillActualLRPByInstanceGuidRoute, rata.Params{"process_guid": processGuid, "instance_guid": instanceGuid}, nil, nil, nil)
+}
+
+func (c *client) ActualLRPCrashes(processGuid string, index int) ([]ActualLRPCrashResponse, error) {
+	crashes := []ActualLRPCrashResponse{}
+	err := c.doRequest(ActualLRPCrashesRoute, rata.Params{"process_guid": processGuid, "index": strconv.Itoa(index)}, nil, nil, &crashes)
+	return crashes, err
+}
+
+func (c *client) CrashingActualLRPs() ([]CrashingActualLRPResponse, error) {
+	crashing := []CrashingActualLRPResponse{}
+	err := c.doRequest(CrashingActualLRPsRoute, nil, nil, nil, &crashing)
+	return crashing, err
+}
+
 func (c *client) SubscribeToEvents() (EventSource, error) {
-	eventSource, err := sse.Connect(c.streamingHTTPClient, time.Second, func() *http.Request {
-		request, err := c.reqGen.CreateRequest(EventStream, nil, nil)
+	return c.SubscribeToEventsWithFilter(EventFilter{})
+}
+
+func (c *client) SubscribeToEventsWithFilter(filter EventFilter) (EventSource, error) {
+	queryParams := url.Values{}
+	if len(filter.Types) > 0 {
+		types := make([]string, 0, len(filter.Types))
+		for _, eventType := range filter.Types {
+			types = append(types, string(eventType))
+		}
+		queryParams.Set("types", strings.Join(types, ","))
+	}
+	if filter.Domain != "" {
+		queryParams.Set("domain", filter.Domain)
+	}
+	if filter.ProcessGuid != "" {
+		queryParams.Set("process_guid", filter.ProcessGuid)
+	}
+
+	connect := func(lastEventID string) (RawEventSource, error) {
+		source, err := sse.Connect(c.streamingHTTPClient, time.Second, func() *http.Request {
+			request, err := c.reqGen.CreateRequest(EventStream, nil, nil)
+			if err != nil {
+				panic(err) // totally shouldn't happen
+			}
+
+			request.URL.RawQuery = queryParams.Encode()
+			if lastEventID != "" {
+				request.Header.Set("Last-Event-ID", lastEventID)
+			}
+			return request
+		})
 		if err != nil {
-			panic(err) // totally shouldn't happen
+			return nil, err
 		}
 
-		return request
-	})
+		return source, nil
+	}
+
+	eventSource, err := connect("")
 	if err != nil {
 		return nil, err
 	}
 
-	return NewEventSource(eventSource), nil
+	return NewEventSourceWithReconnect(eventSource, connect), nil
 }
 
 func (c *client) Cells() ([]CellResponse, error) {
@@ -251,26 +609,161 @@ func (c *client) doRequest(requestName string, params rata.Params, queryParams u
 	return c.do(req, response)
 }
 
+// doPagedRequest follows the Link headers of a paginated list endpoint,
+// handing each page of the JSON array to appendPage.
+func (c *client) doPagedRequest(requestName string, queryParams url.Values, pageSize int, appendPage func(json.RawMessage) error) error {
+	pageToken := ""
+	for {
+		pageParams := url.Values{}
+		for key, values := range queryParams {
+			pageParams[key] = values
+		}
+		pageParams.Set("limit", strconv.Itoa(pageSize))
+		if pageToken != "" {
+			pageParams.Set("page_token", pageToken)
+		}
+
+		req, err := c.createRequest(requestName, nil, pageParams, nil)
+		if err != nil {
+			return err
+		}
+
+		var page json.RawMessage
+		header, err := c.doWithHeader(req, &page)
+		if err != nil {
+			return err
+		}
+
+		err = appendPage(page)
+		if err != nil {
+			return err
+		}
+
+		pageToken = nextPageToken(header.Get("Link"))
+		if pageToken == "" {
+			return nil
+		}
+	}
+}
+
+func nextPageToken(link string) string {
+	if !strings.Contains(link, `rel="next"`) {
+		return ""
+	}
+
+	start := strings.Index(link, "<")
+	end := strings.Index(link, ">")
+	if start < 0 || end < start {
+		return ""
+	}
+
+	next, err := url.Parse(link[start+1 : end])
+	if err != nil {
+		return ""
+	}
+
+	return next.Query().Get("page_token")
+}
+
+func domainQuery(domain string) url.Values {
+	if domain == "" {
+		return nil
+	}
+	return url.Values{"domain": []string{domain}}
+}
+
+func taskFilterQuery(filter TaskFilter) url.Values {
+	queryParams := url.Values{}
+	if filter.Domain != "" {
+		queryParams.Set("domain", filter.Domain)
+	}
+	if filter.State != "" {
+		queryParams.Set("state", filter.State)
+	}
+	if filter.CellID != "" {
+		queryParams.Set("cell_id", filter.CellID)
+	}
+	if filter.CompletedBefore != 0 {
+		queryParams.Set("completed_before", strconv.FormatInt(filter.CompletedBefore, 10))
+	}
+	if filter.OmitResult {
+		queryParams.Set("omit_result", "true")
+	}
+	return queryParams
+}
+
+func actualLRPFilterQuery(filter ActualLRPFilter) url.Values {
+	queryParams := url.Values{}
+	if filter.Domain != "" {
+		queryParams.Set("domain", filter.Domain)
+	}
+	if filter.CellID != "" {
+		queryParams.Set("cell_id", filter.CellID)
+	}
+	if filter.State != "" {
+		queryParams.Set("state", string(filter.State))
+	}
+	if filter.Zone != "" {
+		queryParams.Set("zone", filter.Zone)
+	}
+	return queryParams
+}
+
 func (c *client) do(req *http.Request, responseObject interface{}) error {
-	res, err := c.httpClient.Do(req)
+	_, err := c.doWithHeader(req, responseObject)
+	return err
+}
+
+func (c *client) doWithHeader(req *http.Request, responseObject interface{}) (http.Header, error) {
+	return c.doWithClient(c.httpClient, req, responseObject)
+}
+
+func (c *client) doWithClient(httpClient *http.Client, req *http.Request, responseObject interface{}) (http.Header, error) {
+	var cached cachedResponse
+	var isCached bool
+
+	cacheable := req.Method == "GET"
+	if cacheable {
+		cached, isCached = c.cache.get(req.URL.String())
+		if isCached {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+	}
+
+	res, err := httpClient.Do(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer res.Body.Close()
 
+	if res.StatusCode == http.StatusNotModified && isCached {
+		return cached.header, handleCachedResponse(cached, responseObject)
+	}
+
 	var parsedContentType string
 	if contentType, ok := res.Header[ContentTypeHeader]; ok {
 		parsedContentType, _, _ = mime.ParseMediaType(contentType[0])
 	}
 
 	if routerError, ok := res.Header[XCfRouterErrorHeader]; ok {
-		return Error{Type: RouterError, Message: routerError[0]}
+		return res.Header, Error{Type: RouterError, Message: routerError[0]}
 	}
 
 	if parsedContentType == JSONContentType {
-		return handleJSONResponse(res, responseObject)
+		etag := res.Header.Get("ETag")
+		if cacheable && etag != "" && res.StatusCode == http.StatusOK {
+			body, err := ioutil.ReadAll(res.Body)
+			if err != nil {
+				return res.Header, err
+			}
+
+			c.cache.put(req.URL.String(), cachedResponse{etag: etag, header: res.Header, body: body})
+			res.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+
+		return res.Header, handleJSONResponse(res, responseObject)
 	} else {
-		return handleNonJSONResponse(res)
+		return res.Header, handleNonJSONResponse(res)
 	}
 }
 
@@ -289,6 +782,13 @@ func handleJSONResponse(res *http.Response, responseObject interface{}) error {
 	return nil
 }
 
+func handleCachedResponse(cached cachedResponse, responseObject interface{}) error {
+	if err := json.Unmarshal(cached.body, responseObject); err != nil {
+		return Error{Type: InvalidJSON, Message: err.Error()}
+	}
+	return nil
+}
+
 func handleNonJSONResponse(res *http.Response) error {
 	if res.StatusCode > 299 {
 		return Error{
@@ -3,6 +3,7 @@ package receptor_test
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -54,6 +55,420 @@ var _ = Describe("Receptor Client", func() {
 		})
 	})
 
+	Describe("PagedTasks", func() {
+		BeforeEach(func() {
+			fakeReceptorServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/v1/tasks", "domain=some-domain&limit=2"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, []receptor.TaskResponse{
+						{TaskGuid: "task-guid-1"},
+						{TaskGuid: "task-guid-2"},
+					}, http.Header{"Link": []string{`</v1/tasks?domain=some-domain&limit=2&page_token=some-token>; rel="next"`}}),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/v1/tasks", "domain=some-domain&limit=2&page_token=some-token"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, []receptor.TaskResponse{
+						{TaskGuid: "task-guid-3"},
+					}),
+				),
+			)
+		})
+
+		It("follows the next page links until the last page", func() {
+			tasks, err := client.PagedTasks("some-domain", 2)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeReceptorServer.ReceivedRequests()).To(HaveLen(2))
+
+			Expect(tasks).To(Equal([]receptor.TaskResponse{
+				{TaskGuid: "task-guid-1"},
+				{TaskGuid: "task-guid-2"},
+				{TaskGuid: "task-guid-3"},
+			}))
+		})
+	})
+
+	Describe("UpdateDesiredLRPIfMatch", func() {
+		BeforeEach(func() {
+			fakeReceptorServer.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("PUT", "/v1/desired_lrps/some-guid"),
+				ghttp.VerifyHeaderKV("If-Match", `"some-epoch:4"`),
+				ghttp.RespondWith(http.StatusPreconditionFailed, `{"name":"PreconditionFailed","message":"modified"}`, http.Header{"Content-Type": []string{receptor.JSONContentType}}),
+			))
+		})
+
+		It("sends the modification tag and returns the precondition failure", func() {
+			instances := 2
+			err := client.UpdateDesiredLRPIfMatch("some-guid", receptor.ModificationTag{Epoch: "some-epoch", Index: 4}, receptor.DesiredLRPUpdateRequest{Instances: &instances})
+			Expect(err).To(Equal(receptor.Error{Type: receptor.PreconditionFailed, Message: "modified"}))
+		})
+	})
+
+	Describe("Conditional requests", func() {
+		var lrpResponse []receptor.DesiredLRPResponse
+
+		BeforeEach(func() {
+			lrpResponse = []receptor.DesiredLRPResponse{
+				{ProcessGuid: "some-guid", Domain: "diego"},
+			}
+
+			fakeReceptorServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/v1/desired_lrps"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, lrpResponse, http.Header{"ETag": []string{`"some-etag"`}}),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/v1/desired_lrps"),
+					ghttp.VerifyHeaderKV("If-None-Match", `"some-etag"`),
+					ghttp.RespondWith(http.StatusNotModified, nil),
+				),
+			)
+		})
+
+		It("revalidates cached responses and reuses them when not modified", func() {
+			response, err := client.DesiredLRPs()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal(lrpResponse))
+
+			response, err = client.DesiredLRPs()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal(lrpResponse))
+
+			Expect(fakeReceptorServer.ReceivedRequests()).To(HaveLen(2))
+		})
+
+		Context("when the response is too large to cache", func() {
+			BeforeEach(func() {
+				lrpResponse[0].Annotation = strings.Repeat("x", 1024*1024)
+
+				fakeReceptorServer.SetHandler(0, ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/v1/desired_lrps"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, lrpResponse, http.Header{"ETag": []string{`"some-etag"`}}),
+				))
+				fakeReceptorServer.SetHandler(1, ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/v1/desired_lrps"),
+					func(w http.ResponseWriter, req *http.Request) {
+						Expect(req.Header.Get("If-None-Match")).To(BeEmpty())
+					},
+					ghttp.RespondWithJSONEncoded(http.StatusOK, lrpResponse, http.Header{"ETag": []string{`"some-etag"`}}),
+				))
+			})
+
+			It("does not revalidate it", func() {
+				response, err := client.DesiredLRPs()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response).To(Equal(lrpResponse))
+
+				response, err = client.DesiredLRPs()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response).To(Equal(lrpResponse))
+			})
+		})
+	})
+
+	Describe("GetTaskResult", func() {
+		Context("when the task has completed", func() {
+			BeforeEach(func() {
+				fakeReceptorServer.AppendHandlers(ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/v1/tasks/some-guid/result"),
+					ghttp.RespondWith(http.StatusOK, "some-result", http.Header{"Content-Type": []string{"application/octet-stream"}}),
+				))
+			})
+
+			It("returns the raw result", func() {
+				result, err := client.GetTaskResult("some-guid")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(Equal([]byte("some-result")))
+			})
+		})
+
+		Context("when the receptor responds with an error", func() {
+			BeforeEach(func() {
+				fakeReceptorServer.AppendHandlers(ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/v1/tasks/some-guid/result"),
+					ghttp.RespondWith(http.StatusConflict, `{"name":"TaskNotCompleted","message":"not yet"}`, http.Header{"Content-Type": []string{receptor.JSONContentType}}),
+				))
+			})
+
+			It("returns the error", func() {
+				_, err := client.GetTaskResult("some-guid")
+				Expect(err).To(Equal(receptor.Error{Type: receptor.TaskNotCompleted, Message: "not yet"}))
+			})
+		})
+	})
+
+	Describe("DeleteTasks", func() {
+		var summary receptor.TasksDeleteResponse
+
+		BeforeEach(func() {
+			summary = receptor.TasksDeleteResponse{
+				Deleted: []string{"task-guid-1"},
+				Failed: []receptor.TaskDeleteFailure{
+					{TaskGuid: "task-guid-2", Error: receptor.Error{Type: receptor.TaskNotFound, Message: "gone"}},
+				},
+			}
+
+			fakeReceptorServer.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("DELETE", "/v1/tasks", "completed_before=1000&domain=some-domain&state=COMPLETED"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, summary),
+			))
+		})
+
+		It("passes the filter and returns the summary", func() {
+			response, err := client.DeleteTasks(receptor.TaskFilter{
+				Domain:          "some-domain",
+				State:           receptor.TaskStateCompleted,
+				CompletedBefore: 1000,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal(summary))
+		})
+	})
+
+	Describe("RetryTask", func() {
+		BeforeEach(func() {
+			fakeReceptorServer.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/v1/tasks/failed-guid/retry"),
+				ghttp.VerifyJSON(`{"task_guid":"retried-guid"}`),
+				ghttp.RespondWithJSONEncoded(http.StatusCreated, receptor.TaskRetryResponse{
+					TaskGuid: "retried-guid",
+					RetryOf:  "failed-guid",
+				}),
+			))
+		})
+
+		It("returns the lineage of the retried task", func() {
+			response, err := client.RetryTask("failed-guid", receptor.TaskRetryRequest{TaskGuid: "retried-guid"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal(receptor.TaskRetryResponse{
+				TaskGuid: "retried-guid",
+				RetryOf:  "failed-guid",
+			}))
+		})
+	})
+
+	Describe("StartRollingUpdate", func() {
+		var status receptor.RollingUpdateResponse
+
+		BeforeEach(func() {
+			status = receptor.RollingUpdateResponse{
+				ProcessGuid:          "old-guid",
+				SuccessorProcessGuid: "new-guid",
+				State:                receptor.RollingUpdateStateInProgress,
+			}
+
+			fakeReceptorServer.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/v1/desired_lrps/old-guid/rolling_update"),
+				ghttp.RespondWithJSONEncoded(http.StatusAccepted, status),
+			))
+		})
+
+		It("returns the progress of the update", func() {
+			response, err := client.StartRollingUpdate("old-guid", receptor.RollingUpdateRequest{BatchSize: 2})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal(status))
+		})
+	})
+
+	Describe("StopDesiredLRP", func() {
+		BeforeEach(func() {
+			fakeReceptorServer.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/v1/desired_lrps/some-guid/stop"),
+				ghttp.RespondWith(http.StatusNoContent, nil),
+			))
+		})
+
+		It("stops the desired LRP", func() {
+			err := client.StopDesiredLRP("some-guid")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeReceptorServer.ReceivedRequests()).To(HaveLen(1))
+		})
+	})
+
+	Describe("StartDesiredLRP", func() {
+		BeforeEach(func() {
+			fakeReceptorServer.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/v1/desired_lrps/some-guid/start"),
+				ghttp.RespondWith(http.StatusConflict, `{"name":"DesiredLRPNotStopped","message":"not stopped"}`, http.Header{"Content-Type": []string{receptor.JSONContentType}}),
+			))
+		})
+
+		It("returns the error when the desired LRP is not stopped", func() {
+			err := client.StartDesiredLRP("some-guid")
+			Expect(err).To(Equal(receptor.Error{Type: receptor.DesiredLRPNotStopped, Message: "not stopped"}))
+		})
+	})
+
+	Describe("RestartDesiredLRPsByDomain", func() {
+		var summary receptor.DesiredLRPsBulkResponse
+
+		BeforeEach(func() {
+			summary = receptor.DesiredLRPsBulkResponse{
+				Succeeded: []string{"some-guid"},
+				Failed:    []receptor.DesiredLRPBulkFailure{},
+			}
+
+			fakeReceptorServer.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("POST", "/v1/domains/some-domain/desired_lrps/restart", "max_in_flight=5"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, summary),
+			))
+		})
+
+		It("returns the outcome for each desired LRP", func() {
+			response, err := client.RestartDesiredLRPsByDomain("some-domain", 5)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal(summary))
+		})
+	})
+
+	Describe("SetAutoscalingPolicy", func() {
+		var policy receptor.AutoscalingPolicy
+
+		BeforeEach(func() {
+			policy = receptor.AutoscalingPolicy{
+				ProcessGuid:  "some-guid",
+				MinInstances: 1,
+				MaxInstances: 3,
+			}
+
+			fakeReceptorServer.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("PUT", "/v1/autoscaling_policies/some-guid"),
+				ghttp.VerifyJSONRepresenting(policy),
+				ghttp.RespondWith(http.StatusNoContent, nil),
+			))
+		})
+
+		It("sets the policy of the desired LRP it names", func() {
+			err := client.SetAutoscalingPolicy(policy)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeReceptorServer.ReceivedRequests()).To(HaveLen(1))
+		})
+	})
+
+	Describe("ActualLRPsWithFilter", func() {
+		BeforeEach(func() {
+			fakeReceptorServer.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v1/actual_lrps", "cell_id=cell-1&state=RUNNING&zone=z1"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, []receptor.ActualLRPResponse{
+					{ProcessGuid: "some-guid", CellID: "cell-1", State: receptor.ActualLRPStateRunning},
+				}),
+			))
+		})
+
+		It("sends the non-empty fields of the filter as query params", func() {
+			actualLRPs, err := client.ActualLRPsWithFilter(receptor.ActualLRPFilter{
+				CellID: "cell-1",
+				State:  receptor.ActualLRPStateRunning,
+				Zone:   "z1",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actualLRPs).To(HaveLen(1))
+			Expect(actualLRPs[0].ProcessGuid).To(Equal("some-guid"))
+		})
+	})
+
+	Describe("ActualLRPGroupsByProcessGuid", func() {
+		var groups []receptor.ActualLRPGroupResponse
+
+		BeforeEach(func() {
+			groups = []receptor.ActualLRPGroupResponse{{
+				Instance:   &receptor.ActualLRPResponse{ProcessGuid: "some-guid", InstanceGuid: "new-instance"},
+				Evacuating: &receptor.ActualLRPResponse{ProcessGuid: "some-guid", InstanceGuid: "old-instance", Evacuating: true},
+			}}
+
+			fakeReceptorServer.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v1/actual_lrps/some-guid", "groups=true"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, groups),
+			))
+		})
+
+		It("returns both actual LRPs of each index", func() {
+			response, err := client.ActualLRPGroupsByProcessGuid("some-guid")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal(groups))
+		})
+	})
+
+	Describe("KillActualLRPsByProcessGuid", func() {
+		var summary receptor.ActualLRPsKillResponse
+
+		BeforeEach(func() {
+			summary = receptor.ActualLRPsKillResponse{
+				Killed: []int{0, 1},
+				Failed: []receptor.ActualLRPKillFailure{},
+			}
+
+			fakeReceptorServer.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("DELETE", "/v1/actual_lrps/some-guid", "max_in_flight=1"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, summary),
+			))
+		})
+
+		It("returns the indices which were killed", func() {
+			response, err := client.KillActualLRPsByProcessGuid("some-guid", 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal(summary))
+		})
+	})
+
+	Describe("KillActualLRPByInstanceGuid", func() {
+		BeforeEach(func() {
+			fakeReceptorServer.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("DELETE", "/v1/actual_lrps/some-guid/instances/some-instance-guid"),
+				ghttp.RespondWith(http.StatusNoContent, nil),
+			))
+		})
+
+		It("kills the instance", func() {
+			err := client.KillActualLRPByInstanceGuid("some-guid", "some-instance-guid")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeReceptorServer.ReceivedRequests()).To(HaveLen(1))
+		})
+	})
+
+	Describe("ActualLRPCrashes", func() {
+		var crashes []receptor.ActualLRPCrashResponse
+
+		BeforeEach(func() {
+			crashes = []receptor.ActualLRPCrashResponse{
+				{InstanceGuid: "some-instance-guid", CrashCount: 1, CrashReason: "out of memory", CrashedAt: 10},
+			}
+
+			fakeReceptorServer.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v1/actual_lrps/some-guid/index/2/crashes"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, crashes),
+			))
+		})
+
+		It("returns the crashes of the actual LRP", func() {
+			response, err := client.ActualLRPCrashes("some-guid", 2)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal(crashes))
+		})
+	})
+
+	Describe("SubscribeToEventsWithFilter", func() {
+		BeforeEach(func() {
+			fakeReceptorServer.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/v1/events", "domain=some-domain&process_guid=some-guid&types=desired_lrp_created%2Cactual_lrp_changed"),
+				ghttp.RespondWith(http.StatusOK, "", http.Header{
+					receptor.ContentTypeHeader: []string{"text/event-stream; charset=utf-8"},
+				}),
+			))
+		})
+
+		It("passes the filter as query parameters", func() {
+			eventSource, err := client.SubscribeToEventsWithFilter(receptor.EventFilter{
+				Types:       []receptor.EventType{receptor.EventTypeDesiredLRPCreated, receptor.EventTypeActualLRPChanged},
+				Domain:      "some-domain",
+				ProcessGuid: "some-guid",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeReceptorServer.ReceivedRequests()).To(HaveLen(1))
+
+			eventSource.Close()
+		})
+	})
+
 	Describe("Content Type Validation and Error Handling", func() {
 		var (
 			httpHeaders  http.Header
@@ -145,7 +560,7 @@ var _ = Describe("Receptor Client", func() {
 
 						_, err := client.DesiredLRPs()
 
-						verifyReceptorError(err, receptor.Error{receptor.RouterError, expectedErrorMessage})
+						verifyReceptorError(err, receptor.Error{Type: receptor.RouterError, Message: expectedErrorMessage})
 					})
 				})
 			})
@@ -158,7 +573,7 @@ var _ = Describe("Receptor Client", func() {
 
 					_, err := client.DesiredLRPs()
 
-					verifyReceptorError(err, receptor.Error{receptor.InvalidResponse, expectedErrorMessage})
+					verifyReceptorError(err, receptor.Error{Type: receptor.InvalidResponse, Message: expectedErrorMessage})
 				})
 			})
 		})
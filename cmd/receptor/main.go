@@ -19,9 +19,17 @@ import (
 	"github.com/cloudfoundry-incubator/cf_http"
 	"github.com/cloudfoundry-incubator/consuladapter"
 	"github.com/cloudfoundry-incubator/natbeat"
+	"github.com/cloudfoundry-incubator/receptor/autoscaler"
+	"github.com/cloudfoundry-incubator/receptor/callback"
+	"github.com/cloudfoundry-incubator/receptor/crashhistory"
+	"github.com/cloudfoundry-incubator/receptor/event"
 	"github.com/cloudfoundry-incubator/receptor/handlers"
+	"github.com/cloudfoundry-incubator/receptor/leader"
+	"github.com/cloudfoundry-incubator/receptor/rollingupdate"
+	"github.com/cloudfoundry-incubator/receptor/store"
 	"github.com/cloudfoundry/dropsonde"
 	"github.com/cloudfoundry/gunk/diegonats"
+	"github.com/hashicorp/consul/api"
 	"github.com/pivotal-golang/clock"
 	"github.com/pivotal-golang/lager"
 	"github.com/pivotal-golang/localip"
@@ -127,6 +135,54 @@ var bbsClientKey = flag.String(
 	"path to client key used for mutually authenticated TLS BBS communication",
 )
 
+var eventReplayBufferSize = flag.Int(
+	"eventReplayBufferSize",
+	1024,
+	"Number of recent events retained for clients resuming the event stream with Last-Event-ID.",
+)
+
+var eventHeartbeatInterval = flag.Duration(
+	"eventHeartbeatInterval",
+	15*time.Second,
+	"Interval at which heartbeats are sent on event streams, 0 disables them.",
+)
+
+var dispatchCompletionCallbacks = flag.Bool(
+	"dispatchCompletionCallbacks",
+	false,
+	"Deliver signed task completion callbacks from the receptor, retrying failed deliveries.",
+)
+
+var callbackSecret = flag.String(
+	"callbackSecret",
+	"",
+	"Secret used to sign the completion callbacks delivered by the receptor.",
+)
+
+var callbackMaxAttempts = flag.Int(
+	"callbackMaxAttempts",
+	callback.DefaultMaxAttempts,
+	"Number of times the receptor attempts to deliver a completion callback.",
+)
+
+var rollingUpdateBatchTimeout = flag.Duration(
+	"rollingUpdateBatchTimeout",
+	rollingupdate.DefaultBatchTimeout,
+	"Time a batch of a rolling update has to start running before the update is rolled back.",
+)
+
+var autoscalingInterval = flag.Duration(
+	"autoscalingInterval",
+	0,
+	"Interval at which desired LRPs are scaled according to their autoscaling policies, 0 disables autoscaling.",
+)
+
+var autoscalingCooldown = flag.Duration(
+	"autoscalingCooldown",
+	autoscaler.DefaultCooldown,
+	"Time a desired LRP is left alone after being autoscaled.",
+)
+
 var artifactPath = flag.String(
 	"artifactPath",
 	"",
@@ -142,6 +198,8 @@ var versionFilesPath = flag.String(
 const (
 	dropsondeDestination = "localhost:3457"
 	dropsondeOrigin      = "receptor"
+
+	consulStorePrefix = "v1/receptor"
 )
 
 type artifactLocator struct {
@@ -186,14 +244,57 @@ func main() {
 		logger.Fatal("invalid-bbs-address", err)
 	}
 
-	serviceClient := initializeServiceClient(logger)
+	consulClient := initializeConsulClient(logger)
+	serviceClient := initializeServiceClient(consulClient, logger)
+	receptorStore := store.NewConsulStore(consulClient, consulStorePrefix)
+	stoppedLRPs := store.NewStoppedLRPStore(receptorStore)
+
+	bbsClient := initializeBBSClient(logger)
+	hub := event.NewHub(*eventReplayBufferSize)
+
+	callbackStatuses := callback.NewStatusStore(receptorStore, clock.NewClock(), callback.DefaultStatusRetention)
+
+	rollingUpdates := rollingupdate.NewCoordinator(bbsClient, hub, receptorStore, clock.NewClock(), rollingupdate.Config{
+		BatchTimeout: *rollingUpdateBatchTimeout,
+		PollInterval: rollingupdate.DefaultPollInterval,
+	}, logger)
+
+	autoscalingPolicies := autoscaler.NewPolicyStore(bbsClient, receptorStore)
 
-	handler := handlers.New(initializeBBSClient(logger), serviceClient, logger, *username, *password, *corsEnabled, &artifactLocator{*artifactPath}, &versionFilesLocator{*versionFilesPath})
+	crashHistory := crashhistory.NewStore(clock.NewClock(), crashhistory.DefaultConfig())
+
+	handler := handlers.New(bbsClient, serviceClient, hub, *eventHeartbeatInterval, callbackStatuses, rollingUpdates, stoppedLRPs, autoscalingPolicies, crashHistory, logger, *username, *password, *corsEnabled, &artifactLocator{*artifactPath}, &versionFilesLocator{*versionFilesPath})
 
 	members := grouper.Members{
-		{"server", http_server.New(*serverAddress, handler)},
+		{"bbs-event-relay", event.NewBBSRelay(bbsClient, hub, clock.NewClock(), event.DefaultResubscribeInterval, logger)},
+		{"crash-recorder", crashhistory.NewRecorder(hub, crashHistory, clock.NewClock(), logger)},
+	}
+
+	if *dispatchCompletionCallbacks {
+		members = append(members, grouper.Member{
+			Name:   "callback-dispatcher",
+			Runner: leader.NewRunner(initializeLock(consulClient, "callback-dispatcher", logger), initializeCallbackDispatcher(bbsClient, hub, callbackStatuses, logger), clock.NewClock(), leader.DefaultRetryInterval, logger),
+		})
 	}
 
+	members = append(members, grouper.Member{
+		Name:   "rolling-update-coordinator",
+		Runner: leader.NewRunner(initializeLock(consulClient, "rolling-update-coordinator", logger), rollingUpdates, clock.NewClock(), leader.DefaultRetryInterval, logger),
+	})
+
+	if *autoscalingInterval > 0 {
+		controller := autoscaler.NewController(bbsClient, autoscalingPolicies, stoppedLRPs, autoscaler.NoMetrics, clock.NewClock(), *autoscalingInterval, *autoscalingCooldown, logger)
+		members = append(members, grouper.Member{
+			Name:   "autoscaler",
+			Runner: leader.NewRunner(initializeLock(consulClient, "autoscaler", logger), controller, clock.NewClock(), leader.DefaultRetryInterval, logger),
+		})
+	}
+
+	members = append(members, grouper.Member{
+		Name:   "server",
+		Runner: http_server.New(*serverAddress, handler),
+	})
+
 	if *registerWithRouter {
 		registration := initializeServerRegistration(logger)
 		natsClient := diegonats.NewClient()
@@ -224,6 +325,21 @@ func main() {
 	logger.Info("exited")
 }
 
+func initializeCallbackDispatcher(bbsClient bbs.Client, hub event.Hub, statuses callback.StatusStore, logger lager.Logger) ifrit.Runner {
+	if *callbackSecret == "" {
+		logger.Fatal("invalid-callback-flags", errors.New("dispatchCompletionCallbacks is set, but callbackSecret was left blank"))
+	}
+
+	config := callback.Config{
+		Secret:         *callbackSecret,
+		InitialBackoff: callback.DefaultInitialBackoff,
+		MaxBackoff:     callback.DefaultMaxBackoff,
+		MaxAttempts:    *callbackMaxAttempts,
+	}
+
+	return callback.NewDispatcher(bbsClient, hub, statuses, cf_http.NewClient(), clock.NewClock(), config, logger)
+}
+
 func validateBBSAddress() error {
 	if *bbsAddress == "" {
 		return errors.New("bbsAddress is required")
@@ -247,12 +363,28 @@ func initializeDropsonde(logger lager.Logger) {
 	}
 }
 
-func initializeServiceClient(logger lager.Logger) bbs.ServiceClient {
+func initializeConsulClient(logger lager.Logger) *api.Client {
 	client, err := consuladapter.NewClient(*consulCluster)
 	if err != nil {
 		logger.Fatal("new-client-failed", err)
 	}
+	return client
+}
+
+// initializeLock returns a lock which at most one receptor holds, for the
+// components that must only run on one of them.
+func initializeLock(client *api.Client, name string, logger lager.Logger) leader.Lock {
+	lock, err := client.LockOpts(&api.LockOptions{
+		Key:        consulStorePrefix + "/locks/" + name,
+		SessionTTL: lockTTL.String(),
+	})
+	if err != nil {
+		logger.Fatal("failed-to-create-lock", err, lager.Data{"name": name})
+	}
+	return lock
+}
 
+func initializeServiceClient(client *api.Client, logger lager.Logger) bbs.ServiceClient {
 	sessionMgr := consuladapter.NewSessionManager(client)
 	consulSession, err := consuladapter.NewSession("receptor", *lockTTL, client, sessionMgr)
 	if err != nil {
@@ -0,0 +1,13 @@
+package crashhistory_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestCrashHistory(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Crash History Suite")
+}
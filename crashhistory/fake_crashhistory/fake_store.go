@@ -0,0 +1,116 @@
+// This file was generated by counterfeiter
+package fake_crashhistory
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/crashhistory"
+)
+
+type FakeStore struct {
+	RecordStub        func(actualLRP receptor.ActualLRPResponse, crash receptor.ActualLRPCrashResponse)
+	recordMutex       sync.RWMutex
+	recordArgsForCall []struct {
+		actualLRP receptor.ActualLRPResponse
+		crash     receptor.ActualLRPCrashResponse
+	}
+	HistoryStub        func(processGuid string, index int) []receptor.ActualLRPCrashResponse
+	historyMutex       sync.RWMutex
+	historyArgsForCall []struct {
+		processGuid string
+		index       int
+	}
+	historyReturns struct {
+		result1 []receptor.ActualLRPCrashResponse
+	}
+	CrashingStub        func() []receptor.CrashingActualLRPResponse
+	crashingMutex       sync.RWMutex
+	crashingArgsForCall []struct{}
+	crashingReturns     struct {
+		result1 []receptor.CrashingActualLRPResponse
+	}
+}
+
+func (fake *FakeStore) Record(actualLRP receptor.ActualLRPResponse, crash receptor.ActualLRPCrashResponse) {
+	fake.recordMutex.Lock()
+	fake.recordArgsForCall = append(fake.recordArgsForCall, struct {
+		actualLRP receptor.ActualLRPResponse
+		crash     receptor.ActualLRPCrashResponse
+	}{actualLRP, crash})
+	fake.recordMutex.Unlock()
+	if fake.RecordStub != nil {
+		fake.RecordStub(actualLRP, crash)
+	}
+}
+
+func (fake *FakeStore) RecordCallCount() int {
+	fake.recordMutex.RLock()
+	defer fake.recordMutex.RUnlock()
+	return len(fake.recordArgsForCall)
+}
+
+func (fake *FakeStore) RecordArgsForCall(i int) (receptor.ActualLRPResponse, receptor.ActualLRPCrashResponse) {
+	fake.recordMutex.RLock()
+	defer fake.recordMutex.RUnlock()
+	return fake.recordArgsForCall[i].actualLRP, fake.recordArgsForCall[i].crash
+}
+
+func (fake *FakeStore) History(processGuid string, index int) []receptor.ActualLRPCrashResponse {
+	fake.historyMutex.Lock()
+	fake.historyArgsForCall = append(fake.historyArgsForCall, struct {
+		processGuid string
+		index       int
+	}{processGuid, index})
+	fake.historyMutex.Unlock()
+	if fake.HistoryStub != nil {
+		return fake.HistoryStub(processGuid, index)
+	} else {
+		return fake.historyReturns.result1
+	}
+}
+
+func (fake *FakeStore) HistoryCallCount() int {
+	fake.historyMutex.RLock()
+	defer fake.historyMutex.RUnlock()
+	return len(fake.historyArgsForCall)
+}
+
+func (fake *FakeStore) HistoryArgsForCall(i int) (string, int) {
+	fake.historyMutex.RLock()
+	defer fake.historyMutex.RUnlock()
+	return fake.historyArgsForCall[i].processGuid, fake.historyArgsForCall[i].index
+}
+
+func (fake *FakeStore) HistoryReturns(result1 []receptor.ActualLRPCrashResponse) {
+	fake.HistoryStub = nil
+	fake.historyReturns = struct {
+		result1 []receptor.ActualLRPCrashResponse
+	}{result1}
+}
+
+func (fake *FakeStore) Crashing() []receptor.CrashingActualLRPResponse {
+	fake.crashingMutex.Lock()
+	fake.crashingArgsForCall = append(fake.crashingArgsForCall, struct{}{})
+	fake.crashingMutex.Unlock()
+	if fake.CrashingStub != nil {
+		return fake.CrashingStub()
+	} else {
+		return fake.crashingReturns.result1
+	}
+}
+
+func (fake *FakeStore) CrashingCallCount() int {
+	fake.crashingMutex.RLock()
+	defer fake.crashingMutex.RUnlock()
+	return len(fake.crashingArgsForCall)
+}
+
+func (fake *FakeStore) CrashingReturns(result1 []receptor.CrashingActualLRPResponse) {
+	fake.CrashingStub = nil
+	fake.crashingReturns = struct {
+		result1 []receptor.CrashingActualLRPResponse
+	}{result1}
+}
+
+var _ crashhistory.Store = new(FakeStore)
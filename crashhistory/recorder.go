@@ -0,0 +1,119 @@
+package crashhistory
+
+import (
+	"os"
+
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/event"
+	"github.com/pivotal-golang/clock"
+	"github.com/pivotal-golang/lager"
+	"github.com/tedsuo/ifrit"
+)
+
+type recorder struct {
+	hub    event.Hub
+	store  Store
+	clock  clock.Clock
+	logger lager.Logger
+}
+
+// NewRecorder returns a runner which watches the hub for ActualLRPs whose
+// crash count goes up, and records each such crash in the store. When the
+// event stream fails, the recorder resubscribes and replays the events it
+// missed; crashes which are no longer retained by the hub are not recorded.
+func NewRecorder(hub event.Hub, store Store, clock clock.Clock, logger lager.Logger) ifrit.Runner {
+	return &recorder{
+		hub:    hub,
+		store:  store,
+		clock:  clock,
+		logger: logger.Session("crash-recorder"),
+	}
+}
+
+func (r *recorder) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	source, err := r.hub.Subscribe()
+	if err != nil {
+		return err
+	}
+
+	close(ready)
+
+	var lastEventID uint64
+	for {
+		lastEventID, err = r.record(source, lastEventID, signals)
+		if err == nil {
+			return nil
+		}
+		r.logger.Error("lost-event-stream", err)
+
+		if lastEventID == 0 {
+			source, err = r.hub.Subscribe()
+		} else {
+			source, err = r.hub.SubscribeSince(lastEventID)
+		}
+		if err != nil {
+			r.logger.Error("failed-to-resubscribe", err)
+			return err
+		}
+	}
+}
+
+// record records the crashes among the events from source until the runner
+// is signalled or the source fails. It returns the ID of the last event it
+// received, along with the error of the source if it failed.
+func (r *recorder) record(source event.Source, lastEventID uint64, signals <-chan os.Signal) (uint64, error) {
+	defer source.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+
+	messages := make(chan event.Message)
+	errs := make(chan error, 1)
+	go func() {
+		for {
+			message, err := source.Next()
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			select {
+			case messages <- message:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-signals:
+			return lastEventID, nil
+
+		case err := <-errs:
+			return lastEventID, err
+
+		case message := <-messages:
+			lastEventID = message.ID
+
+			changed, ok := message.Event.(receptor.ActualLRPChangedEvent)
+			if !ok || changed.After.CrashCount <= changed.Before.CrashCount {
+				continue
+			}
+
+			r.logger.Info("recording-crash", lager.Data{
+				"process-guid": changed.After.ProcessGuid,
+				"index":        changed.After.Index,
+				"crash-count":  changed.After.CrashCount,
+			})
+
+			r.store.Record(changed.After, receptor.ActualLRPCrashResponse{
+				InstanceGuid: changed.Before.InstanceGuid,
+				CellID:       changed.Before.CellID,
+				CrashCount:   changed.After.CrashCount,
+				CrashReason:  changed.After.CrashReason,
+				CrashedAt:    r.clock.Now().UnixNano(),
+			})
+		}
+	}
+}
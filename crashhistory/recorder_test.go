@@ -0,0 +1,150 @@
+package crashhistory_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/crashhistory"
+	"github.com/cloudfoundry-incubator/receptor/event"
+	"github.com/cloudfoundry-incubator/receptor/event/eventfakes"
+	"github.com/pivotal-golang/clock/fakeclock"
+	"github.com/pivotal-golang/lager/lagertest"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/ginkgomon"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Recorder", func() {
+	var (
+		hub       event.Hub
+		store     crashhistory.Store
+		fakeClock *fakeclock.FakeClock
+
+		running receptor.ActualLRPResponse
+
+		process ifrit.Process
+	)
+
+	history := func() []receptor.ActualLRPCrashResponse {
+		return store.History("some-guid", 1)
+	}
+
+	BeforeEach(func() {
+		hub = event.NewHub(10)
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+		store = crashhistory.NewStore(fakeClock, crashhistory.DefaultConfig())
+
+		running = receptor.ActualLRPResponse{
+			ProcessGuid:  "some-guid",
+			Index:        1,
+			InstanceGuid: "some-instance-guid",
+			CellID:       "some-cell",
+			State:        receptor.ActualLRPStateRunning,
+			CrashCount:   1,
+		}
+	})
+
+	JustBeforeEach(func() {
+		process = ginkgomon.Invoke(crashhistory.NewRecorder(hub, store, fakeClock, lagertest.NewTestLogger("test")))
+	})
+
+	AfterEach(func() {
+		ginkgomon.Interrupt(process)
+	})
+
+	It("records the crashes of actual LRPs", func() {
+		crashed := receptor.ActualLRPResponse{
+			ProcessGuid: "some-guid",
+			Index:       1,
+			State:       receptor.ActualLRPStateCrashed,
+			CrashCount:  2,
+			CrashReason: "out of memory",
+		}
+		hub.Emit(receptor.NewActualLRPChangedEvent(running, crashed))
+
+		Eventually(history).Should(Equal([]receptor.ActualLRPCrashResponse{{
+			InstanceGuid: "some-instance-guid",
+			CellID:       "some-cell",
+			CrashCount:   2,
+			CrashReason:  "out of memory",
+			CrashedAt:    fakeClock.Now().UnixNano(),
+		}}))
+	})
+
+	It("ignores changes which are not crashes", func() {
+		claimed := running
+		claimed.State = receptor.ActualLRPStateClaimed
+		hub.Emit(receptor.NewActualLRPChangedEvent(claimed, running))
+		hub.Emit(receptor.NewActualLRPRemovedEvent(running))
+
+		Consistently(history).Should(BeEmpty())
+	})
+
+	Context("when the event stream fails", func() {
+		var (
+			fakeHub *eventfakes.FakeHub
+			crashed receptor.ActualLRPResponse
+		)
+
+		BeforeEach(func() {
+			crashed = running
+			crashed.State = receptor.ActualLRPStateCrashed
+			crashed.CrashCount = 2
+
+			fakeHub = new(eventfakes.FakeHub)
+			hub = fakeHub
+
+			failedSource := new(eventfakes.FakeSource)
+			calls := 0
+			failedSource.NextStub = func() (event.Message, error) {
+				calls++
+				if calls == 1 {
+					return event.Message{ID: 5, Event: receptor.NewActualLRPRemovedEvent(running)}, nil
+				}
+				return event.Message{}, errors.New("boom")
+			}
+			fakeHub.SubscribeReturns(failedSource, nil)
+		})
+
+		Context("and resubscribing succeeds", func() {
+			BeforeEach(func() {
+				replay := new(eventfakes.FakeSource)
+				closed := make(chan struct{})
+				replay.CloseStub = func() error {
+					close(closed)
+					return nil
+				}
+				replayed := false
+				replay.NextStub = func() (event.Message, error) {
+					if !replayed {
+						replayed = true
+						return event.Message{ID: 6, Event: receptor.NewActualLRPChangedEvent(running, crashed)}, nil
+					}
+					<-closed
+					return event.Message{}, errors.New("closed")
+				}
+				fakeHub.SubscribeSinceReturns(replay, nil)
+			})
+
+			It("resubscribes after the last event it received and keeps recording", func() {
+				Eventually(history).Should(HaveLen(1))
+
+				Expect(fakeHub.SubscribeSinceCallCount()).To(Equal(1))
+				Expect(fakeHub.SubscribeSinceArgsForCall(0)).To(BeEquivalentTo(5))
+			})
+		})
+
+		Context("and resubscribing fails", func() {
+			BeforeEach(func() {
+				fakeHub.SubscribeSinceReturns(nil, errors.New("hub closed"))
+			})
+
+			It("exits with the error", func() {
+				Eventually(process.Wait()).Should(Receive(MatchError("hub closed")))
+			})
+		})
+	})
+})
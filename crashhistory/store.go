@@ -0,0 +1,178 @@
+package crashhistory
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/pivotal-golang/clock"
+)
+
+const (
+	DefaultCapacity         = 10000
+	DefaultHistoryLength    = 20
+	DefaultCrashLoopCrashes = 3
+	DefaultCrashLoopWindow  = 10 * time.Minute
+)
+
+type Config struct {
+	// Capacity is the number of ActualLRPs whose history is kept. Once it is
+	// exceeded, the history of the ActualLRP which crashed least recently is
+	// evicted.
+	Capacity int
+
+	// HistoryLength is the number of crashes kept for each ActualLRP.
+	HistoryLength int
+
+	// An ActualLRP which crashes CrashLoopCrashes times within
+	// CrashLoopWindow is considered to be in a crash loop.
+	CrashLoopCrashes int
+	CrashLoopWindow  time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Capacity:         DefaultCapacity,
+		HistoryLength:    DefaultHistoryLength,
+		CrashLoopCrashes: DefaultCrashLoopCrashes,
+		CrashLoopWindow:  DefaultCrashLoopWindow,
+	}
+}
+
+//go:generate counterfeiter -o fake_crashhistory/fake_store.go . Store
+
+// Store keeps the most recent crashes of each ActualLRP in memory. It only
+// holds the crashes recorded since the receptor started, and is not shared
+// with other receptors, which each record their own history.
+type Store interface {
+	Record(actualLRP receptor.ActualLRPResponse, crash receptor.ActualLRPCrashResponse)
+
+	// History returns the crashes of an ActualLRP, oldest first.
+	History(processGuid string, index int) []receptor.ActualLRPCrashResponse
+
+	// Crashing returns the ActualLRPs which are in a crash loop.
+	Crashing() []receptor.CrashingActualLRPResponse
+}
+
+type history struct {
+	processGuid string
+	index       int
+	domain      string
+	crashes     []receptor.ActualLRPCrashResponse
+}
+
+type store struct {
+	lock      sync.Mutex
+	clock     clock.Clock
+	config    Config
+	histories map[string]*history
+
+	// order lists the keys of histories, least recently crashed first.
+	order []string
+}
+
+func NewStore(clock clock.Clock, config Config) Store {
+	return &store{
+		clock:     clock,
+		config:    config,
+		histories: make(map[string]*history),
+	}
+}
+
+func (s *store) Record(actualLRP receptor.ActualLRPResponse, crash receptor.ActualLRPCrashResponse) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	key := historyKey(actualLRP.ProcessGuid, actualLRP.Index)
+	h, ok := s.histories[key]
+	if !ok {
+		h = &history{
+			processGuid: actualLRP.ProcessGuid,
+			index:       actualLRP.Index,
+		}
+		s.histories[key] = h
+	} else {
+		s.removeFromOrder(key)
+	}
+	s.order = append(s.order, key)
+
+	h.domain = actualLRP.Domain
+	h.crashes = append(h.crashes, crash)
+	if len(h.crashes) > s.config.HistoryLength {
+		h.crashes = h.crashes[len(h.crashes)-s.config.HistoryLength:]
+	}
+
+	if len(s.order) > s.config.Capacity {
+		delete(s.histories, s.order[0])
+		s.order = s.order[1:]
+	}
+}
+
+func (s *store) History(processGuid string, index int) []receptor.ActualLRPCrashResponse {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	crashes := []receptor.ActualLRPCrashResponse{}
+	if h, ok := s.histories[historyKey(processGuid, index)]; ok {
+		crashes = append(crashes, h.crashes...)
+	}
+	return crashes
+}
+
+func (s *store) Crashing() []receptor.CrashingActualLRPResponse {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	since := s.clock.Now().Add(-s.config.CrashLoopWindow).UnixNano()
+
+	crashing := []receptor.CrashingActualLRPResponse{}
+	for _, h := range s.histories {
+		recent := 0
+		for _, crash := range h.crashes {
+			if crash.CrashedAt >= since {
+				recent++
+			}
+		}
+
+		if recent < s.config.CrashLoopCrashes {
+			continue
+		}
+
+		crashing = append(crashing, receptor.CrashingActualLRPResponse{
+			ProcessGuid:   h.processGuid,
+			Index:         h.index,
+			Domain:        h.domain,
+			RecentCrashes: recent,
+			LastCrash:     h.crashes[len(h.crashes)-1],
+		})
+	}
+
+	sort.Sort(byProcessGuidAndIndex(crashing))
+	return crashing
+}
+
+func (s *store) removeFromOrder(key string) {
+	for i, k := range s.order {
+		if k == key {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			return
+		}
+	}
+}
+
+func historyKey(processGuid string, index int) string {
+	return fmt.Sprintf("%s/%d", processGuid, index)
+}
+
+type byProcessGuidAndIndex []receptor.CrashingActualLRPResponse
+
+func (c byProcessGuidAndIndex) Len() int      { return len(c) }
+func (c byProcessGuidAndIndex) Swap(i, j int) { c[i], c[j] = c[j], c[i] }
+func (c byProcessGuidAndIndex) Less(i, j int) bool {
+	if c[i].ProcessGuid != c[j].ProcessGuid {
+		return c[i].ProcessGuid < c[j].ProcessGuid
+	}
+	return c[i].Index < c[j].Index
+}
@@ -0,0 +1,98 @@
+package crashhistory_test
+
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/crashhistory"
+	"github.com/pivotal-golang/clock/fakeclock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Store", func() {
+	var (
+		fakeClock *fakeclock.FakeClock
+		store     crashhistory.Store
+	)
+
+	instance := func(processGuid string, index int) receptor.ActualLRPResponse {
+		return receptor.ActualLRPResponse{ProcessGuid: processGuid, Index: index, Domain: "some-domain"}
+	}
+
+	crashNow := func(crashCount int) receptor.ActualLRPCrashResponse {
+		return receptor.ActualLRPCrashResponse{
+			InstanceGuid: "some-instance-guid",
+			CrashCount:   crashCount,
+			CrashedAt:    fakeClock.Now().UnixNano(),
+		}
+	}
+
+	BeforeEach(func() {
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+		store = crashhistory.NewStore(fakeClock, crashhistory.Config{
+			Capacity:         2,
+			HistoryLength:    3,
+			CrashLoopCrashes: 2,
+			CrashLoopWindow:  time.Minute,
+		})
+	})
+
+	Describe("History", func() {
+		It("returns the crashes of the instance, oldest first", func() {
+			store.Record(instance("some-guid", 0), crashNow(1))
+			store.Record(instance("some-guid", 1), crashNow(1))
+			store.Record(instance("some-guid", 0), crashNow(2))
+
+			Expect(store.History("some-guid", 0)).To(Equal([]receptor.ActualLRPCrashResponse{crashNow(1), crashNow(2)}))
+		})
+
+		It("keeps only the most recent crashes", func() {
+			for i := 1; i <= 5; i++ {
+				store.Record(instance("some-guid", 0), crashNow(i))
+			}
+
+			Expect(store.History("some-guid", 0)).To(Equal([]receptor.ActualLRPCrashResponse{crashNow(3), crashNow(4), crashNow(5)}))
+		})
+
+		It("returns an empty history for an instance which has not crashed", func() {
+			Expect(store.History("some-guid", 0)).To(BeEmpty())
+		})
+
+		It("evicts the instance which crashed least recently once over capacity", func() {
+			store.Record(instance("guid-a", 0), crashNow(1))
+			store.Record(instance("guid-b", 0), crashNow(1))
+			store.Record(instance("guid-a", 0), crashNow(2))
+			store.Record(instance("guid-c", 0), crashNow(1))
+
+			Expect(store.History("guid-a", 0)).To(HaveLen(2))
+			Expect(store.History("guid-b", 0)).To(BeEmpty())
+			Expect(store.History("guid-c", 0)).To(HaveLen(1))
+		})
+	})
+
+	Describe("Crashing", func() {
+		It("returns the instances which crashed often enough within the window", func() {
+			store.Record(instance("guid-b", 0), crashNow(1))
+			store.Record(instance("guid-b", 0), crashNow(2))
+			store.Record(instance("guid-a", 3), crashNow(1))
+
+			Expect(store.Crashing()).To(Equal([]receptor.CrashingActualLRPResponse{{
+				ProcessGuid:   "guid-b",
+				Index:         0,
+				Domain:        "some-domain",
+				RecentCrashes: 2,
+				LastCrash:     crashNow(2),
+			}}))
+		})
+
+		It("disregards crashes from before the window", func() {
+			store.Record(instance("some-guid", 0), crashNow(1))
+			fakeClock.Increment(2 * time.Minute)
+			store.Record(instance("some-guid", 0), crashNow(2))
+
+			Expect(store.Crashing()).To(BeEmpty())
+		})
+	})
+})
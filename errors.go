@@ -1,23 +1,55 @@
 package receptor
 
+// Error is the body of every unsuccessful response. FieldErrors is only
+// present when a request failed validation, and identifies the offending
+// fields.
 type Error struct {
-	Type    string `json:"name"`
-	Message string `json:"message"`
+	Type        string       `json:"name"`
+	Message     string       `json:"message"`
+	FieldErrors []FieldError `json:"field_errors,omitempty"`
 }
 
 func (err Error) Error() string {
 	return err.Message
 }
 
+// FieldError describes why a single field of a request failed validation.
+// Field is the JSON path of the field, e.g. "env[0].name".
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+const (
+	FieldRequired   = "required"
+	FieldInvalid    = "invalid"
+	FieldOutOfRange = "out_of_range"
+	FieldDuplicate  = "duplicate"
+)
+
 const (
 	TaskGuidAlreadyExists = "TaskGuidAlreadyExists"
 	TaskNotDeletable      = "TaskNotDeletable"
 	TaskNotFound          = "TaskNotFound"
 	InvalidTask           = "InvalidTask"
+	TaskNotCompleted      = "TaskNotCompleted"
+	TaskNotRetryable      = "TaskNotRetryable"
+	TaskResultTooLarge    = "TaskResultTooLarge"
+
+	CallbackDeliveryNotFound = "CallbackDeliveryNotFound"
 
 	DesiredLRPAlreadyExists = "DesiredLRPAlreadyExists"
 	DesiredLRPNotFound      = "DesiredLRPNotFound"
 	InvalidLRP              = "InvalidLRP"
+	DesiredLRPNotStopped    = "DesiredLRPNotStopped"
+
+	RollingUpdateInProgress   = "RollingUpdateInProgress"
+	RollingUpdateNotFound     = "RollingUpdateNotFound"
+	RollingUpdateNotAbortable = "RollingUpdateNotAbortable"
+
+	AutoscalingPolicyNotFound = "AutoscalingPolicyNotFound"
+	InvalidAutoscalingPolicy  = "InvalidAutoscalingPolicy"
 
 	InvalidDomain = "InvalidDomain"
 
@@ -28,8 +60,12 @@ const (
 	UnknownError = "UnknownError"
 	Unauthorized = "Unauthorized"
 
-	ActualLRPIndexNotFound = "ActualLRPIndexNotFound"
+	ActualLRPIndexNotFound    = "ActualLRPIndexNotFound"
+	ActualLRPInstanceNotFound = "ActualLRPInstanceNotFound"
+	ActualLRPRestartTimedOut  = "ActualLRPRestartTimedOut"
+	ActualLRPRestartCancelled = "ActualLRPRestartCancelled"
 
-	ResourceConflict = "ResourceConflict"
-	RouterError      = "RouterError"
+	ResourceConflict   = "ResourceConflict"
+	PreconditionFailed = "PreconditionFailed"
+	RouterError        = "RouterError"
 )
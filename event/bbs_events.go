@@ -0,0 +1,46 @@
+package event
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/bbs/models"
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/serialization"
+)
+
+func NewEventFromBBS(bbsEvent models.Event) (receptor.Event, error) {
+	switch bbsEvent := bbsEvent.(type) {
+	case *models.ActualLRPCreatedEvent:
+		actualLRP, evacuating := bbsEvent.ActualLrpGroup.Resolve()
+		return receptor.NewActualLRPCreatedEvent(serialization.ActualLRPProtoToResponse(actualLRP, evacuating)), nil
+	case *models.ActualLRPChangedEvent:
+		before, evacuating := bbsEvent.Before.Resolve()
+		after, evacuating := bbsEvent.After.Resolve()
+		return receptor.NewActualLRPChangedEvent(
+			serialization.ActualLRPProtoToResponse(before, evacuating),
+			serialization.ActualLRPProtoToResponse(after, evacuating),
+		), nil
+	case *models.ActualLRPRemovedEvent:
+		actualLRP, evacuating := bbsEvent.ActualLrpGroup.Resolve()
+		return receptor.NewActualLRPRemovedEvent(serialization.ActualLRPProtoToResponse(actualLRP, evacuating)), nil
+	case *models.DesiredLRPCreatedEvent:
+		return receptor.NewDesiredLRPCreatedEvent(serialization.DesiredLRPProtoToResponse(bbsEvent.DesiredLrp)), nil
+	case *models.DesiredLRPChangedEvent:
+		return receptor.NewDesiredLRPChangedEvent(
+			serialization.DesiredLRPProtoToResponse(bbsEvent.Before),
+			serialization.DesiredLRPProtoToResponse(bbsEvent.After),
+		), nil
+	case *models.DesiredLRPRemovedEvent:
+		return receptor.NewDesiredLRPRemovedEvent(serialization.DesiredLRPProtoToResponse(bbsEvent.DesiredLrp)), nil
+	case *models.TaskCreatedEvent:
+		return receptor.NewTaskCreatedEvent(serialization.TaskToResponse(bbsEvent.Task)), nil
+	case *models.TaskChangedEvent:
+		return receptor.NewTaskChangedEvent(
+			serialization.TaskToResponse(bbsEvent.Before),
+			serialization.TaskToResponse(bbsEvent.After),
+		), nil
+	case *models.TaskRemovedEvent:
+		return receptor.NewTaskRemovedEvent(serialization.TaskToResponse(bbsEvent.Task)), nil
+	}
+	return nil, fmt.Errorf("unknown event type: %#v", bbsEvent)
+}
@@ -0,0 +1,102 @@
+package event
+
+import (
+	"os"
+	"time"
+
+	"github.com/cloudfoundry-incubator/bbs"
+	"github.com/cloudfoundry-incubator/bbs/events"
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/pivotal-golang/clock"
+	"github.com/pivotal-golang/lager"
+	"github.com/tedsuo/ifrit"
+)
+
+const DefaultResubscribeInterval = time.Second
+
+type bbsRelay struct {
+	bbs                 bbs.Client
+	hub                 Hub
+	clock               clock.Clock
+	resubscribeInterval time.Duration
+	logger              lager.Logger
+}
+
+// NewBBSRelay returns a runner which subscribes to the BBS event stream and
+// emits every event it receives into the hub. When the BBS subscription is
+// lost it resubscribes and emits a ResyncRequiredEvent, as any events from the
+// gap can not be replayed. The hub is closed when the runner exits.
+func NewBBSRelay(bbs bbs.Client, hub Hub, clock clock.Clock, resubscribeInterval time.Duration, logger lager.Logger) ifrit.Runner {
+	return &bbsRelay{
+		bbs:                 bbs,
+		hub:                 hub,
+		clock:               clock,
+		resubscribeInterval: resubscribeInterval,
+		logger:              logger.Session("bbs-relay"),
+	}
+}
+
+func (r *bbsRelay) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	defer r.hub.Close()
+
+	close(ready)
+
+	subscribedBefore := false
+	for {
+		source, err := r.bbs.SubscribeToEvents()
+		if err != nil {
+			r.logger.Error("failed-to-subscribe-to-events", err)
+
+			timer := r.clock.NewTimer(r.resubscribeInterval)
+			select {
+			case <-signals:
+				timer.Stop()
+				return nil
+			case <-timer.C():
+				continue
+			}
+		}
+
+		if subscribedBefore {
+			r.hub.Emit(receptor.NewResyncRequiredEvent())
+		}
+		subscribedBefore = true
+
+		if r.relay(source, signals) {
+			return nil
+		}
+	}
+}
+
+// relay emits events from source until it fails or the runner is signalled,
+// returning true in the latter case.
+func (r *bbsRelay) relay(source events.EventSource, signals <-chan os.Signal) bool {
+	defer source.Close()
+
+	errChan := make(chan error, 1)
+	go func() {
+		for {
+			bbsEvent, err := source.Next()
+			if err != nil {
+				errChan <- err
+				return
+			}
+
+			event, err := NewEventFromBBS(bbsEvent)
+			if err != nil {
+				r.logger.Error("failed-to-convert-event", err)
+				continue
+			}
+
+			r.hub.Emit(event)
+		}
+	}()
+
+	select {
+	case <-signals:
+		return true
+	case err := <-errChan:
+		r.logger.Error("failed-to-get-next-event", err)
+		return false
+	}
+}
@@ -0,0 +1,138 @@
+package event_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/bbs/events"
+	"github.com/cloudfoundry-incubator/bbs/events/eventfakes"
+	"github.com/cloudfoundry-incubator/bbs/fake_bbs"
+	"github.com/cloudfoundry-incubator/bbs/models"
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/event"
+	"github.com/cloudfoundry-incubator/receptor/serialization"
+	"github.com/pivotal-golang/clock/fakeclock"
+	"github.com/pivotal-golang/lager/lagertest"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/ginkgomon"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BBSRelay", func() {
+	var (
+		fakeBBS   *fake_bbs.FakeClient
+		fakeClock *fakeclock.FakeClock
+		hub       event.Hub
+		source    event.Source
+
+		bbsSource    *eventfakes.FakeEventSource
+		eventChannel chan models.Event
+
+		process ifrit.Process
+	)
+
+	BeforeEach(func() {
+		fakeBBS = new(fake_bbs.FakeClient)
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+		hub = event.NewHub(10)
+
+		events := make(chan models.Event)
+		eventChannel = events
+		bbsSource = new(eventfakes.FakeEventSource)
+		bbsSource.NextStub = func() (models.Event, error) {
+			event, ok := <-events
+			if !ok {
+				return nil, errors.New("closed")
+			}
+			return event, nil
+		}
+		fakeBBS.SubscribeToEventsReturns(bbsSource, nil)
+
+		var err error
+		source, err = hub.Subscribe()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		process = ginkgomon.Invoke(event.NewBBSRelay(fakeBBS, hub, fakeClock, time.Second, lagertest.NewTestLogger("test")))
+	})
+
+	AfterEach(func() {
+		ginkgomon.Interrupt(process)
+	})
+
+	It("emits bbs events into the hub", func() {
+		desiredLRP := &models.DesiredLRP{ProcessGuid: "some-guid", Domain: "some-domain"}
+		eventChannel <- models.NewDesiredLRPCreatedEvent(desiredLRP)
+
+		message, err := source.Next()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(message.Event).To(Equal(receptor.NewDesiredLRPCreatedEvent(serialization.DesiredLRPProtoToResponse(desiredLRP))))
+	})
+
+	It("skips events it can not convert", func() {
+		eventChannel <- eventfakes.FakeEvent{"B"}
+
+		desiredLRP := &models.DesiredLRP{ProcessGuid: "some-guid", Domain: "some-domain"}
+		eventChannel <- models.NewDesiredLRPRemovedEvent(desiredLRP)
+
+		message, err := source.Next()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(message.Event.EventType()).To(Equal(receptor.EventTypeDesiredLRPRemoved))
+	})
+
+	It("closes the hub when signalled", func() {
+		ginkgomon.Interrupt(process)
+
+		_, err := source.Next()
+		Expect(err).To(Equal(receptor.ErrReadFromClosedSource))
+	})
+
+	Context("when the bbs subscription is lost", func() {
+		BeforeEach(func() {
+			blockingSource := new(eventfakes.FakeEventSource)
+			blockingSource.NextStub = func() (models.Event, error) {
+				select {}
+			}
+
+			sources := []events.EventSource{bbsSource, blockingSource}
+			fakeBBS.SubscribeToEventsStub = func() (events.EventSource, error) {
+				source := sources[0]
+				sources = sources[1:]
+				return source, nil
+			}
+		})
+
+		JustBeforeEach(func() {
+			Eventually(fakeBBS.SubscribeToEventsCallCount).Should(Equal(1))
+			close(eventChannel)
+		})
+
+		It("resubscribes and emits a resync required event", func() {
+			Eventually(fakeBBS.SubscribeToEventsCallCount).Should(Equal(2))
+			Expect(bbsSource.CloseCallCount()).To(Equal(1))
+
+			message, err := source.Next()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(message.Event).To(Equal(receptor.NewResyncRequiredEvent()))
+		})
+	})
+
+	Context("when subscribing to the bbs fails", func() {
+		BeforeEach(func() {
+			fakeBBS.SubscribeToEventsReturns(nil, models.ErrUnknownError)
+		})
+
+		It("retries after the resubscribe interval", func() {
+			Eventually(fakeBBS.SubscribeToEventsCallCount).Should(Equal(1))
+			Consistently(fakeBBS.SubscribeToEventsCallCount).Should(Equal(1))
+
+			Eventually(func() int {
+				fakeClock.Increment(time.Second)
+				return fakeBBS.SubscribeToEventsCallCount()
+			}).Should(BeNumerically(">=", 2))
+		})
+	})
+})
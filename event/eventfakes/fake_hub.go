@@ -0,0 +1,208 @@
+// This file was generated by counterfeiter
+package eventfakes
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/event"
+)
+
+type FakeHub struct {
+	EmitStub        func(receptor.Event)
+	emitMutex       sync.RWMutex
+	emitArgsForCall []struct {
+		arg1 receptor.Event
+	}
+	SubscribeStub        func() (event.Source, error)
+	subscribeMutex       sync.RWMutex
+	subscribeArgsForCall []struct{}
+	subscribeReturns     struct {
+		result1 event.Source
+		result2 error
+	}
+	SubscribeSinceStub        func(lastEventID uint64) (event.Source, error)
+	subscribeSinceMutex       sync.RWMutex
+	subscribeSinceArgsForCall []struct {
+		lastEventID uint64
+	}
+	subscribeSinceReturns struct {
+		result1 event.Source
+		result2 error
+	}
+	SubscribeWithResyncStub        func() (event.Source, error)
+	subscribeWithResyncMutex       sync.RWMutex
+	subscribeWithResyncArgsForCall []struct{}
+	subscribeWithResyncReturns     struct {
+		result1 event.Source
+		result2 error
+	}
+	EpochStub        func() string
+	epochMutex       sync.RWMutex
+	epochArgsForCall []struct{}
+	epochReturns     struct {
+		result1 string
+	}
+	CloseStub        func() error
+	closeMutex       sync.RWMutex
+	closeArgsForCall []struct{}
+	closeReturns     struct {
+		result1 error
+	}
+}
+
+func (fake *FakeHub) Emit(arg1 receptor.Event) {
+	fake.emitMutex.Lock()
+	fake.emitArgsForCall = append(fake.emitArgsForCall, struct {
+		arg1 receptor.Event
+	}{arg1})
+	fake.emitMutex.Unlock()
+	if fake.EmitStub != nil {
+		fake.EmitStub(arg1)
+	}
+}
+
+func (fake *FakeHub) EmitCallCount() int {
+	fake.emitMutex.RLock()
+	defer fake.emitMutex.RUnlock()
+	return len(fake.emitArgsForCall)
+}
+
+func (fake *FakeHub) EmitArgsForCall(i int) receptor.Event {
+	fake.emitMutex.RLock()
+	defer fake.emitMutex.RUnlock()
+	return fake.emitArgsForCall[i].arg1
+}
+
+func (fake *FakeHub) Subscribe() (event.Source, error) {
+	fake.subscribeMutex.Lock()
+	fake.subscribeArgsForCall = append(fake.subscribeArgsForCall, struct{}{})
+	fake.subscribeMutex.Unlock()
+	if fake.SubscribeStub != nil {
+		return fake.SubscribeStub()
+	} else {
+		return fake.subscribeReturns.result1, fake.subscribeReturns.result2
+	}
+}
+
+func (fake *FakeHub) SubscribeCallCount() int {
+	fake.subscribeMutex.RLock()
+	defer fake.subscribeMutex.RUnlock()
+	return len(fake.subscribeArgsForCall)
+}
+
+func (fake *FakeHub) SubscribeReturns(result1 event.Source, result2 error) {
+	fake.SubscribeStub = nil
+	fake.subscribeReturns = struct {
+		result1 event.Source
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeHub) SubscribeSince(lastEventID uint64) (event.Source, error) {
+	fake.subscribeSinceMutex.Lock()
+	fake.subscribeSinceArgsForCall = append(fake.subscribeSinceArgsForCall, struct {
+		lastEventID uint64
+	}{lastEventID})
+	fake.subscribeSinceMutex.Unlock()
+	if fake.SubscribeSinceStub != nil {
+		return fake.SubscribeSinceStub(lastEventID)
+	} else {
+		return fake.subscribeSinceReturns.result1, fake.subscribeSinceReturns.result2
+	}
+}
+
+func (fake *FakeHub) SubscribeSinceCallCount() int {
+	fake.subscribeSinceMutex.RLock()
+	defer fake.subscribeSinceMutex.RUnlock()
+	return len(fake.subscribeSinceArgsForCall)
+}
+
+func (fake *FakeHub) SubscribeSinceArgsForCall(i int) uint64 {
+	fake.subscribeSinceMutex.RLock()
+	defer fake.subscribeSinceMutex.RUnlock()
+	return fake.subscribeSinceArgsForCall[i].lastEventID
+}
+
+func (fake *FakeHub) SubscribeSinceReturns(result1 event.Source, result2 error) {
+	fake.SubscribeSinceStub = nil
+	fake.subscribeSinceReturns = struct {
+		result1 event.Source
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeHub) SubscribeWithResync() (event.Source, error) {
+	fake.subscribeWithResyncMutex.Lock()
+	fake.subscribeWithResyncArgsForCall = append(fake.subscribeWithResyncArgsForCall, struct{}{})
+	fake.subscribeWithResyncMutex.Unlock()
+	if fake.SubscribeWithResyncStub != nil {
+		return fake.SubscribeWithResyncStub()
+	} else {
+		return fake.subscribeWithResyncReturns.result1, fake.subscribeWithResyncReturns.result2
+	}
+}
+
+func (fake *FakeHub) SubscribeWithResyncCallCount() int {
+	fake.subscribeWithResyncMutex.RLock()
+	defer fake.subscribeWithResyncMutex.RUnlock()
+	return len(fake.subscribeWithResyncArgsForCall)
+}
+
+func (fake *FakeHub) SubscribeWithResyncReturns(result1 event.Source, result2 error) {
+	fake.SubscribeWithResyncStub = nil
+	fake.subscribeWithResyncReturns = struct {
+		result1 event.Source
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeHub) Epoch() string {
+	fake.epochMutex.Lock()
+	fake.epochArgsForCall = append(fake.epochArgsForCall, struct{}{})
+	fake.epochMutex.Unlock()
+	if fake.EpochStub != nil {
+		return fake.EpochStub()
+	} else {
+		return fake.epochReturns.result1
+	}
+}
+
+func (fake *FakeHub) EpochCallCount() int {
+	fake.epochMutex.RLock()
+	defer fake.epochMutex.RUnlock()
+	return len(fake.epochArgsForCall)
+}
+
+func (fake *FakeHub) EpochReturns(result1 string) {
+	fake.EpochStub = nil
+	fake.epochReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *FakeHub) Close() error {
+	fake.closeMutex.Lock()
+	fake.closeArgsForCall = append(fake.closeArgsForCall, struct{}{})
+	fake.closeMutex.Unlock()
+	if fake.CloseStub != nil {
+		return fake.CloseStub()
+	} else {
+		return fake.closeReturns.result1
+	}
+}
+
+func (fake *FakeHub) CloseCallCount() int {
+	fake.closeMutex.RLock()
+	defer fake.closeMutex.RUnlock()
+	return len(fake.closeArgsForCall)
+}
+
+func (fake *FakeHub) CloseReturns(result1 error) {
+	fake.CloseStub = nil
+	fake.closeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+var _ event.Hub = new(FakeHub)
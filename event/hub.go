@@ -0,0 +1,250 @@
+package event
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cloudfoundry-incubator/receptor"
+)
+
+const subscriberBufferSize = 1024
+
+// Message is an event together with the ID the hub assigned to it. IDs start
+// at 1 and are monotonically increasing across all subscribers of a hub.
+type Message struct {
+	ID    uint64
+	Event receptor.Event
+}
+
+//go:generate counterfeiter -o eventfakes/fake_hub.go . Hub
+
+// Hub fans events out to its subscribers and retains the most recent ones so
+// that a subscriber which reconnects can replay whatever it missed.
+type Hub interface {
+	Emit(receptor.Event)
+
+	// Subscribe returns a source of the events emitted from now on.
+	Subscribe() (Source, error)
+
+	// SubscribeSince returns a source which first replays every retained event
+	// with an ID greater than lastEventID. If those events are no longer
+	// retained, the source instead starts with a ResyncRequiredEvent.
+	SubscribeSince(lastEventID uint64) (Source, error)
+
+	// SubscribeWithResync returns a source which starts with a
+	// ResyncRequiredEvent, for subscribers whose last event ID was assigned
+	// by another hub.
+	SubscribeWithResync() (Source, error)
+
+	// Epoch identifies the hub. Event IDs are only meaningful to the hub
+	// which assigned them, so a new hub, e.g. in a restarted receptor, has a
+	// new epoch.
+	Epoch() string
+
+	Close() error
+}
+
+//go:generate counterfeiter -o eventfakes/fake_source.go . Source
+
+type Source interface {
+	Next() (Message, error)
+	Close() error
+}
+
+type hub struct {
+	epoch string
+
+	lock        sync.Mutex
+	subscribers map[*hubSource]struct{}
+	closed      bool
+
+	nextID   uint64
+	buffer   []Message
+	start    int
+	buffered int
+}
+
+func NewHub(replayBufferSize int) Hub {
+	return &hub{
+		epoch:       newEpoch(),
+		subscribers: make(map[*hubSource]struct{}),
+		nextID:      1,
+		buffer:      make([]Message, replayBufferSize),
+	}
+}
+
+func (h *hub) Emit(event receptor.Event) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.closed {
+		return
+	}
+
+	message := Message{ID: h.nextID, Event: event}
+	h.nextID++
+	h.retain(message)
+
+	for source := range h.subscribers {
+		select {
+		case source.messages <- message:
+		default:
+			source.closeWithError(receptor.ErrSlowConsumer)
+			delete(h.subscribers, source)
+		}
+	}
+}
+
+func (h *hub) Subscribe() (Source, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.closed {
+		return nil, receptor.ErrSubscribedToClosedHub
+	}
+
+	return h.subscribe(nil), nil
+}
+
+func (h *hub) SubscribeSince(lastEventID uint64) (Source, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.closed {
+		return nil, receptor.ErrSubscribedToClosedHub
+	}
+
+	return h.subscribe(h.replaySince(lastEventID)), nil
+}
+
+func (h *hub) SubscribeWithResync() (Source, error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.closed {
+		return nil, receptor.ErrSubscribedToClosedHub
+	}
+
+	return h.subscribe([]Message{{ID: h.nextID - 1, Event: receptor.NewResyncRequiredEvent()}}), nil
+}
+
+func (h *hub) Epoch() string {
+	return h.epoch
+}
+
+func (h *hub) Close() error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.closed {
+		return receptor.ErrHubAlreadyClosed
+	}
+
+	h.closed = true
+	for source := range h.subscribers {
+		source.closeWithError(receptor.ErrReadFromClosedSource)
+		delete(h.subscribers, source)
+	}
+
+	return nil
+}
+
+func (h *hub) subscribe(replay []Message) *hubSource {
+	source := &hubSource{
+		hub:      h,
+		messages: make(chan Message, subscriberBufferSize+len(replay)),
+	}
+
+	for _, message := range replay {
+		source.messages <- message
+	}
+
+	h.subscribers[source] = struct{}{}
+	return source
+}
+
+func (h *hub) unsubscribe(source *hubSource) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if _, ok := h.subscribers[source]; ok {
+		source.closeWithError(receptor.ErrReadFromClosedSource)
+		delete(h.subscribers, source)
+	}
+}
+
+func (h *hub) retain(message Message) {
+	if len(h.buffer) == 0 {
+		return
+	}
+
+	if h.buffered < len(h.buffer) {
+		h.buffer[(h.start+h.buffered)%len(h.buffer)] = message
+		h.buffered++
+		return
+	}
+
+	h.buffer[h.start] = message
+	h.start = (h.start + 1) % len(h.buffer)
+}
+
+func newEpoch() string {
+	epoch := make([]byte, 8)
+	_, err := rand.Read(epoch)
+	if err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(epoch)
+}
+
+// replaySince must be called with the lock held.
+func (h *hub) replaySince(lastEventID uint64) []Message {
+	if lastEventID+1 == h.nextID {
+		return nil
+	}
+
+	oldestID := h.nextID - uint64(h.buffered)
+	if lastEventID >= h.nextID || lastEventID+1 < oldestID {
+		return []Message{{ID: h.nextID - 1, Event: receptor.NewResyncRequiredEvent()}}
+	}
+
+	replay := make([]Message, 0, h.nextID-lastEventID-1)
+	for i := 0; i < h.buffered; i++ {
+		message := h.buffer[(h.start+i)%len(h.buffer)]
+		if message.ID > lastEventID {
+			replay = append(replay, message)
+		}
+	}
+
+	return replay
+}
+
+type hubSource struct {
+	hub      *hub
+	messages chan Message
+
+	// err is set by the hub, with its lock held, before messages is closed.
+	err error
+}
+
+func (s *hubSource) Next() (Message, error) {
+	message, ok := <-s.messages
+	if !ok {
+		return Message{}, s.err
+	}
+
+	return message, nil
+}
+
+func (s *hubSource) Close() error {
+	s.hub.unsubscribe(s)
+	return nil
+}
+
+func (s *hubSource) closeWithError(err error) {
+	s.err = err
+	close(s.messages)
+}
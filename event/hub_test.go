@@ -0,0 +1,214 @@
+package event_test
+
+import (
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/event"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Hub", func() {
+	var hub event.Hub
+
+	newEvent := func(processGuid string) receptor.Event {
+		return receptor.NewDesiredLRPCreatedEvent(receptor.DesiredLRPResponse{ProcessGuid: processGuid})
+	}
+
+	BeforeEach(func() {
+		hub = event.NewHub(3)
+	})
+
+	Describe("Subscribe", func() {
+		It("delivers events emitted after subscribing with increasing IDs", func() {
+			hub.Emit(newEvent("before"))
+
+			source, err := hub.Subscribe()
+			Expect(err).NotTo(HaveOccurred())
+
+			hub.Emit(newEvent("first"))
+			hub.Emit(newEvent("second"))
+
+			message, err := source.Next()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(message).To(Equal(event.Message{ID: 2, Event: newEvent("first")}))
+
+			message, err = source.Next()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(message).To(Equal(event.Message{ID: 3, Event: newEvent("second")}))
+		})
+
+		It("delivers each event to every subscriber", func() {
+			sourceA, err := hub.Subscribe()
+			Expect(err).NotTo(HaveOccurred())
+			sourceB, err := hub.Subscribe()
+			Expect(err).NotTo(HaveOccurred())
+
+			hub.Emit(newEvent("some-guid"))
+
+			messageA, err := sourceA.Next()
+			Expect(err).NotTo(HaveOccurred())
+			messageB, err := sourceB.Next()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messageA).To(Equal(messageB))
+		})
+
+		Context("when the hub is closed", func() {
+			BeforeEach(func() {
+				Expect(hub.Close()).To(Succeed())
+			})
+
+			It("returns an error", func() {
+				_, err := hub.Subscribe()
+				Expect(err).To(Equal(receptor.ErrSubscribedToClosedHub))
+			})
+		})
+	})
+
+	Describe("SubscribeSince", func() {
+		BeforeEach(func() {
+			for _, guid := range []string{"1", "2", "3", "4", "5"} {
+				hub.Emit(newEvent(guid))
+			}
+		})
+
+		Context("when the missed events are retained", func() {
+			It("replays them before delivering new events", func() {
+				source, err := hub.SubscribeSince(3)
+				Expect(err).NotTo(HaveOccurred())
+
+				hub.Emit(newEvent("6"))
+
+				for _, id := range []uint64{4, 5, 6} {
+					message, err := source.Next()
+					Expect(err).NotTo(HaveOccurred())
+					Expect(message.ID).To(Equal(id))
+				}
+			})
+		})
+
+		Context("when nothing was missed", func() {
+			It("only delivers new events", func() {
+				source, err := hub.SubscribeSince(5)
+				Expect(err).NotTo(HaveOccurred())
+
+				hub.Emit(newEvent("6"))
+
+				message, err := source.Next()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(message.ID).To(BeEquivalentTo(6))
+			})
+		})
+
+		Context("when the missed events are no longer retained", func() {
+			It("starts with a resync required event", func() {
+				source, err := hub.SubscribeSince(1)
+				Expect(err).NotTo(HaveOccurred())
+
+				message, err := source.Next()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(message).To(Equal(event.Message{ID: 5, Event: receptor.NewResyncRequiredEvent()}))
+			})
+		})
+
+		Context("when the event ID is from the future", func() {
+			It("starts with a resync required event", func() {
+				source, err := hub.SubscribeSince(42)
+				Expect(err).NotTo(HaveOccurred())
+
+				message, err := source.Next()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(message.Event).To(Equal(receptor.NewResyncRequiredEvent()))
+			})
+		})
+	})
+
+	Describe("SubscribeWithResync", func() {
+		BeforeEach(func() {
+			hub.Emit(newEvent("1"))
+			hub.Emit(newEvent("2"))
+		})
+
+		It("starts with a resync required event carrying the ID of the last event", func() {
+			source, err := hub.SubscribeWithResync()
+			Expect(err).NotTo(HaveOccurred())
+
+			hub.Emit(newEvent("3"))
+
+			message, err := source.Next()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(message).To(Equal(event.Message{ID: 2, Event: receptor.NewResyncRequiredEvent()}))
+
+			message, err = source.Next()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(message.ID).To(BeEquivalentTo(3))
+		})
+
+		Context("when the hub is closed", func() {
+			BeforeEach(func() {
+				Expect(hub.Close()).To(Succeed())
+			})
+
+			It("returns an error", func() {
+				_, err := hub.SubscribeWithResync()
+				Expect(err).To(Equal(receptor.ErrSubscribedToClosedHub))
+			})
+		})
+	})
+
+	Describe("Epoch", func() {
+		It("differs between hubs", func() {
+			Expect(hub.Epoch()).NotTo(BeEmpty())
+			Expect(hub.Epoch()).NotTo(Equal(event.NewHub(5).Epoch()))
+		})
+	})
+
+	Describe("Close", func() {
+		It("closes all subscribers", func() {
+			source, err := hub.Subscribe()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(hub.Close()).To(Succeed())
+
+			_, err = source.Next()
+			Expect(err).To(Equal(receptor.ErrReadFromClosedSource))
+		})
+
+		It("errors when already closed", func() {
+			Expect(hub.Close()).To(Succeed())
+			Expect(hub.Close()).To(Equal(receptor.ErrHubAlreadyClosed))
+		})
+	})
+
+	Describe("closing a source", func() {
+		It("stops delivering events to it", func() {
+			source, err := hub.Subscribe()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(source.Close()).To(Succeed())
+			hub.Emit(newEvent("some-guid"))
+
+			_, err = source.Next()
+			Expect(err).To(Equal(receptor.ErrReadFromClosedSource))
+		})
+	})
+
+	Context("when a subscriber does not keep up", func() {
+		It("closes it with a slow consumer error", func() {
+			source, err := hub.Subscribe()
+			Expect(err).NotTo(HaveOccurred())
+
+			for i := 0; i < 2048; i++ {
+				hub.Emit(newEvent("some-guid"))
+			}
+
+			for {
+				_, err = source.Next()
+				if err != nil {
+					break
+				}
+			}
+			Expect(err).To(Equal(receptor.ErrSlowConsumer))
+		})
+	})
+})
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sync"
+	"time"
 
 	"github.com/vito/go-sse/sse"
 )
@@ -54,7 +56,10 @@ func (e closeError) Error() string {
 // EventSource provides sequential access to a stream of events.
 type EventSource interface {
 	// Next reads the next event from the source. If the connection is lost, it
-	// automatically reconnects.
+	// automatically reconnects and resumes after the last event it received.
+	// When the receptor can no longer replay the missed events, a
+	// ResyncRequiredEvent is returned instead. Heartbeats are consumed and
+	// never returned.
 	//
 	// If the end of the stream is reached cleanly (which should actually never
 	// happen), io.EOF is returned. If called after or during Close,
@@ -73,8 +78,22 @@ type RawEventSource interface {
 	Close() error
 }
 
+// missedHeartbeatsBeforeReconnect is the number of heartbeat intervals an
+// event source waits for any event before it considers the connection dead.
+const missedHeartbeatsBeforeReconnect = 3
+
+var errHeartbeatMissed = errors.New("heartbeat missed")
+
+// Reconnector opens a new raw event source that resumes after lastEventID.
+type Reconnector func(lastEventID string) (RawEventSource, error)
+
 type eventSource struct {
-	rawEventSource RawEventSource
+	lock             sync.Mutex
+	rawEventSource   RawEventSource
+	reconnect        Reconnector
+	lastEventID      string
+	heartbeatTimeout time.Duration
+	closed           bool
 }
 
 func NewEventSource(raw RawEventSource) EventSource {
@@ -83,26 +102,80 @@ func NewEventSource(raw RawEventSource) EventSource {
 	}
 }
 
-func (e *eventSource) Next() (Event, error) {
-	rawEvent, err := e.rawEventSource.Next()
-	if err != nil {
-		switch err {
-		case io.EOF:
-			return nil, err
+// NewEventSourceWithReconnect returns an event source which, once the
+// receptor has announced its heartbeat interval, treats a connection that
+// stays silent for several intervals as dead and replaces it using reconnect.
+func NewEventSourceWithReconnect(raw RawEventSource, reconnect Reconnector) EventSource {
+	return &eventSource{
+		rawEventSource: raw,
+		reconnect:      reconnect,
+	}
+}
 
-		case sse.ErrSourceClosed:
+func (e *eventSource) Next() (Event, error) {
+	for {
+		e.lock.Lock()
+		if e.closed {
+			e.lock.Unlock()
 			return nil, ErrSourceClosed
+		}
+		raw := e.rawEventSource
+		heartbeatTimeout := e.heartbeatTimeout
+		e.lock.Unlock()
+
+		rawEvent, err := nextWithTimeout(raw, heartbeatTimeout)
+		if err != nil {
+			switch err {
+			case errHeartbeatMissed:
+				err = e.replace(raw)
+				if err != nil {
+					return nil, err
+				}
+				continue
+
+			case io.EOF:
+				return nil, err
+
+			case sse.ErrSourceClosed:
+				return nil, ErrSourceClosed
+
+			default:
+				return nil, NewRawEventSourceError(err)
+			}
+		}
 
-		default:
-			return nil, NewRawEventSourceError(err)
+		if rawEvent.ID != "" {
+			e.lock.Lock()
+			e.lastEventID = rawEvent.ID
+			e.lock.Unlock()
+		}
+
+		if EventType(rawEvent.Name) == EventTypeHeartbeat {
+			var heartbeat HeartbeatEvent
+			err := json.Unmarshal(rawEvent.Data, &heartbeat)
+			if err != nil {
+				return nil, NewInvalidPayloadError(err)
+			}
+
+			if e.reconnect != nil {
+				e.lock.Lock()
+				e.heartbeatTimeout = missedHeartbeatsBeforeReconnect * heartbeat.Interval()
+				e.lock.Unlock()
+			}
+			continue
 		}
-	}
 
-	return parseRawEvent(rawEvent)
+		return parseRawEvent(rawEvent)
+	}
 }
 
 func (e *eventSource) Close() error {
-	err := e.rawEventSource.Close()
+	e.lock.Lock()
+	e.closed = true
+	raw := e.rawEventSource
+	e.lock.Unlock()
+
+	err := raw.Close()
 	if err != nil {
 		return NewCloseError(err)
 	}
@@ -110,6 +183,63 @@ func (e *eventSource) Close() error {
 	return nil
 }
 
+// replace closes a raw source which missed its heartbeats and reconnects.
+func (e *eventSource) replace(stale RawEventSource) error {
+	stale.Close()
+
+	e.lock.Lock()
+	lastEventID := e.lastEventID
+	closed := e.closed
+	e.lock.Unlock()
+
+	if closed {
+		return ErrSourceClosed
+	}
+
+	raw, err := e.reconnect(lastEventID)
+	if err != nil {
+		return NewRawEventSourceError(err)
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if e.closed {
+		raw.Close()
+		return ErrSourceClosed
+	}
+
+	e.rawEventSource = raw
+	return nil
+}
+
+func nextWithTimeout(raw RawEventSource, timeout time.Duration) (sse.Event, error) {
+	if timeout <= 0 {
+		return raw.Next()
+	}
+
+	type result struct {
+		event sse.Event
+		err   error
+	}
+
+	results := make(chan result, 1)
+	go func() {
+		event, err := raw.Next()
+		results <- result{event: event, err: err}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case r := <-results:
+		return r.event, r.err
+	case <-timer.C:
+		return sse.Event{}, errHeartbeatMissed
+	}
+}
+
 func parseRawEvent(rawEvent sse.Event) (Event, error) {
 	switch EventType(rawEvent.Name) {
 	case EventTypeDesiredLRPCreated:
@@ -165,6 +295,36 @@ func parseRawEvent(rawEvent sse.Event) (Event, error) {
 		}
 
 		return event, nil
+
+	case EventTypeTaskCreated:
+		var event TaskCreatedEvent
+		err := json.Unmarshal(rawEvent.Data, &event)
+		if err != nil {
+			return nil, NewInvalidPayloadError(err)
+		}
+
+		return event, nil
+
+	case EventTypeTaskChanged:
+		var event TaskChangedEvent
+		err := json.Unmarshal(rawEvent.Data, &event)
+		if err != nil {
+			return nil, NewInvalidPayloadError(err)
+		}
+
+		return event, nil
+
+	case EventTypeTaskRemoved:
+		var event TaskRemovedEvent
+		err := json.Unmarshal(rawEvent.Data, &event)
+		if err != nil {
+			return nil, NewInvalidPayloadError(err)
+		}
+
+		return event, nil
+
+	case EventTypeResyncRequired:
+		return NewResyncRequiredEvent(), nil
 	}
 
 	return nil, ErrUnrecognizedEventType
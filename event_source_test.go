@@ -231,6 +231,207 @@ var _ = Describe("EventSource", func() {
 			})
 		})
 
+		Describe("Task Events", func() {
+			var taskResponse receptor.TaskResponse
+
+			BeforeEach(func() {
+				taskResponse = serialization.TaskToResponse(
+					&models.Task{
+						TaskGuid: "some-guid",
+						Domain:   "some-domain",
+						TaskDefinition: &models.TaskDefinition{
+							RootFs: "some-rootfs",
+							Action: models.WrapAction(&models.RunAction{
+								Path: "true",
+								User: "marcy",
+							}),
+						},
+						State: models.Task_Pending,
+					},
+				)
+			})
+
+			Context("when receiving a TaskCreatedEvent", func() {
+				var expectedEvent receptor.TaskCreatedEvent
+
+				BeforeEach(func() {
+					expectedEvent = receptor.NewTaskCreatedEvent(taskResponse)
+					payload, err := json.Marshal(expectedEvent)
+					Expect(err).NotTo(HaveOccurred())
+
+					fakeRawEventSource.NextReturns(
+						sse.Event{
+							ID:   "sup",
+							Name: string(expectedEvent.EventType()),
+							Data: payload,
+						},
+						nil,
+					)
+				})
+
+				It("returns the event", func() {
+					event, err := eventSource.Next()
+					Expect(err).NotTo(HaveOccurred())
+
+					taskCreatedEvent, ok := event.(receptor.TaskCreatedEvent)
+					Expect(ok).To(BeTrue())
+					Expect(taskCreatedEvent).To(Equal(expectedEvent))
+				})
+			})
+
+			Context("when receiving a TaskChangedEvent", func() {
+				var expectedEvent receptor.TaskChangedEvent
+
+				BeforeEach(func() {
+					expectedEvent = receptor.NewTaskChangedEvent(
+						taskResponse,
+						taskResponse,
+					)
+					payload, err := json.Marshal(expectedEvent)
+					Expect(err).NotTo(HaveOccurred())
+
+					fakeRawEventSource.NextReturns(
+						sse.Event{
+							ID:   "sup",
+							Name: string(expectedEvent.EventType()),
+							Data: payload,
+						},
+						nil,
+					)
+				})
+
+				It("returns the event", func() {
+					event, err := eventSource.Next()
+					Expect(err).NotTo(HaveOccurred())
+
+					taskChangedEvent, ok := event.(receptor.TaskChangedEvent)
+					Expect(ok).To(BeTrue())
+					Expect(taskChangedEvent).To(Equal(expectedEvent))
+				})
+			})
+
+			Context("when receiving a TaskRemovedEvent", func() {
+				var expectedEvent receptor.TaskRemovedEvent
+
+				BeforeEach(func() {
+					expectedEvent = receptor.NewTaskRemovedEvent(taskResponse)
+					payload, err := json.Marshal(expectedEvent)
+					Expect(err).NotTo(HaveOccurred())
+
+					fakeRawEventSource.NextReturns(
+						sse.Event{
+							ID:   "sup",
+							Name: string(expectedEvent.EventType()),
+							Data: payload,
+						},
+						nil,
+					)
+				})
+
+				It("returns the event", func() {
+					event, err := eventSource.Next()
+					Expect(err).NotTo(HaveOccurred())
+
+					taskRemovedEvent, ok := event.(receptor.TaskRemovedEvent)
+					Expect(ok).To(BeTrue())
+					Expect(taskRemovedEvent).To(Equal(expectedEvent))
+				})
+			})
+		})
+
+		Context("when receiving a ResyncRequiredEvent", func() {
+			BeforeEach(func() {
+				fakeRawEventSource.NextReturns(
+					sse.Event{
+						ID:   "42",
+						Name: string(receptor.EventTypeResyncRequired),
+						Data: []byte("{}"),
+					},
+					nil,
+				)
+			})
+
+			It("returns the event", func() {
+				event, err := eventSource.Next()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(event).To(Equal(receptor.NewResyncRequiredEvent()))
+			})
+		})
+
+		Context("when receiving a heartbeat", func() {
+			var expectedEvent receptor.DesiredLRPRemovedEvent
+
+			BeforeEach(func() {
+				expectedEvent = receptor.NewDesiredLRPRemovedEvent(receptor.DesiredLRPResponse{ProcessGuid: "some-guid"})
+				payload, err := json.Marshal(expectedEvent)
+				Expect(err).NotTo(HaveOccurred())
+
+				rawEvents := []sse.Event{
+					{ID: "1", Name: string(receptor.EventTypeHeartbeat), Data: []byte(`{"interval_ms":1000}`)},
+					{ID: "2", Name: string(expectedEvent.EventType()), Data: payload},
+				}
+				fakeRawEventSource.NextStub = func() (sse.Event, error) {
+					rawEvent := rawEvents[0]
+					rawEvents = rawEvents[1:]
+					return rawEvent, nil
+				}
+			})
+
+			It("skips it", func() {
+				event, err := eventSource.Next()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(event).To(Equal(expectedEvent))
+			})
+		})
+
+		Context("when heartbeats are missed", func() {
+			var (
+				reconnectedSource *fake_receptor.FakeRawEventSource
+				reconnectIDs      chan string
+				expectedEvent     receptor.DesiredLRPRemovedEvent
+			)
+
+			BeforeEach(func() {
+				expectedEvent = receptor.NewDesiredLRPRemovedEvent(receptor.DesiredLRPResponse{ProcessGuid: "some-guid"})
+				payload, err := json.Marshal(expectedEvent)
+				Expect(err).NotTo(HaveOccurred())
+
+				closed := make(chan struct{})
+				heartbeatSent := false
+				fakeRawEventSource.NextStub = func() (sse.Event, error) {
+					if !heartbeatSent {
+						heartbeatSent = true
+						return sse.Event{ID: "7", Name: string(receptor.EventTypeHeartbeat), Data: []byte(`{"interval_ms":10}`)}, nil
+					}
+
+					<-closed
+					return sse.Event{}, sse.ErrSourceClosed
+				}
+				fakeRawEventSource.CloseStub = func() error {
+					close(closed)
+					return nil
+				}
+
+				reconnectedSource = new(fake_receptor.FakeRawEventSource)
+				reconnectedSource.NextReturns(sse.Event{ID: "8", Name: string(expectedEvent.EventType()), Data: payload}, nil)
+
+				reconnectIDs = make(chan string, 1)
+				eventSource = receptor.NewEventSourceWithReconnect(fakeRawEventSource, func(lastEventID string) (receptor.RawEventSource, error) {
+					reconnectIDs <- lastEventID
+					return reconnectedSource, nil
+				})
+			})
+
+			It("closes the dead connection and resumes after the last event", func() {
+				event, err := eventSource.Next()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(event).To(Equal(expectedEvent))
+
+				Expect(fakeRawEventSource.CloseCallCount()).To(Equal(1))
+				Expect(reconnectIDs).To(Receive(Equal("7")))
+			})
+		})
+
 		Context("when receiving an unrecognized event", func() {
 			BeforeEach(func() {
 				fakeRawEventSource.NextReturns(
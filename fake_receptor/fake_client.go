@@ -18,6 +18,15 @@ type FakeClient struct {
 	createTaskReturns struct {
 		result1 error
 	}
+	CreateTasksStub        func([]receptor.TaskCreateRequest) ([]receptor.TaskCreateResult, error)
+	createTasksMutex       sync.RWMutex
+	createTasksArgsForCall []struct {
+		arg1 []receptor.TaskCreateRequest
+	}
+	createTasksReturns struct {
+		result1 []receptor.TaskCreateResult
+		result2 error
+	}
 	TasksStub        func() ([]receptor.TaskResponse, error)
 	tasksMutex       sync.RWMutex
 	tasksArgsForCall []struct{}
@@ -34,6 +43,25 @@ type FakeClient struct {
 		result1 []receptor.TaskResponse
 		result2 error
 	}
+	TasksWithFilterStub        func(filter receptor.TaskFilter) ([]receptor.TaskResponse, error)
+	tasksWithFilterMutex       sync.RWMutex
+	tasksWithFilterArgsForCall []struct {
+		filter receptor.TaskFilter
+	}
+	tasksWithFilterReturns struct {
+		result1 []receptor.TaskResponse
+		result2 error
+	}
+	PagedTasksStub        func(domain string, pageSize int) ([]receptor.TaskResponse, error)
+	pagedTasksMutex       sync.RWMutex
+	pagedTasksArgsForCall []struct {
+		domain   string
+		pageSize int
+	}
+	pagedTasksReturns struct {
+		result1 []receptor.TaskResponse
+		result2 error
+	}
 	GetTaskStub        func(taskId string) (receptor.TaskResponse, error)
 	getTaskMutex       sync.RWMutex
 	getTaskArgsForCall []struct {
@@ -43,6 +71,25 @@ type FakeClient struct {
 		result1 receptor.TaskResponse
 		result2 error
 	}
+	GetTaskResultStub        func(taskId string) ([]byte, error)
+	getTaskResultMutex       sync.RWMutex
+	getTaskResultArgsForCall []struct {
+		taskId string
+	}
+	getTaskResultReturns struct {
+		result1 []byte
+		result2 error
+	}
+	WaitForTaskStub        func(taskId string, timeout time.Duration) (receptor.TaskResponse, error)
+	waitForTaskMutex       sync.RWMutex
+	waitForTaskArgsForCall []struct {
+		taskId  string
+		timeout time.Duration
+	}
+	waitForTaskReturns struct {
+		result1 receptor.TaskResponse
+		result2 error
+	}
 	DeleteTaskStub        func(taskId string) error
 	deleteTaskMutex       sync.RWMutex
 	deleteTaskArgsForCall []struct {
@@ -51,6 +98,15 @@ type FakeClient struct {
 	deleteTaskReturns struct {
 		result1 error
 	}
+	DeleteTasksStub        func(filter receptor.TaskFilter) (receptor.TasksDeleteResponse, error)
+	deleteTasksMutex       sync.RWMutex
+	deleteTasksArgsForCall []struct {
+		filter receptor.TaskFilter
+	}
+	deleteTasksReturns struct {
+		result1 receptor.TasksDeleteResponse
+		result2 error
+	}
 	CancelTaskStub        func(taskId string) error
 	cancelTaskMutex       sync.RWMutex
 	cancelTaskArgsForCall []struct {
@@ -59,6 +115,25 @@ type FakeClient struct {
 	cancelTaskReturns struct {
 		result1 error
 	}
+	RetryTaskStub        func(taskId string, request receptor.TaskRetryRequest) (receptor.TaskRetryResponse, error)
+	retryTaskMutex       sync.RWMutex
+	retryTaskArgsForCall []struct {
+		taskId  string
+		request receptor.TaskRetryRequest
+	}
+	retryTaskReturns struct {
+		result1 receptor.TaskRetryResponse
+		result2 error
+	}
+	TaskCallbackDeliveryStub        func(taskId string) (receptor.CallbackDeliveryResponse, error)
+	taskCallbackDeliveryMutex       sync.RWMutex
+	taskCallbackDeliveryArgsForCall []struct {
+		taskId string
+	}
+	taskCallbackDeliveryReturns struct {
+		result1 receptor.CallbackDeliveryResponse
+		result2 error
+	}
 	CreateDesiredLRPStub        func(receptor.DesiredLRPCreateRequest) error
 	createDesiredLRPMutex       sync.RWMutex
 	createDesiredLRPArgsForCall []struct {
@@ -85,6 +160,16 @@ type FakeClient struct {
 	updateDesiredLRPReturns struct {
 		result1 error
 	}
+	UpdateDesiredLRPIfMatchStub        func(processGuid string, tag receptor.ModificationTag, update receptor.DesiredLRPUpdateRequest) error
+	updateDesiredLRPIfMatchMutex       sync.RWMutex
+	updateDesiredLRPIfMatchArgsForCall []struct {
+		processGuid string
+		tag         receptor.ModificationTag
+		update      receptor.DesiredLRPUpdateRequest
+	}
+	updateDesiredLRPIfMatchReturns struct {
+		result1 error
+	}
 	DeleteDesiredLRPStub        func(processGuid string) error
 	deleteDesiredLRPMutex       sync.RWMutex
 	deleteDesiredLRPArgsForCall []struct {
@@ -93,6 +178,85 @@ type FakeClient struct {
 	deleteDesiredLRPReturns struct {
 		result1 error
 	}
+	StopDesiredLRPStub        func(processGuid string) error
+	stopDesiredLRPMutex       sync.RWMutex
+	stopDesiredLRPArgsForCall []struct {
+		processGuid string
+	}
+	stopDesiredLRPReturns struct {
+		result1 error
+	}
+	StartDesiredLRPStub        func(processGuid string) error
+	startDesiredLRPMutex       sync.RWMutex
+	startDesiredLRPArgsForCall []struct {
+		processGuid string
+	}
+	startDesiredLRPReturns struct {
+		result1 error
+	}
+	ScaleDesiredLRPsByDomainStub        func(domain string, instances int, maxInFlight int) (receptor.DesiredLRPsBulkResponse, error)
+	scaleDesiredLRPsByDomainMutex       sync.RWMutex
+	scaleDesiredLRPsByDomainArgsForCall []struct {
+		domain      string
+		instances   int
+		maxInFlight int
+	}
+	scaleDesiredLRPsByDomainReturns struct {
+		result1 receptor.DesiredLRPsBulkResponse
+		result2 error
+	}
+	RestartDesiredLRPsByDomainStub        func(domain string, maxInFlight int) (receptor.DesiredLRPsBulkResponse, error)
+	restartDesiredLRPsByDomainMutex       sync.RWMutex
+	restartDesiredLRPsByDomainArgsForCall []struct {
+		domain      string
+		maxInFlight int
+	}
+	restartDesiredLRPsByDomainReturns struct {
+		result1 receptor.DesiredLRPsBulkResponse
+		result2 error
+	}
+	DeleteDesiredLRPsByDomainStub        func(domain string, maxInFlight int) (receptor.DesiredLRPsBulkResponse, error)
+	deleteDesiredLRPsByDomainMutex       sync.RWMutex
+	deleteDesiredLRPsByDomainArgsForCall []struct {
+		domain      string
+		maxInFlight int
+	}
+	deleteDesiredLRPsByDomainReturns struct {
+		result1 receptor.DesiredLRPsBulkResponse
+		result2 error
+	}
+	AutoscalingPoliciesStub        func() ([]receptor.AutoscalingPolicy, error)
+	autoscalingPoliciesMutex       sync.RWMutex
+	autoscalingPoliciesArgsForCall []struct{}
+	autoscalingPoliciesReturns     struct {
+		result1 []receptor.AutoscalingPolicy
+		result2 error
+	}
+	GetAutoscalingPolicyStub        func(processGuid string) (receptor.AutoscalingPolicy, error)
+	getAutoscalingPolicyMutex       sync.RWMutex
+	getAutoscalingPolicyArgsForCall []struct {
+		processGuid string
+	}
+	getAutoscalingPolicyReturns struct {
+		result1 receptor.AutoscalingPolicy
+		result2 error
+	}
+	SetAutoscalingPolicyStub        func(policy receptor.AutoscalingPolicy) error
+	setAutoscalingPolicyMutex       sync.RWMutex
+	setAutoscalingPolicyArgsForCall []struct {
+		policy receptor.AutoscalingPolicy
+	}
+	setAutoscalingPolicyReturns struct {
+		result1 error
+	}
+	DeleteAutoscalingPolicyStub        func(processGuid string) error
+	deleteAutoscalingPolicyMutex       sync.RWMutex
+	deleteAutoscalingPolicyArgsForCall []struct {
+		processGuid string
+	}
+	deleteAutoscalingPolicyReturns struct {
+		result1 error
+	}
 	DesiredLRPsStub        func() ([]receptor.DesiredLRPResponse, error)
 	desiredLRPsMutex       sync.RWMutex
 	desiredLRPsArgsForCall []struct{}
@@ -109,6 +273,60 @@ type FakeClient struct {
 		result1 []receptor.DesiredLRPResponse
 		result2 error
 	}
+	PagedDesiredLRPsStub        func(domain string, pageSize int) ([]receptor.DesiredLRPResponse, error)
+	pagedDesiredLRPsMutex       sync.RWMutex
+	pagedDesiredLRPsArgsForCall []struct {
+		domain   string
+		pageSize int
+	}
+	pagedDesiredLRPsReturns struct {
+		result1 []receptor.DesiredLRPResponse
+		result2 error
+	}
+	DesiredLRPSummariesStub        func() ([]receptor.DesiredLRPSummaryResponse, error)
+	desiredLRPSummariesMutex       sync.RWMutex
+	desiredLRPSummariesArgsForCall []struct{}
+	desiredLRPSummariesReturns     struct {
+		result1 []receptor.DesiredLRPSummaryResponse
+		result2 error
+	}
+	DesiredLRPSummariesByDomainStub        func(domain string) ([]receptor.DesiredLRPSummaryResponse, error)
+	desiredLRPSummariesByDomainMutex       sync.RWMutex
+	desiredLRPSummariesByDomainArgsForCall []struct {
+		domain string
+	}
+	desiredLRPSummariesByDomainReturns struct {
+		result1 []receptor.DesiredLRPSummaryResponse
+		result2 error
+	}
+	StartRollingUpdateStub        func(processGuid string, request receptor.RollingUpdateRequest) (receptor.RollingUpdateResponse, error)
+	startRollingUpdateMutex       sync.RWMutex
+	startRollingUpdateArgsForCall []struct {
+		processGuid string
+		request     receptor.RollingUpdateRequest
+	}
+	startRollingUpdateReturns struct {
+		result1 receptor.RollingUpdateResponse
+		result2 error
+	}
+	GetRollingUpdateStub        func(processGuid string) (receptor.RollingUpdateResponse, error)
+	getRollingUpdateMutex       sync.RWMutex
+	getRollingUpdateArgsForCall []struct {
+		processGuid string
+	}
+	getRollingUpdateReturns struct {
+		result1 receptor.RollingUpdateResponse
+		result2 error
+	}
+	AbortRollingUpdateStub        func(processGuid string) (receptor.RollingUpdateResponse, error)
+	abortRollingUpdateMutex       sync.RWMutex
+	abortRollingUpdateArgsForCall []struct {
+		processGuid string
+	}
+	abortRollingUpdateReturns struct {
+		result1 receptor.RollingUpdateResponse
+		result2 error
+	}
 	ActualLRPsStub        func() ([]receptor.ActualLRPResponse, error)
 	actualLRPsMutex       sync.RWMutex
 	actualLRPsArgsForCall []struct{}
@@ -125,6 +343,25 @@ type FakeClient struct {
 		result1 []receptor.ActualLRPResponse
 		result2 error
 	}
+	ActualLRPsWithFilterStub        func(filter receptor.ActualLRPFilter) ([]receptor.ActualLRPResponse, error)
+	actualLRPsWithFilterMutex       sync.RWMutex
+	actualLRPsWithFilterArgsForCall []struct {
+		filter receptor.ActualLRPFilter
+	}
+	actualLRPsWithFilterReturns struct {
+		result1 []receptor.ActualLRPResponse
+		result2 error
+	}
+	PagedActualLRPsStub        func(domain string, pageSize int) ([]receptor.ActualLRPResponse, error)
+	pagedActualLRPsMutex       sync.RWMutex
+	pagedActualLRPsArgsForCall []struct {
+		domain   string
+		pageSize int
+	}
+	pagedActualLRPsReturns struct {
+		result1 []receptor.ActualLRPResponse
+		result2 error
+	}
 	ActualLRPsByProcessGuidStub        func(processGuid string) ([]receptor.ActualLRPResponse, error)
 	actualLRPsByProcessGuidMutex       sync.RWMutex
 	actualLRPsByProcessGuidArgsForCall []struct {
@@ -144,6 +381,41 @@ type FakeClient struct {
 		result1 receptor.ActualLRPResponse
 		result2 error
 	}
+	ActualLRPGroupsStub        func() ([]receptor.ActualLRPGroupResponse, error)
+	actualLRPGroupsMutex       sync.RWMutex
+	actualLRPGroupsArgsForCall []struct{}
+	actualLRPGroupsReturns     struct {
+		result1 []receptor.ActualLRPGroupResponse
+		result2 error
+	}
+	ActualLRPGroupsByDomainStub        func(domain string) ([]receptor.ActualLRPGroupResponse, error)
+	actualLRPGroupsByDomainMutex       sync.RWMutex
+	actualLRPGroupsByDomainArgsForCall []struct {
+		domain string
+	}
+	actualLRPGroupsByDomainReturns struct {
+		result1 []receptor.ActualLRPGroupResponse
+		result2 error
+	}
+	ActualLRPGroupsByProcessGuidStub        func(processGuid string) ([]receptor.ActualLRPGroupResponse, error)
+	actualLRPGroupsByProcessGuidMutex       sync.RWMutex
+	actualLRPGroupsByProcessGuidArgsForCall []struct {
+		processGuid string
+	}
+	actualLRPGroupsByProcessGuidReturns struct {
+		result1 []receptor.ActualLRPGroupResponse
+		result2 error
+	}
+	ActualLRPGroupByProcessGuidAndIndexStub        func(processGuid string, index int) (receptor.ActualLRPGroupResponse, error)
+	actualLRPGroupByProcessGuidAndIndexMutex       sync.RWMutex
+	actualLRPGroupByProcessGuidAndIndexArgsForCall []struct {
+		processGuid string
+		index       int
+	}
+	actualLRPGroupByProcessGuidAndIndexReturns struct {
+		result1 receptor.ActualLRPGroupResponse
+		result2 error
+	}
 	KillActualLRPByProcessGuidAndIndexStub        func(processGuid string, index int) error
 	killActualLRPByProcessGuidAndIndexMutex       sync.RWMutex
 	killActualLRPByProcessGuidAndIndexArgsForCall []struct {
@@ -153,6 +425,42 @@ type FakeClient struct {
 	killActualLRPByProcessGuidAndIndexReturns struct {
 		result1 error
 	}
+	KillActualLRPsByProcessGuidStub        func(processGuid string, maxInFlight int) (receptor.ActualLRPsKillResponse, error)
+	killActualLRPsByProcessGuidMutex       sync.RWMutex
+	killActualLRPsByProcessGuidArgsForCall []struct {
+		processGuid string
+		maxInFlight int
+	}
+	killActualLRPsByProcessGuidReturns struct {
+		result1 receptor.ActualLRPsKillResponse
+		result2 error
+	}
+	KillActualLRPByInstanceGuidStub        func(processGuid string, instanceGuid string) error
+	killActualLRPByInstanceGuidMutex       sync.RWMutex
+	killActualLRPByInstanceGuidArgsForCall []struct {
+		processGuid  string
+		instanceGuid string
+	}
+	killActualLRPByInstanceGuidReturns struct {
+		result1 error
+	}
+	ActualLRPCrashesStub        func(processGuid string, index int) ([]receptor.ActualLRPCrashResponse, error)
+	actualLRPCrashesMutex       sync.RWMutex
+	actualLRPCrashesArgsForCall []struct {
+		processGuid string
+		index       int
+	}
+	actualLRPCrashesReturns struct {
+		result1 []receptor.ActualLRPCrashResponse
+		result2 error
+	}
+	CrashingActualLRPsStub        func() ([]receptor.CrashingActualLRPResponse, error)
+	crashingActualLRPsMutex       sync.RWMutex
+	crashingActualLRPsArgsForCall []struct{}
+	crashingActualLRPsReturns     struct {
+		result1 []receptor.CrashingActualLRPResponse
+		result2 error
+	}
 	SubscribeToEventsStub        func() (receptor.EventSource, error)
 	subscribeToEventsMutex       sync.RWMutex
 	subscribeToEventsArgsForCall []struct{}
@@ -160,6 +468,15 @@ type FakeClient struct {
 		result1 receptor.EventSource
 		result2 error
 	}
+	SubscribeToEventsWithFilterStub        func(filter receptor.EventFilter) (receptor.EventSource, error)
+	subscribeToEventsWithFilterMutex       sync.RWMutex
+	subscribeToEventsWithFilterArgsForCall []struct {
+		filter receptor.EventFilter
+	}
+	subscribeToEventsWithFilterReturns struct {
+		result1 receptor.EventSource
+		result2 error
+	}
 	CellsStub        func() ([]receptor.CellResponse, error)
 	cellsMutex       sync.RWMutex
 	cellsArgsForCall []struct{}
@@ -236,6 +553,39 @@ func (fake *FakeClient) CreateTaskReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeClient) CreateTasks(arg1 []receptor.TaskCreateRequest) ([]receptor.TaskCreateResult, error) {
+	fake.createTasksMutex.Lock()
+	fake.createTasksArgsForCall = append(fake.createTasksArgsForCall, struct {
+		arg1 []receptor.TaskCreateRequest
+	}{arg1})
+	fake.createTasksMutex.Unlock()
+	if fake.CreateTasksStub != nil {
+		return fake.CreateTasksStub(arg1)
+	} else {
+		return fake.createTasksReturns.result1, fake.createTasksReturns.result2
+	}
+}
+
+func (fake *FakeClient) CreateTasksCallCount() int {
+	fake.createTasksMutex.RLock()
+	defer fake.createTasksMutex.RUnlock()
+	return len(fake.createTasksArgsForCall)
+}
+
+func (fake *FakeClient) CreateTasksArgsForCall(i int) []receptor.TaskCreateRequest {
+	fake.createTasksMutex.RLock()
+	defer fake.createTasksMutex.RUnlock()
+	return fake.createTasksArgsForCall[i].arg1
+}
+
+func (fake *FakeClient) CreateTasksReturns(result1 []receptor.TaskCreateResult, result2 error) {
+	fake.CreateTasksStub = nil
+	fake.createTasksReturns = struct {
+		result1 []receptor.TaskCreateResult
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClient) Tasks() ([]receptor.TaskResponse, error) {
 	fake.tasksMutex.Lock()
 	fake.tasksArgsForCall = append(fake.tasksArgsForCall, struct{}{})
@@ -294,6 +644,73 @@ func (fake *FakeClient) TasksByDomainReturns(result1 []receptor.TaskResponse, re
 	}{result1, result2}
 }
 
+func (fake *FakeClient) TasksWithFilter(filter receptor.TaskFilter) ([]receptor.TaskResponse, error) {
+	fake.tasksWithFilterMutex.Lock()
+	fake.tasksWithFilterArgsForCall = append(fake.tasksWithFilterArgsForCall, struct {
+		filter receptor.TaskFilter
+	}{filter})
+	fake.tasksWithFilterMutex.Unlock()
+	if fake.TasksWithFilterStub != nil {
+		return fake.TasksWithFilterStub(filter)
+	} else {
+		return fake.tasksWithFilterReturns.result1, fake.tasksWithFilterReturns.result2
+	}
+}
+
+func (fake *FakeClient) TasksWithFilterCallCount() int {
+	fake.tasksWithFilterMutex.RLock()
+	defer fake.tasksWithFilterMutex.RUnlock()
+	return len(fake.tasksWithFilterArgsForCall)
+}
+
+func (fake *FakeClient) TasksWithFilterArgsForCall(i int) receptor.TaskFilter {
+	fake.tasksWithFilterMutex.RLock()
+	defer fake.tasksWithFilterMutex.RUnlock()
+	return fake.tasksWithFilterArgsForCall[i].filter
+}
+
+func (fake *FakeClient) TasksWithFilterReturns(result1 []receptor.TaskResponse, result2 error) {
+	fake.TasksWithFilterStub = nil
+	fake.tasksWithFilterReturns = struct {
+		result1 []receptor.TaskResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) PagedTasks(domain string, pageSize int) ([]receptor.TaskResponse, error) {
+	fake.pagedTasksMutex.Lock()
+	fake.pagedTasksArgsForCall = append(fake.pagedTasksArgsForCall, struct {
+		domain   string
+		pageSize int
+	}{domain, pageSize})
+	fake.pagedTasksMutex.Unlock()
+	if fake.PagedTasksStub != nil {
+		return fake.PagedTasksStub(domain, pageSize)
+	} else {
+		return fake.pagedTasksReturns.result1, fake.pagedTasksReturns.result2
+	}
+}
+
+func (fake *FakeClient) PagedTasksCallCount() int {
+	fake.pagedTasksMutex.RLock()
+	defer fake.pagedTasksMutex.RUnlock()
+	return len(fake.pagedTasksArgsForCall)
+}
+
+func (fake *FakeClient) PagedTasksArgsForCall(i int) (string, int) {
+	fake.pagedTasksMutex.RLock()
+	defer fake.pagedTasksMutex.RUnlock()
+	return fake.pagedTasksArgsForCall[i].domain, fake.pagedTasksArgsForCall[i].pageSize
+}
+
+func (fake *FakeClient) PagedTasksReturns(result1 []receptor.TaskResponse, result2 error) {
+	fake.PagedTasksStub = nil
+	fake.pagedTasksReturns = struct {
+		result1 []receptor.TaskResponse
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClient) GetTask(taskId string) (receptor.TaskResponse, error) {
 	fake.getTaskMutex.Lock()
 	fake.getTaskArgsForCall = append(fake.getTaskArgsForCall, struct {
@@ -327,6 +744,73 @@ func (fake *FakeClient) GetTaskReturns(result1 receptor.TaskResponse, result2 er
 	}{result1, result2}
 }
 
+func (fake *FakeClient) GetTaskResult(taskId string) ([]byte, error) {
+	fake.getTaskResultMutex.Lock()
+	fake.getTaskResultArgsForCall = append(fake.getTaskResultArgsForCall, struct {
+		taskId string
+	}{taskId})
+	fake.getTaskResultMutex.Unlock()
+	if fake.GetTaskResultStub != nil {
+		return fake.GetTaskResultStub(taskId)
+	} else {
+		return fake.getTaskResultReturns.result1, fake.getTaskResultReturns.result2
+	}
+}
+
+func (fake *FakeClient) GetTaskResultCallCount() int {
+	fake.getTaskResultMutex.RLock()
+	defer fake.getTaskResultMutex.RUnlock()
+	return len(fake.getTaskResultArgsForCall)
+}
+
+func (fake *FakeClient) GetTaskResultArgsForCall(i int) string {
+	fake.getTaskResultMutex.RLock()
+	defer fake.getTaskResultMutex.RUnlock()
+	return fake.getTaskResultArgsForCall[i].taskId
+}
+
+func (fake *FakeClient) GetTaskResultReturns(result1 []byte, result2 error) {
+	fake.GetTaskResultStub = nil
+	fake.getTaskResultReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) WaitForTask(taskId string, timeout time.Duration) (receptor.TaskResponse, error) {
+	fake.waitForTaskMutex.Lock()
+	fake.waitForTaskArgsForCall = append(fake.waitForTaskArgsForCall, struct {
+		taskId  string
+		timeout time.Duration
+	}{taskId, timeout})
+	fake.waitForTaskMutex.Unlock()
+	if fake.WaitForTaskStub != nil {
+		return fake.WaitForTaskStub(taskId, timeout)
+	} else {
+		return fake.waitForTaskReturns.result1, fake.waitForTaskReturns.result2
+	}
+}
+
+func (fake *FakeClient) WaitForTaskCallCount() int {
+	fake.waitForTaskMutex.RLock()
+	defer fake.waitForTaskMutex.RUnlock()
+	return len(fake.waitForTaskArgsForCall)
+}
+
+func (fake *FakeClient) WaitForTaskArgsForCall(i int) (string, time.Duration) {
+	fake.waitForTaskMutex.RLock()
+	defer fake.waitForTaskMutex.RUnlock()
+	return fake.waitForTaskArgsForCall[i].taskId, fake.waitForTaskArgsForCall[i].timeout
+}
+
+func (fake *FakeClient) WaitForTaskReturns(result1 receptor.TaskResponse, result2 error) {
+	fake.WaitForTaskStub = nil
+	fake.waitForTaskReturns = struct {
+		result1 receptor.TaskResponse
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClient) DeleteTask(taskId string) error {
 	fake.deleteTaskMutex.Lock()
 	fake.deleteTaskArgsForCall = append(fake.deleteTaskArgsForCall, struct {
@@ -359,6 +843,39 @@ func (fake *FakeClient) DeleteTaskReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeClient) DeleteTasks(filter receptor.TaskFilter) (receptor.TasksDeleteResponse, error) {
+	fake.deleteTasksMutex.Lock()
+	fake.deleteTasksArgsForCall = append(fake.deleteTasksArgsForCall, struct {
+		filter receptor.TaskFilter
+	}{filter})
+	fake.deleteTasksMutex.Unlock()
+	if fake.DeleteTasksStub != nil {
+		return fake.DeleteTasksStub(filter)
+	} else {
+		return fake.deleteTasksReturns.result1, fake.deleteTasksReturns.result2
+	}
+}
+
+func (fake *FakeClient) DeleteTasksCallCount() int {
+	fake.deleteTasksMutex.RLock()
+	defer fake.deleteTasksMutex.RUnlock()
+	return len(fake.deleteTasksArgsForCall)
+}
+
+func (fake *FakeClient) DeleteTasksArgsForCall(i int) receptor.TaskFilter {
+	fake.deleteTasksMutex.RLock()
+	defer fake.deleteTasksMutex.RUnlock()
+	return fake.deleteTasksArgsForCall[i].filter
+}
+
+func (fake *FakeClient) DeleteTasksReturns(result1 receptor.TasksDeleteResponse, result2 error) {
+	fake.DeleteTasksStub = nil
+	fake.deleteTasksReturns = struct {
+		result1 receptor.TasksDeleteResponse
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClient) CancelTask(taskId string) error {
 	fake.cancelTaskMutex.Lock()
 	fake.cancelTaskArgsForCall = append(fake.cancelTaskArgsForCall, struct {
@@ -391,6 +908,73 @@ func (fake *FakeClient) CancelTaskReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeClient) RetryTask(taskId string, request receptor.TaskRetryRequest) (receptor.TaskRetryResponse, error) {
+	fake.retryTaskMutex.Lock()
+	fake.retryTaskArgsForCall = append(fake.retryTaskArgsForCall, struct {
+		taskId  string
+		request receptor.TaskRetryRequest
+	}{taskId, request})
+	fake.retryTaskMutex.Unlock()
+	if fake.RetryTaskStub != nil {
+		return fake.RetryTaskStub(taskId, request)
+	} else {
+		return fake.retryTaskReturns.result1, fake.retryTaskReturns.result2
+	}
+}
+
+func (fake *FakeClient) RetryTaskCallCount() int {
+	fake.retryTaskMutex.RLock()
+	defer fake.retryTaskMutex.RUnlock()
+	return len(fake.retryTaskArgsForCall)
+}
+
+func (fake *FakeClient) RetryTaskArgsForCall(i int) (string, receptor.TaskRetryRequest) {
+	fake.retryTaskMutex.RLock()
+	defer fake.retryTaskMutex.RUnlock()
+	return fake.retryTaskArgsForCall[i].taskId, fake.retryTaskArgsForCall[i].request
+}
+
+func (fake *FakeClient) RetryTaskReturns(result1 receptor.TaskRetryResponse, result2 error) {
+	fake.RetryTaskStub = nil
+	fake.retryTaskReturns = struct {
+		result1 receptor.TaskRetryResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) TaskCallbackDelivery(taskId string) (receptor.CallbackDeliveryResponse, error) {
+	fake.taskCallbackDeliveryMutex.Lock()
+	fake.taskCallbackDeliveryArgsForCall = append(fake.taskCallbackDeliveryArgsForCall, struct {
+		taskId string
+	}{taskId})
+	fake.taskCallbackDeliveryMutex.Unlock()
+	if fake.TaskCallbackDeliveryStub != nil {
+		return fake.TaskCallbackDeliveryStub(taskId)
+	} else {
+		return fake.taskCallbackDeliveryReturns.result1, fake.taskCallbackDeliveryReturns.result2
+	}
+}
+
+func (fake *FakeClient) TaskCallbackDeliveryCallCount() int {
+	fake.taskCallbackDeliveryMutex.RLock()
+	defer fake.taskCallbackDeliveryMutex.RUnlock()
+	return len(fake.taskCallbackDeliveryArgsForCall)
+}
+
+func (fake *FakeClient) TaskCallbackDeliveryArgsForCall(i int) string {
+	fake.taskCallbackDeliveryMutex.RLock()
+	defer fake.taskCallbackDeliveryMutex.RUnlock()
+	return fake.taskCallbackDeliveryArgsForCall[i].taskId
+}
+
+func (fake *FakeClient) TaskCallbackDeliveryReturns(result1 receptor.CallbackDeliveryResponse, result2 error) {
+	fake.TaskCallbackDeliveryStub = nil
+	fake.taskCallbackDeliveryReturns = struct {
+		result1 receptor.CallbackDeliveryResponse
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClient) CreateDesiredLRP(arg1 receptor.DesiredLRPCreateRequest) error {
 	fake.createDesiredLRPMutex.Lock()
 	fake.createDesiredLRPArgsForCall = append(fake.createDesiredLRPArgsForCall, struct {
@@ -489,6 +1073,40 @@ func (fake *FakeClient) UpdateDesiredLRPReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeClient) UpdateDesiredLRPIfMatch(processGuid string, tag receptor.ModificationTag, update receptor.DesiredLRPUpdateRequest) error {
+	fake.updateDesiredLRPIfMatchMutex.Lock()
+	fake.updateDesiredLRPIfMatchArgsForCall = append(fake.updateDesiredLRPIfMatchArgsForCall, struct {
+		processGuid string
+		tag         receptor.ModificationTag
+		update      receptor.DesiredLRPUpdateRequest
+	}{processGuid, tag, update})
+	fake.updateDesiredLRPIfMatchMutex.Unlock()
+	if fake.UpdateDesiredLRPIfMatchStub != nil {
+		return fake.UpdateDesiredLRPIfMatchStub(processGuid, tag, update)
+	} else {
+		return fake.updateDesiredLRPIfMatchReturns.result1
+	}
+}
+
+func (fake *FakeClient) UpdateDesiredLRPIfMatchCallCount() int {
+	fake.updateDesiredLRPIfMatchMutex.RLock()
+	defer fake.updateDesiredLRPIfMatchMutex.RUnlock()
+	return len(fake.updateDesiredLRPIfMatchArgsForCall)
+}
+
+func (fake *FakeClient) UpdateDesiredLRPIfMatchArgsForCall(i int) (string, receptor.ModificationTag, receptor.DesiredLRPUpdateRequest) {
+	fake.updateDesiredLRPIfMatchMutex.RLock()
+	defer fake.updateDesiredLRPIfMatchMutex.RUnlock()
+	return fake.updateDesiredLRPIfMatchArgsForCall[i].processGuid, fake.updateDesiredLRPIfMatchArgsForCall[i].tag, fake.updateDesiredLRPIfMatchArgsForCall[i].update
+}
+
+func (fake *FakeClient) UpdateDesiredLRPIfMatchReturns(result1 error) {
+	fake.UpdateDesiredLRPIfMatchStub = nil
+	fake.updateDesiredLRPIfMatchReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeClient) DeleteDesiredLRP(processGuid string) error {
 	fake.deleteDesiredLRPMutex.Lock()
 	fake.deleteDesiredLRPArgsForCall = append(fake.deleteDesiredLRPArgsForCall, struct {
@@ -521,19 +1139,308 @@ func (fake *FakeClient) DeleteDesiredLRPReturns(result1 error) {
 	}{result1}
 }
 
-func (fake *FakeClient) DesiredLRPs() ([]receptor.DesiredLRPResponse, error) {
-	fake.desiredLRPsMutex.Lock()
-	fake.desiredLRPsArgsForCall = append(fake.desiredLRPsArgsForCall, struct{}{})
-	fake.desiredLRPsMutex.Unlock()
-	if fake.DesiredLRPsStub != nil {
-		return fake.DesiredLRPsStub()
+func (fake *FakeClient) StopDesiredLRP(processGuid string) error {
+	fake.stopDesiredLRPMutex.Lock()
+	fake.stopDesiredLRPArgsForCall = append(fake.stopDesiredLRPArgsForCall, struct {
+		processGuid string
+	}{processGuid})
+	fake.stopDesiredLRPMutex.Unlock()
+	if fake.StopDesiredLRPStub != nil {
+		return fake.StopDesiredLRPStub(processGuid)
 	} else {
-		return fake.desiredLRPsReturns.result1, fake.desiredLRPsReturns.result2
+		return fake.stopDesiredLRPReturns.result1
 	}
 }
 
-func (fake *FakeClient) DesiredLRPsCallCount() int {
-	fake.desiredLRPsMutex.RLock()
+func (fake *FakeClient) StopDesiredLRPCallCount() int {
+	fake.stopDesiredLRPMutex.RLock()
+	defer fake.stopDesiredLRPMutex.RUnlock()
+	return len(fake.stopDesiredLRPArgsForCall)
+}
+
+func (fake *FakeClient) StopDesiredLRPArgsForCall(i int) string {
+	fake.stopDesiredLRPMutex.RLock()
+	defer fake.stopDesiredLRPMutex.RUnlock()
+	return fake.stopDesiredLRPArgsForCall[i].processGuid
+}
+
+func (fake *FakeClient) StopDesiredLRPReturns(result1 error) {
+	fake.StopDesiredLRPStub = nil
+	fake.stopDesiredLRPReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) StartDesiredLRP(processGuid string) error {
+	fake.startDesiredLRPMutex.Lock()
+	fake.startDesiredLRPArgsForCall = append(fake.startDesiredLRPArgsForCall, struct {
+		processGuid string
+	}{processGuid})
+	fake.startDesiredLRPMutex.Unlock()
+	if fake.StartDesiredLRPStub != nil {
+		return fake.StartDesiredLRPStub(processGuid)
+	} else {
+		return fake.startDesiredLRPReturns.result1
+	}
+}
+
+func (fake *FakeClient) StartDesiredLRPCallCount() int {
+	fake.startDesiredLRPMutex.RLock()
+	defer fake.startDesiredLRPMutex.RUnlock()
+	return len(fake.startDesiredLRPArgsForCall)
+}
+
+func (fake *FakeClient) StartDesiredLRPArgsForCall(i int) string {
+	fake.startDesiredLRPMutex.RLock()
+	defer fake.startDesiredLRPMutex.RUnlock()
+	return fake.startDesiredLRPArgsForCall[i].processGuid
+}
+
+func (fake *FakeClient) StartDesiredLRPReturns(result1 error) {
+	fake.StartDesiredLRPStub = nil
+	fake.startDesiredLRPReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) ScaleDesiredLRPsByDomain(domain string, instances int, maxInFlight int) (receptor.DesiredLRPsBulkResponse, error) {
+	fake.scaleDesiredLRPsByDomainMutex.Lock()
+	fake.scaleDesiredLRPsByDomainArgsForCall = append(fake.scaleDesiredLRPsByDomainArgsForCall, struct {
+		domain      string
+		instances   int
+		maxInFlight int
+	}{domain, instances, maxInFlight})
+	fake.scaleDesiredLRPsByDomainMutex.Unlock()
+	if fake.ScaleDesiredLRPsByDomainStub != nil {
+		return fake.ScaleDesiredLRPsByDomainStub(domain, instances, maxInFlight)
+	} else {
+		return fake.scaleDesiredLRPsByDomainReturns.result1, fake.scaleDesiredLRPsByDomainReturns.result2
+	}
+}
+
+func (fake *FakeClient) ScaleDesiredLRPsByDomainCallCount() int {
+	fake.scaleDesiredLRPsByDomainMutex.RLock()
+	defer fake.scaleDesiredLRPsByDomainMutex.RUnlock()
+	return len(fake.scaleDesiredLRPsByDomainArgsForCall)
+}
+
+func (fake *FakeClient) ScaleDesiredLRPsByDomainArgsForCall(i int) (string, int, int) {
+	fake.scaleDesiredLRPsByDomainMutex.RLock()
+	defer fake.scaleDesiredLRPsByDomainMutex.RUnlock()
+	return fake.scaleDesiredLRPsByDomainArgsForCall[i].domain, fake.scaleDesiredLRPsByDomainArgsForCall[i].instances, fake.scaleDesiredLRPsByDomainArgsForCall[i].maxInFlight
+}
+
+func (fake *FakeClient) ScaleDesiredLRPsByDomainReturns(result1 receptor.DesiredLRPsBulkResponse, result2 error) {
+	fake.ScaleDesiredLRPsByDomainStub = nil
+	fake.scaleDesiredLRPsByDomainReturns = struct {
+		result1 receptor.DesiredLRPsBulkResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) RestartDesiredLRPsByDomain(domain string, maxInFlight int) (receptor.DesiredLRPsBulkResponse, error) {
+	fake.restartDesiredLRPsByDomainMutex.Lock()
+	fake.restartDesiredLRPsByDomainArgsForCall = append(fake.restartDesiredLRPsByDomainArgsForCall, struct {
+		domain      string
+		maxInFlight int
+	}{domain, maxInFlight})
+	fake.restartDesiredLRPsByDomainMutex.Unlock()
+	if fake.RestartDesiredLRPsByDomainStub != nil {
+		return fake.RestartDesiredLRPsByDomainStub(domain, maxInFlight)
+	} else {
+		return fake.restartDesiredLRPsByDomainReturns.result1, fake.restartDesiredLRPsByDomainReturns.result2
+	}
+}
+
+func (fake *FakeClient) RestartDesiredLRPsByDomainCallCount() int {
+	fake.restartDesiredLRPsByDomainMutex.RLock()
+	defer fake.restartDesiredLRPsByDomainMutex.RUnlock()
+	return len(fake.restartDesiredLRPsByDomainArgsForCall)
+}
+
+func (fake *FakeClient) RestartDesiredLRPsByDomainArgsForCall(i int) (string, int) {
+	fake.restartDesiredLRPsByDomainMutex.RLock()
+	defer fake.restartDesiredLRPsByDomainMutex.RUnlock()
+	return fake.restartDesiredLRPsByDomainArgsForCall[i].domain, fake.restartDesiredLRPsByDomainArgsForCall[i].maxInFlight
+}
+
+func (fake *FakeClient) RestartDesiredLRPsByDomainReturns(result1 receptor.DesiredLRPsBulkResponse, result2 error) {
+	fake.RestartDesiredLRPsByDomainStub = nil
+	fake.restartDesiredLRPsByDomainReturns = struct {
+		result1 receptor.DesiredLRPsBulkResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DeleteDesiredLRPsByDomain(domain string, maxInFlight int) (receptor.DesiredLRPsBulkResponse, error) {
+	fake.deleteDesiredLRPsByDomainMutex.Lock()
+	fake.deleteDesiredLRPsByDomainArgsForCall = append(fake.deleteDesiredLRPsByDomainArgsForCall, struct {
+		domain      string
+		maxInFlight int
+	}{domain, maxInFlight})
+	fake.deleteDesiredLRPsByDomainMutex.Unlock()
+	if fake.DeleteDesiredLRPsByDomainStub != nil {
+		return fake.DeleteDesiredLRPsByDomainStub(domain, maxInFlight)
+	} else {
+		return fake.deleteDesiredLRPsByDomainReturns.result1, fake.deleteDesiredLRPsByDomainReturns.result2
+	}
+}
+
+func (fake *FakeClient) DeleteDesiredLRPsByDomainCallCount() int {
+	fake.deleteDesiredLRPsByDomainMutex.RLock()
+	defer fake.deleteDesiredLRPsByDomainMutex.RUnlock()
+	return len(fake.deleteDesiredLRPsByDomainArgsForCall)
+}
+
+func (fake *FakeClient) DeleteDesiredLRPsByDomainArgsForCall(i int) (string, int) {
+	fake.deleteDesiredLRPsByDomainMutex.RLock()
+	defer fake.deleteDesiredLRPsByDomainMutex.RUnlock()
+	return fake.deleteDesiredLRPsByDomainArgsForCall[i].domain, fake.deleteDesiredLRPsByDomainArgsForCall[i].maxInFlight
+}
+
+func (fake *FakeClient) DeleteDesiredLRPsByDomainReturns(result1 receptor.DesiredLRPsBulkResponse, result2 error) {
+	fake.DeleteDesiredLRPsByDomainStub = nil
+	fake.deleteDesiredLRPsByDomainReturns = struct {
+		result1 receptor.DesiredLRPsBulkResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) AutoscalingPolicies() ([]receptor.AutoscalingPolicy, error) {
+	fake.autoscalingPoliciesMutex.Lock()
+	fake.autoscalingPoliciesArgsForCall = append(fake.autoscalingPoliciesArgsForCall, struct{}{})
+	fake.autoscalingPoliciesMutex.Unlock()
+	if fake.AutoscalingPoliciesStub != nil {
+		return fake.AutoscalingPoliciesStub()
+	} else {
+		return fake.autoscalingPoliciesReturns.result1, fake.autoscalingPoliciesReturns.result2
+	}
+}
+
+func (fake *FakeClient) AutoscalingPoliciesCallCount() int {
+	fake.autoscalingPoliciesMutex.RLock()
+	defer fake.autoscalingPoliciesMutex.RUnlock()
+	return len(fake.autoscalingPoliciesArgsForCall)
+}
+
+func (fake *FakeClient) AutoscalingPoliciesReturns(result1 []receptor.AutoscalingPolicy, result2 error) {
+	fake.AutoscalingPoliciesStub = nil
+	fake.autoscalingPoliciesReturns = struct {
+		result1 []receptor.AutoscalingPolicy
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetAutoscalingPolicy(processGuid string) (receptor.AutoscalingPolicy, error) {
+	fake.getAutoscalingPolicyMutex.Lock()
+	fake.getAutoscalingPolicyArgsForCall = append(fake.getAutoscalingPolicyArgsForCall, struct {
+		processGuid string
+	}{processGuid})
+	fake.getAutoscalingPolicyMutex.Unlock()
+	if fake.GetAutoscalingPolicyStub != nil {
+		return fake.GetAutoscalingPolicyStub(processGuid)
+	} else {
+		return fake.getAutoscalingPolicyReturns.result1, fake.getAutoscalingPolicyReturns.result2
+	}
+}
+
+func (fake *FakeClient) GetAutoscalingPolicyCallCount() int {
+	fake.getAutoscalingPolicyMutex.RLock()
+	defer fake.getAutoscalingPolicyMutex.RUnlock()
+	return len(fake.getAutoscalingPolicyArgsForCall)
+}
+
+func (fake *FakeClient) GetAutoscalingPolicyArgsForCall(i int) string {
+	fake.getAutoscalingPolicyMutex.RLock()
+	defer fake.getAutoscalingPolicyMutex.RUnlock()
+	return fake.getAutoscalingPolicyArgsForCall[i].processGuid
+}
+
+func (fake *FakeClient) GetAutoscalingPolicyReturns(result1 receptor.AutoscalingPolicy, result2 error) {
+	fake.GetAutoscalingPolicyStub = nil
+	fake.getAutoscalingPolicyReturns = struct {
+		result1 receptor.AutoscalingPolicy
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) SetAutoscalingPolicy(policy receptor.AutoscalingPolicy) error {
+	fake.setAutoscalingPolicyMutex.Lock()
+	fake.setAutoscalingPolicyArgsForCall = append(fake.setAutoscalingPolicyArgsForCall, struct {
+		policy receptor.AutoscalingPolicy
+	}{policy})
+	fake.setAutoscalingPolicyMutex.Unlock()
+	if fake.SetAutoscalingPolicyStub != nil {
+		return fake.SetAutoscalingPolicyStub(policy)
+	} else {
+		return fake.setAutoscalingPolicyReturns.result1
+	}
+}
+
+func (fake *FakeClient) SetAutoscalingPolicyCallCount() int {
+	fake.setAutoscalingPolicyMutex.RLock()
+	defer fake.setAutoscalingPolicyMutex.RUnlock()
+	return len(fake.setAutoscalingPolicyArgsForCall)
+}
+
+func (fake *FakeClient) SetAutoscalingPolicyArgsForCall(i int) receptor.AutoscalingPolicy {
+	fake.setAutoscalingPolicyMutex.RLock()
+	defer fake.setAutoscalingPolicyMutex.RUnlock()
+	return fake.setAutoscalingPolicyArgsForCall[i].policy
+}
+
+func (fake *FakeClient) SetAutoscalingPolicyReturns(result1 error) {
+	fake.SetAutoscalingPolicyStub = nil
+	fake.setAutoscalingPolicyReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) DeleteAutoscalingPolicy(processGuid string) error {
+	fake.deleteAutoscalingPolicyMutex.Lock()
+	fake.deleteAutoscalingPolicyArgsForCall = append(fake.deleteAutoscalingPolicyArgsForCall, struct {
+		processGuid string
+	}{processGuid})
+	fake.deleteAutoscalingPolicyMutex.Unlock()
+	if fake.DeleteAutoscalingPolicyStub != nil {
+		return fake.DeleteAutoscalingPolicyStub(processGuid)
+	} else {
+		return fake.deleteAutoscalingPolicyReturns.result1
+	}
+}
+
+func (fake *FakeClient) DeleteAutoscalingPolicyCallCount() int {
+	fake.deleteAutoscalingPolicyMutex.RLock()
+	defer fake.deleteAutoscalingPolicyMutex.RUnlock()
+	return len(fake.deleteAutoscalingPolicyArgsForCall)
+}
+
+func (fake *FakeClient) DeleteAutoscalingPolicyArgsForCall(i int) string {
+	fake.deleteAutoscalingPolicyMutex.RLock()
+	defer fake.deleteAutoscalingPolicyMutex.RUnlock()
+	return fake.deleteAutoscalingPolicyArgsForCall[i].processGuid
+}
+
+func (fake *FakeClient) DeleteAutoscalingPolicyReturns(result1 error) {
+	fake.DeleteAutoscalingPolicyStub = nil
+	fake.deleteAutoscalingPolicyReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) DesiredLRPs() ([]receptor.DesiredLRPResponse, error) {
+	fake.desiredLRPsMutex.Lock()
+	fake.desiredLRPsArgsForCall = append(fake.desiredLRPsArgsForCall, struct{}{})
+	fake.desiredLRPsMutex.Unlock()
+	if fake.DesiredLRPsStub != nil {
+		return fake.DesiredLRPsStub()
+	} else {
+		return fake.desiredLRPsReturns.result1, fake.desiredLRPsReturns.result2
+	}
+}
+
+func (fake *FakeClient) DesiredLRPsCallCount() int {
+	fake.desiredLRPsMutex.RLock()
 	defer fake.desiredLRPsMutex.RUnlock()
 	return len(fake.desiredLRPsArgsForCall)
 }
@@ -579,6 +1486,198 @@ func (fake *FakeClient) DesiredLRPsByDomainReturns(result1 []receptor.DesiredLRP
 	}{result1, result2}
 }
 
+func (fake *FakeClient) PagedDesiredLRPs(domain string, pageSize int) ([]receptor.DesiredLRPResponse, error) {
+	fake.pagedDesiredLRPsMutex.Lock()
+	fake.pagedDesiredLRPsArgsForCall = append(fake.pagedDesiredLRPsArgsForCall, struct {
+		domain   string
+		pageSize int
+	}{domain, pageSize})
+	fake.pagedDesiredLRPsMutex.Unlock()
+	if fake.PagedDesiredLRPsStub != nil {
+		return fake.PagedDesiredLRPsStub(domain, pageSize)
+	} else {
+		return fake.pagedDesiredLRPsReturns.result1, fake.pagedDesiredLRPsReturns.result2
+	}
+}
+
+func (fake *FakeClient) PagedDesiredLRPsCallCount() int {
+	fake.pagedDesiredLRPsMutex.RLock()
+	defer fake.pagedDesiredLRPsMutex.RUnlock()
+	return len(fake.pagedDesiredLRPsArgsForCall)
+}
+
+func (fake *FakeClient) PagedDesiredLRPsArgsForCall(i int) (string, int) {
+	fake.pagedDesiredLRPsMutex.RLock()
+	defer fake.pagedDesiredLRPsMutex.RUnlock()
+	return fake.pagedDesiredLRPsArgsForCall[i].domain, fake.pagedDesiredLRPsArgsForCall[i].pageSize
+}
+
+func (fake *FakeClient) PagedDesiredLRPsReturns(result1 []receptor.DesiredLRPResponse, result2 error) {
+	fake.PagedDesiredLRPsStub = nil
+	fake.pagedDesiredLRPsReturns = struct {
+		result1 []receptor.DesiredLRPResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DesiredLRPSummaries() ([]receptor.DesiredLRPSummaryResponse, error) {
+	fake.desiredLRPSummariesMutex.Lock()
+	fake.desiredLRPSummariesArgsForCall = append(fake.desiredLRPSummariesArgsForCall, struct{}{})
+	fake.desiredLRPSummariesMutex.Unlock()
+	if fake.DesiredLRPSummariesStub != nil {
+		return fake.DesiredLRPSummariesStub()
+	} else {
+		return fake.desiredLRPSummariesReturns.result1, fake.desiredLRPSummariesReturns.result2
+	}
+}
+
+func (fake *FakeClient) DesiredLRPSummariesCallCount() int {
+	fake.desiredLRPSummariesMutex.RLock()
+	defer fake.desiredLRPSummariesMutex.RUnlock()
+	return len(fake.desiredLRPSummariesArgsForCall)
+}
+
+func (fake *FakeClient) DesiredLRPSummariesReturns(result1 []receptor.DesiredLRPSummaryResponse, result2 error) {
+	fake.DesiredLRPSummariesStub = nil
+	fake.desiredLRPSummariesReturns = struct {
+		result1 []receptor.DesiredLRPSummaryResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) DesiredLRPSummariesByDomain(domain string) ([]receptor.DesiredLRPSummaryResponse, error) {
+	fake.desiredLRPSummariesByDomainMutex.Lock()
+	fake.desiredLRPSummariesByDomainArgsForCall = append(fake.desiredLRPSummariesByDomainArgsForCall, struct {
+		domain string
+	}{domain})
+	fake.desiredLRPSummariesByDomainMutex.Unlock()
+	if fake.DesiredLRPSummariesByDomainStub != nil {
+		return fake.DesiredLRPSummariesByDomainStub(domain)
+	} else {
+		return fake.desiredLRPSummariesByDomainReturns.result1, fake.desiredLRPSummariesByDomainReturns.result2
+	}
+}
+
+func (fake *FakeClient) DesiredLRPSummariesByDomainCallCount() int {
+	fake.desiredLRPSummariesByDomainMutex.RLock()
+	defer fake.desiredLRPSummariesByDomainMutex.RUnlock()
+	return len(fake.desiredLRPSummariesByDomainArgsForCall)
+}
+
+func (fake *FakeClient) DesiredLRPSummariesByDomainArgsForCall(i int) string {
+	fake.desiredLRPSummariesByDomainMutex.RLock()
+	defer fake.desiredLRPSummariesByDomainMutex.RUnlock()
+	return fake.desiredLRPSummariesByDomainArgsForCall[i].domain
+}
+
+func (fake *FakeClient) DesiredLRPSummariesByDomainReturns(result1 []receptor.DesiredLRPSummaryResponse, result2 error) {
+	fake.DesiredLRPSummariesByDomainStub = nil
+	fake.desiredLRPSummariesByDomainReturns = struct {
+		result1 []receptor.DesiredLRPSummaryResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) StartRollingUpdate(processGuid string, request receptor.RollingUpdateRequest) (receptor.RollingUpdateResponse, error) {
+	fake.startRollingUpdateMutex.Lock()
+	fake.startRollingUpdateArgsForCall = append(fake.startRollingUpdateArgsForCall, struct {
+		processGuid string
+		request     receptor.RollingUpdateRequest
+	}{processGuid, request})
+	fake.startRollingUpdateMutex.Unlock()
+	if fake.StartRollingUpdateStub != nil {
+		return fake.StartRollingUpdateStub(processGuid, request)
+	} else {
+		return fake.startRollingUpdateReturns.result1, fake.startRollingUpdateReturns.result2
+	}
+}
+
+func (fake *FakeClient) StartRollingUpdateCallCount() int {
+	fake.startRollingUpdateMutex.RLock()
+	defer fake.startRollingUpdateMutex.RUnlock()
+	return len(fake.startRollingUpdateArgsForCall)
+}
+
+func (fake *FakeClient) StartRollingUpdateArgsForCall(i int) (string, receptor.RollingUpdateRequest) {
+	fake.startRollingUpdateMutex.RLock()
+	defer fake.startRollingUpdateMutex.RUnlock()
+	return fake.startRollingUpdateArgsForCall[i].processGuid, fake.startRollingUpdateArgsForCall[i].request
+}
+
+func (fake *FakeClient) StartRollingUpdateReturns(result1 receptor.RollingUpdateResponse, result2 error) {
+	fake.StartRollingUpdateStub = nil
+	fake.startRollingUpdateReturns = struct {
+		result1 receptor.RollingUpdateResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) GetRollingUpdate(processGuid string) (receptor.RollingUpdateResponse, error) {
+	fake.getRollingUpdateMutex.Lock()
+	fake.getRollingUpdateArgsForCall = append(fake.getRollingUpdateArgsForCall, struct {
+		processGuid string
+	}{processGuid})
+	fake.getRollingUpdateMutex.Unlock()
+	if fake.GetRollingUpdateStub != nil {
+		return fake.GetRollingUpdateStub(processGuid)
+	} else {
+		return fake.getRollingUpdateReturns.result1, fake.getRollingUpdateReturns.result2
+	}
+}
+
+func (fake *FakeClient) GetRollingUpdateCallCount() int {
+	fake.getRollingUpdateMutex.RLock()
+	defer fake.getRollingUpdateMutex.RUnlock()
+	return len(fake.getRollingUpdateArgsForCall)
+}
+
+func (fake *FakeClient) GetRollingUpdateArgsForCall(i int) string {
+	fake.getRollingUpdateMutex.RLock()
+	defer fake.getRollingUpdateMutex.RUnlock()
+	return fake.getRollingUpdateArgsForCall[i].processGuid
+}
+
+func (fake *FakeClient) GetRollingUpdateReturns(result1 receptor.RollingUpdateResponse, result2 error) {
+	fake.GetRollingUpdateStub = nil
+	fake.getRollingUpdateReturns = struct {
+		result1 receptor.RollingUpdateResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) AbortRollingUpdate(processGuid string) (receptor.RollingUpdateResponse, error) {
+	fake.abortRollingUpdateMutex.Lock()
+	fake.abortRollingUpdateArgsForCall = append(fake.abortRollingUpdateArgsForCall, struct {
+		processGuid string
+	}{processGuid})
+	fake.abortRollingUpdateMutex.Unlock()
+	if fake.AbortRollingUpdateStub != nil {
+		return fake.AbortRollingUpdateStub(processGuid)
+	} else {
+		return fake.abortRollingUpdateReturns.result1, fake.abortRollingUpdateReturns.result2
+	}
+}
+
+func (fake *FakeClient) AbortRollingUpdateCallCount() int {
+	fake.abortRollingUpdateMutex.RLock()
+	defer fake.abortRollingUpdateMutex.RUnlock()
+	return len(fake.abortRollingUpdateArgsForCall)
+}
+
+func (fake *FakeClient) AbortRollingUpdateArgsForCall(i int) string {
+	fake.abortRollingUpdateMutex.RLock()
+	defer fake.abortRollingUpdateMutex.RUnlock()
+	return fake.abortRollingUpdateArgsForCall[i].processGuid
+}
+
+func (fake *FakeClient) AbortRollingUpdateReturns(result1 receptor.RollingUpdateResponse, result2 error) {
+	fake.AbortRollingUpdateStub = nil
+	fake.abortRollingUpdateReturns = struct {
+		result1 receptor.RollingUpdateResponse
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClient) ActualLRPs() ([]receptor.ActualLRPResponse, error) {
 	fake.actualLRPsMutex.Lock()
 	fake.actualLRPsArgsForCall = append(fake.actualLRPsArgsForCall, struct{}{})
@@ -637,6 +1736,73 @@ func (fake *FakeClient) ActualLRPsByDomainReturns(result1 []receptor.ActualLRPRe
 	}{result1, result2}
 }
 
+func (fake *FakeClient) ActualLRPsWithFilter(filter receptor.ActualLRPFilter) ([]receptor.ActualLRPResponse, error) {
+	fake.actualLRPsWithFilterMutex.Lock()
+	fake.actualLRPsWithFilterArgsForCall = append(fake.actualLRPsWithFilterArgsForCall, struct {
+		filter receptor.ActualLRPFilter
+	}{filter})
+	fake.actualLRPsWithFilterMutex.Unlock()
+	if fake.ActualLRPsWithFilterStub != nil {
+		return fake.ActualLRPsWithFilterStub(filter)
+	} else {
+		return fake.actualLRPsWithFilterReturns.result1, fake.actualLRPsWithFilterReturns.result2
+	}
+}
+
+func (fake *FakeClient) ActualLRPsWithFilterCallCount() int {
+	fake.actualLRPsWithFilterMutex.RLock()
+	defer fake.actualLRPsWithFilterMutex.RUnlock()
+	return len(fake.actualLRPsWithFilterArgsForCall)
+}
+
+func (fake *FakeClient) ActualLRPsWithFilterArgsForCall(i int) receptor.ActualLRPFilter {
+	fake.actualLRPsWithFilterMutex.RLock()
+	defer fake.actualLRPsWithFilterMutex.RUnlock()
+	return fake.actualLRPsWithFilterArgsForCall[i].filter
+}
+
+func (fake *FakeClient) ActualLRPsWithFilterReturns(result1 []receptor.ActualLRPResponse, result2 error) {
+	fake.ActualLRPsWithFilterStub = nil
+	fake.actualLRPsWithFilterReturns = struct {
+		result1 []receptor.ActualLRPResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) PagedActualLRPs(domain string, pageSize int) ([]receptor.ActualLRPResponse, error) {
+	fake.pagedActualLRPsMutex.Lock()
+	fake.pagedActualLRPsArgsForCall = append(fake.pagedActualLRPsArgsForCall, struct {
+		domain   string
+		pageSize int
+	}{domain, pageSize})
+	fake.pagedActualLRPsMutex.Unlock()
+	if fake.PagedActualLRPsStub != nil {
+		return fake.PagedActualLRPsStub(domain, pageSize)
+	} else {
+		return fake.pagedActualLRPsReturns.result1, fake.pagedActualLRPsReturns.result2
+	}
+}
+
+func (fake *FakeClient) PagedActualLRPsCallCount() int {
+	fake.pagedActualLRPsMutex.RLock()
+	defer fake.pagedActualLRPsMutex.RUnlock()
+	return len(fake.pagedActualLRPsArgsForCall)
+}
+
+func (fake *FakeClient) PagedActualLRPsArgsForCall(i int) (string, int) {
+	fake.pagedActualLRPsMutex.RLock()
+	defer fake.pagedActualLRPsMutex.RUnlock()
+	return fake.pagedActualLRPsArgsForCall[i].domain, fake.pagedActualLRPsArgsForCall[i].pageSize
+}
+
+func (fake *FakeClient) PagedActualLRPsReturns(result1 []receptor.ActualLRPResponse, result2 error) {
+	fake.PagedActualLRPsStub = nil
+	fake.pagedActualLRPsReturns = struct {
+		result1 []receptor.ActualLRPResponse
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClient) ActualLRPsByProcessGuid(processGuid string) ([]receptor.ActualLRPResponse, error) {
 	fake.actualLRPsByProcessGuidMutex.Lock()
 	fake.actualLRPsByProcessGuidArgsForCall = append(fake.actualLRPsByProcessGuidArgsForCall, struct {
@@ -704,6 +1870,131 @@ func (fake *FakeClient) ActualLRPByProcessGuidAndIndexReturns(result1 receptor.A
 	}{result1, result2}
 }
 
+func (fake *FakeClient) ActualLRPGroups() ([]receptor.ActualLRPGroupResponse, error) {
+	fake.actualLRPGroupsMutex.Lock()
+	fake.actualLRPGroupsArgsForCall = append(fake.actualLRPGroupsArgsForCall, struct{}{})
+	fake.actualLRPGroupsMutex.Unlock()
+	if fake.ActualLRPGroupsStub != nil {
+		return fake.ActualLRPGroupsStub()
+	} else {
+		return fake.actualLRPGroupsReturns.result1, fake.actualLRPGroupsReturns.result2
+	}
+}
+
+func (fake *FakeClient) ActualLRPGroupsCallCount() int {
+	fake.actualLRPGroupsMutex.RLock()
+	defer fake.actualLRPGroupsMutex.RUnlock()
+	return len(fake.actualLRPGroupsArgsForCall)
+}
+
+func (fake *FakeClient) ActualLRPGroupsReturns(result1 []receptor.ActualLRPGroupResponse, result2 error) {
+	fake.ActualLRPGroupsStub = nil
+	fake.actualLRPGroupsReturns = struct {
+		result1 []receptor.ActualLRPGroupResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ActualLRPGroupsByDomain(domain string) ([]receptor.ActualLRPGroupResponse, error) {
+	fake.actualLRPGroupsByDomainMutex.Lock()
+	fake.actualLRPGroupsByDomainArgsForCall = append(fake.actualLRPGroupsByDomainArgsForCall, struct {
+		domain string
+	}{domain})
+	fake.actualLRPGroupsByDomainMutex.Unlock()
+	if fake.ActualLRPGroupsByDomainStub != nil {
+		return fake.ActualLRPGroupsByDomainStub(domain)
+	} else {
+		return fake.actualLRPGroupsByDomainReturns.result1, fake.actualLRPGroupsByDomainReturns.result2
+	}
+}
+
+func (fake *FakeClient) ActualLRPGroupsByDomainCallCount() int {
+	fake.actualLRPGroupsByDomainMutex.RLock()
+	defer fake.actualLRPGroupsByDomainMutex.RUnlock()
+	return len(fake.actualLRPGroupsByDomainArgsForCall)
+}
+
+func (fake *FakeClient) ActualLRPGroupsByDomainArgsForCall(i int) string {
+	fake.actualLRPGroupsByDomainMutex.RLock()
+	defer fake.actualLRPGroupsByDomainMutex.RUnlock()
+	return fake.actualLRPGroupsByDomainArgsForCall[i].domain
+}
+
+func (fake *FakeClient) ActualLRPGroupsByDomainReturns(result1 []receptor.ActualLRPGroupResponse, result2 error) {
+	fake.ActualLRPGroupsByDomainStub = nil
+	fake.actualLRPGroupsByDomainReturns = struct {
+		result1 []receptor.ActualLRPGroupResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ActualLRPGroupsByProcessGuid(processGuid string) ([]receptor.ActualLRPGroupResponse, error) {
+	fake.actualLRPGroupsByProcessGuidMutex.Lock()
+	fake.actualLRPGroupsByProcessGuidArgsForCall = append(fake.actualLRPGroupsByProcessGuidArgsForCall, struct {
+		processGuid string
+	}{processGuid})
+	fake.actualLRPGroupsByProcessGuidMutex.Unlock()
+	if fake.ActualLRPGroupsByProcessGuidStub != nil {
+		return fake.ActualLRPGroupsByProcessGuidStub(processGuid)
+	} else {
+		return fake.actualLRPGroupsByProcessGuidReturns.result1, fake.actualLRPGroupsByProcessGuidReturns.result2
+	}
+}
+
+func (fake *FakeClient) ActualLRPGroupsByProcessGuidCallCount() int {
+	fake.actualLRPGroupsByProcessGuidMutex.RLock()
+	defer fake.actualLRPGroupsByProcessGuidMutex.RUnlock()
+	return len(fake.actualLRPGroupsByProcessGuidArgsForCall)
+}
+
+func (fake *FakeClient) ActualLRPGroupsByProcessGuidArgsForCall(i int) string {
+	fake.actualLRPGroupsByProcessGuidMutex.RLock()
+	defer fake.actualLRPGroupsByProcessGuidMutex.RUnlock()
+	return fake.actualLRPGroupsByProcessGuidArgsForCall[i].processGuid
+}
+
+func (fake *FakeClient) ActualLRPGroupsByProcessGuidReturns(result1 []receptor.ActualLRPGroupResponse, result2 error) {
+	fake.ActualLRPGroupsByProcessGuidStub = nil
+	fake.actualLRPGroupsByProcessGuidReturns = struct {
+		result1 []receptor.ActualLRPGroupResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ActualLRPGroupByProcessGuidAndIndex(processGuid string, index int) (receptor.ActualLRPGroupResponse, error) {
+	fake.actualLRPGroupByProcessGuidAndIndexMutex.Lock()
+	fake.actualLRPGroupByProcessGuidAndIndexArgsForCall = append(fake.actualLRPGroupByProcessGuidAndIndexArgsForCall, struct {
+		processGuid string
+		index       int
+	}{processGuid, index})
+	fake.actualLRPGroupByProcessGuidAndIndexMutex.Unlock()
+	if fake.ActualLRPGroupByProcessGuidAndIndexStub != nil {
+		return fake.ActualLRPGroupByProcessGuidAndIndexStub(processGuid, index)
+	} else {
+		return fake.actualLRPGroupByProcessGuidAndIndexReturns.result1, fake.actualLRPGroupByProcessGuidAndIndexReturns.result2
+	}
+}
+
+func (fake *FakeClient) ActualLRPGroupByProcessGuidAndIndexCallCount() int {
+	fake.actualLRPGroupByProcessGuidAndIndexMutex.RLock()
+	defer fake.actualLRPGroupByProcessGuidAndIndexMutex.RUnlock()
+	return len(fake.actualLRPGroupByProcessGuidAndIndexArgsForCall)
+}
+
+func (fake *FakeClient) ActualLRPGroupByProcessGuidAndIndexArgsForCall(i int) (string, int) {
+	fake.actualLRPGroupByProcessGuidAndIndexMutex.RLock()
+	defer fake.actualLRPGroupByProcessGuidAndIndexMutex.RUnlock()
+	return fake.actualLRPGroupByProcessGuidAndIndexArgsForCall[i].processGuid, fake.actualLRPGroupByProcessGuidAndIndexArgsForCall[i].index
+}
+
+func (fake *FakeClient) ActualLRPGroupByProcessGuidAndIndexReturns(result1 receptor.ActualLRPGroupResponse, result2 error) {
+	fake.ActualLRPGroupByProcessGuidAndIndexStub = nil
+	fake.actualLRPGroupByProcessGuidAndIndexReturns = struct {
+		result1 receptor.ActualLRPGroupResponse
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClient) KillActualLRPByProcessGuidAndIndex(processGuid string, index int) error {
 	fake.killActualLRPByProcessGuidAndIndexMutex.Lock()
 	fake.killActualLRPByProcessGuidAndIndexArgsForCall = append(fake.killActualLRPByProcessGuidAndIndexArgsForCall, struct {
@@ -737,6 +2028,132 @@ func (fake *FakeClient) KillActualLRPByProcessGuidAndIndexReturns(result1 error)
 	}{result1}
 }
 
+func (fake *FakeClient) KillActualLRPsByProcessGuid(processGuid string, maxInFlight int) (receptor.ActualLRPsKillResponse, error) {
+	fake.killActualLRPsByProcessGuidMutex.Lock()
+	fake.killActualLRPsByProcessGuidArgsForCall = append(fake.killActualLRPsByProcessGuidArgsForCall, struct {
+		processGuid string
+		maxInFlight int
+	}{processGuid, maxInFlight})
+	fake.killActualLRPsByProcessGuidMutex.Unlock()
+	if fake.KillActualLRPsByProcessGuidStub != nil {
+		return fake.KillActualLRPsByProcessGuidStub(processGuid, maxInFlight)
+	} else {
+		return fake.killActualLRPsByProcessGuidReturns.result1, fake.killActualLRPsByProcessGuidReturns.result2
+	}
+}
+
+func (fake *FakeClient) KillActualLRPsByProcessGuidCallCount() int {
+	fake.killActualLRPsByProcessGuidMutex.RLock()
+	defer fake.killActualLRPsByProcessGuidMutex.RUnlock()
+	return len(fake.killActualLRPsByProcessGuidArgsForCall)
+}
+
+func (fake *FakeClient) KillActualLRPsByProcessGuidArgsForCall(i int) (string, int) {
+	fake.killActualLRPsByProcessGuidMutex.RLock()
+	defer fake.killActualLRPsByProcessGuidMutex.RUnlock()
+	return fake.killActualLRPsByProcessGuidArgsForCall[i].processGuid, fake.killActualLRPsByProcessGuidArgsForCall[i].maxInFlight
+}
+
+func (fake *FakeClient) KillActualLRPsByProcessGuidReturns(result1 receptor.ActualLRPsKillResponse, result2 error) {
+	fake.KillActualLRPsByProcessGuidStub = nil
+	fake.killActualLRPsByProcessGuidReturns = struct {
+		result1 receptor.ActualLRPsKillResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) KillActualLRPByInstanceGuid(processGuid string, instanceGuid string) error {
+	fake.killActualLRPByInstanceGuidMutex.Lock()
+	fake.killActualLRPByInstanceGuidArgsForCall = append(fake.killActualLRPByInstanceGuidArgsForCall, struct {
+		processGuid  string
+		instanceGuid string
+	}{processGuid, instanceGuid})
+	fake.killActualLRPByInstanceGuidMutex.Unlock()
+	if fake.KillActualLRPByInstanceGuidStub != nil {
+		return fake.KillActualLRPByInstanceGuidStub(processGuid, instanceGuid)
+	} else {
+		return fake.killActualLRPByInstanceGuidReturns.result1
+	}
+}
+
+func (fake *FakeClient) KillActualLRPByInstanceGuidCallCount() int {
+	fake.killActualLRPByInstanceGuidMutex.RLock()
+	defer fake.killActualLRPByInstanceGuidMutex.RUnlock()
+	return len(fake.killActualLRPByInstanceGuidArgsForCall)
+}
+
+func (fake *FakeClient) KillActualLRPByInstanceGuidArgsForCall(i int) (string, string) {
+	fake.killActualLRPByInstanceGuidMutex.RLock()
+	defer fake.killActualLRPByInstanceGuidMutex.RUnlock()
+	return fake.killActualLRPByInstanceGuidArgsForCall[i].processGuid, fake.killActualLRPByInstanceGuidArgsForCall[i].instanceGuid
+}
+
+func (fake *FakeClient) KillActualLRPByInstanceGuidReturns(result1 error) {
+	fake.KillActualLRPByInstanceGuidStub = nil
+	fake.killActualLRPByInstanceGuidReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) ActualLRPCrashes(processGuid string, index int) ([]receptor.ActualLRPCrashResponse, error) {
+	fake.actualLRPCrashesMutex.Lock()
+	fake.actualLRPCrashesArgsForCall = append(fake.actualLRPCrashesArgsForCall, struct {
+		processGuid string
+		index       int
+	}{processGuid, index})
+	fake.actualLRPCrashesMutex.Unlock()
+	if fake.ActualLRPCrashesStub != nil {
+		return fake.ActualLRPCrashesStub(processGuid, index)
+	} else {
+		return fake.actualLRPCrashesReturns.result1, fake.actualLRPCrashesReturns.result2
+	}
+}
+
+func (fake *FakeClient) ActualLRPCrashesCallCount() int {
+	fake.actualLRPCrashesMutex.RLock()
+	defer fake.actualLRPCrashesMutex.RUnlock()
+	return len(fake.actualLRPCrashesArgsForCall)
+}
+
+func (fake *FakeClient) ActualLRPCrashesArgsForCall(i int) (string, int) {
+	fake.actualLRPCrashesMutex.RLock()
+	defer fake.actualLRPCrashesMutex.RUnlock()
+	return fake.actualLRPCrashesArgsForCall[i].processGuid, fake.actualLRPCrashesArgsForCall[i].index
+}
+
+func (fake *FakeClient) ActualLRPCrashesReturns(result1 []receptor.ActualLRPCrashResponse, result2 error) {
+	fake.ActualLRPCrashesStub = nil
+	fake.actualLRPCrashesReturns = struct {
+		result1 []receptor.ActualLRPCrashResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) CrashingActualLRPs() ([]receptor.CrashingActualLRPResponse, error) {
+	fake.crashingActualLRPsMutex.Lock()
+	fake.crashingActualLRPsArgsForCall = append(fake.crashingActualLRPsArgsForCall, struct{}{})
+	fake.crashingActualLRPsMutex.Unlock()
+	if fake.CrashingActualLRPsStub != nil {
+		return fake.CrashingActualLRPsStub()
+	} else {
+		return fake.crashingActualLRPsReturns.result1, fake.crashingActualLRPsReturns.result2
+	}
+}
+
+func (fake *FakeClient) CrashingActualLRPsCallCount() int {
+	fake.crashingActualLRPsMutex.RLock()
+	defer fake.crashingActualLRPsMutex.RUnlock()
+	return len(fake.crashingActualLRPsArgsForCall)
+}
+
+func (fake *FakeClient) CrashingActualLRPsReturns(result1 []receptor.CrashingActualLRPResponse, result2 error) {
+	fake.CrashingActualLRPsStub = nil
+	fake.crashingActualLRPsReturns = struct {
+		result1 []receptor.CrashingActualLRPResponse
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClient) SubscribeToEvents() (receptor.EventSource, error) {
 	fake.subscribeToEventsMutex.Lock()
 	fake.subscribeToEventsArgsForCall = append(fake.subscribeToEventsArgsForCall, struct{}{})
@@ -762,6 +2179,39 @@ func (fake *FakeClient) SubscribeToEventsReturns(result1 receptor.EventSource, r
 	}{result1, result2}
 }
 
+func (fake *FakeClient) SubscribeToEventsWithFilter(filter receptor.EventFilter) (receptor.EventSource, error) {
+	fake.subscribeToEventsWithFilterMutex.Lock()
+	fake.subscribeToEventsWithFilterArgsForCall = append(fake.subscribeToEventsWithFilterArgsForCall, struct {
+		filter receptor.EventFilter
+	}{filter})
+	fake.subscribeToEventsWithFilterMutex.Unlock()
+	if fake.SubscribeToEventsWithFilterStub != nil {
+		return fake.SubscribeToEventsWithFilterStub(filter)
+	} else {
+		return fake.subscribeToEventsWithFilterReturns.result1, fake.subscribeToEventsWithFilterReturns.result2
+	}
+}
+
+func (fake *FakeClient) SubscribeToEventsWithFilterCallCount() int {
+	fake.subscribeToEventsWithFilterMutex.RLock()
+	defer fake.subscribeToEventsWithFilterMutex.RUnlock()
+	return len(fake.subscribeToEventsWithFilterArgsForCall)
+}
+
+func (fake *FakeClient) SubscribeToEventsWithFilterArgsForCall(i int) receptor.EventFilter {
+	fake.subscribeToEventsWithFilterMutex.RLock()
+	defer fake.subscribeToEventsWithFilterMutex.RUnlock()
+	return fake.subscribeToEventsWithFilterArgsForCall[i].filter
+}
+
+func (fake *FakeClient) SubscribeToEventsWithFilterReturns(result1 receptor.EventSource, result2 error) {
+	fake.SubscribeToEventsWithFilterStub = nil
+	fake.subscribeToEventsWithFilterReturns = struct {
+		result1 receptor.EventSource
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClient) Cells() ([]receptor.CellResponse, error) {
 	fake.cellsMutex.Lock()
 	fake.cellsArgsForCall = append(fake.cellsArgsForCall, struct{}{})
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/bbs/models"
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/serialization"
+	"github.com/pivotal-golang/lager"
+)
+
+func actualLRPFilterFromRequest(req *http.Request) (receptor.ActualLRPFilter, error) {
+	filter := receptor.ActualLRPFilter{
+		Domain: req.FormValue("domain"),
+		CellID: req.FormValue("cell_id"),
+		State:  receptor.ActualLRPState(req.FormValue("state")),
+		Zone:   req.FormValue("zone"),
+	}
+
+	switch filter.State {
+	case "",
+		receptor.ActualLRPStateUnclaimed,
+		receptor.ActualLRPStateClaimed,
+		receptor.ActualLRPStateRunning,
+		receptor.ActualLRPStateCrashed:
+	default:
+		return receptor.ActualLRPFilter{}, fmt.Errorf("invalid actual lrp state: %s", filter.State)
+	}
+
+	return filter, nil
+}
+
+// cellIDsInZone returns the ids of the cells currently present in zone.
+func (h *ActualLRPHandler) cellIDsInZone(logger lager.Logger, zone string) (map[string]bool, error) {
+	cellPresences, err := h.serviceClient.Cells(logger)
+	if err != nil {
+		return nil, err
+	}
+
+	cellIDs := make(map[string]bool)
+	for _, cellPresence := range cellPresences {
+		if cellPresence.Zone == zone {
+			cellIDs[cellPresence.CellID] = true
+		}
+	}
+
+	return cellIDs, nil
+}
+
+// filterActualLRPGroups applies the filter to the ActualLRP each group
+// resolves to, which is the one returned unless groups are requested, so that
+// state, cell and zone all describe the same ActualLRP. The BBS has already
+// filtered by domain and cell, but it matches a cell on either ActualLRP of a
+// group. zoneCellIDs holds the cells in the filter's zone, if it has one.
+func filterActualLRPGroups(actualLRPGroups []*models.ActualLRPGroup, filter receptor.ActualLRPFilter, zoneCellIDs map[string]bool) []*models.ActualLRPGroup {
+	if filter.State == "" && filter.CellID == "" && filter.Zone == "" {
+		return actualLRPGroups
+	}
+
+	filtered := make([]*models.ActualLRPGroup, 0, len(actualLRPGroups))
+	for _, actualLRPGroup := range actualLRPGroups {
+		actualLRP, _ := actualLRPGroup.Resolve()
+		if actualLRP == nil {
+			continue
+		}
+		if filter.State != "" && serialization.ActualLRPStateToResponseState(actualLRP.State) != filter.State {
+			continue
+		}
+		if filter.CellID != "" && actualLRP.CellId != filter.CellID {
+			continue
+		}
+		if filter.Zone != "" && !zoneCellIDs[actualLRP.CellId] {
+			continue
+		}
+		filtered = append(filtered, actualLRPGroup)
+	}
+
+	return filtered
+}
@@ -4,7 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
+	"sync"
 
 	"github.com/cloudfoundry-incubator/bbs"
 	"github.com/cloudfoundry-incubator/bbs/models"
@@ -14,39 +16,69 @@ import (
 )
 
 type ActualLRPHandler struct {
-	bbs    bbs.Client
-	logger lager.Logger
+	bbs           bbs.Client
+	serviceClient bbs.ServiceClient
+	logger        lager.Logger
 }
 
-func NewActualLRPHandler(bbs bbs.Client, logger lager.Logger) *ActualLRPHandler {
+func NewActualLRPHandler(bbs bbs.Client, serviceClient bbs.ServiceClient, logger lager.Logger) *ActualLRPHandler {
 	return &ActualLRPHandler{
-		bbs:    bbs,
-		logger: logger.Session("actual-lrp-handler"),
+		bbs:           bbs,
+		serviceClient: serviceClient,
+		logger:        logger.Session("actual-lrp-handler"),
 	}
 }
 
 func (h *ActualLRPHandler) GetAll(w http.ResponseWriter, req *http.Request) {
-	domain := req.FormValue("domain")
 	logger := h.logger.Session("get-all", lager.Data{
-		"domain": domain,
+		"domain":  req.FormValue("domain"),
+		"cell-id": req.FormValue("cell_id"),
+		"state":   req.FormValue("state"),
+		"zone":    req.FormValue("zone"),
 	})
 
-	filter := models.ActualLRPFilter{Domain: domain}
-	actualLRPGroups, err := h.bbs.ActualLRPGroups(filter)
+	filter, err := actualLRPFilterFromRequest(req)
+	if err != nil {
+		logger.Error("invalid-filter", err)
+		writeBadRequestResponse(w, receptor.InvalidRequest, err)
+		return
+	}
+
+	page, err := paginationFromRequest(req)
+	if err != nil {
+		logger.Error("invalid-pagination", err)
+		writeBadRequestResponse(w, receptor.InvalidRequest, err)
+		return
+	}
 
+	groups, err := groupsViewFromRequest(req)
+	if err != nil {
+		logger.Error("invalid-groups", err)
+		writeBadRequestResponse(w, receptor.InvalidRequest, err)
+		return
+	}
+
+	actualLRPGroups, err := h.bbs.ActualLRPGroups(models.ActualLRPFilter{Domain: filter.Domain, CellID: filter.CellID})
 	if err != nil {
 		logger.Error("failed-to-fetch-actual-lrp-groups", err)
 		writeUnknownErrorResponse(w, err)
 		return
 	}
 
-	responses := make([]receptor.ActualLRPResponse, 0, len(actualLRPGroups))
-	for _, actualLRPGroup := range actualLRPGroups {
-		lrp, evacuating := actualLRPGroup.Resolve()
-		responses = append(responses, serialization.ActualLRPProtoToResponse(lrp, evacuating))
+	var zoneCellIDs map[string]bool
+	if filter.Zone != "" {
+		zoneCellIDs, err = h.cellIDsInZone(logger, filter.Zone)
+		if err != nil {
+			logger.Error("failed-to-fetch-cells", err)
+			writeUnknownErrorResponse(w, err)
+			return
+		}
 	}
 
-	writeJSONResponse(w, http.StatusOK, responses)
+	actualLRPGroups = filterActualLRPGroups(actualLRPGroups, filter, zoneCellIDs)
+	actualLRPGroups = paginateActualLRPGroups(w, req, page, actualLRPGroups)
+
+	writeActualLRPGroupsResponse(w, req, actualLRPGroups, groups)
 }
 
 func (h *ActualLRPHandler) GetAllByProcessGuid(w http.ResponseWriter, req *http.Request) {
@@ -62,6 +94,13 @@ func (h *ActualLRPHandler) GetAllByProcessGuid(w http.ResponseWriter, req *http.
 		return
 	}
 
+	groups, err := groupsViewFromRequest(req)
+	if err != nil {
+		logger.Error("invalid-groups", err)
+		writeBadRequestResponse(w, receptor.InvalidRequest, err)
+		return
+	}
+
 	actualLRPGroupsByIndex, err := h.bbs.ActualLRPGroupsByProcessGuid(processGuid)
 	if err != nil {
 		logger.Error("failed-to-fetch-actual-lrp-groups-by-process-guid", err)
@@ -69,13 +108,7 @@ func (h *ActualLRPHandler) GetAllByProcessGuid(w http.ResponseWriter, req *http.
 		return
 	}
 
-	responses := make([]receptor.ActualLRPResponse, 0, len(actualLRPGroupsByIndex))
-	for _, actualLRPGroup := range actualLRPGroupsByIndex {
-		lrp, evacuating := actualLRPGroup.Resolve()
-		responses = append(responses, serialization.ActualLRPProtoToResponse(lrp, evacuating))
-	}
-
-	writeJSONResponse(w, http.StatusOK, responses)
+	writeActualLRPGroupsResponse(w, req, actualLRPGroupsByIndex, groups)
 }
 
 func (h *ActualLRPHandler) GetByProcessGuidAndIndex(w http.ResponseWriter, req *http.Request) {
@@ -111,6 +144,13 @@ func (h *ActualLRPHandler) GetByProcessGuidAndIndex(w http.ResponseWriter, req *
 		return
 	}
 
+	groups, err := groupsViewFromRequest(req)
+	if err != nil {
+		logger.Error("invalid-groups", err)
+		writeBadRequestResponse(w, receptor.InvalidRequest, err)
+		return
+	}
+
 	actualLRPGroup, err := h.bbs.ActualLRPGroupByProcessGuidAndIndex(processGuid, index)
 	if err != nil {
 		bbsError := models.ConvertError(err)
@@ -123,9 +163,50 @@ func (h *ActualLRPHandler) GetByProcessGuidAndIndex(w http.ResponseWriter, req *
 		return
 	}
 
+	if groups {
+		writeCacheableJSONResponse(w, req, serialization.ActualLRPGroupProtoToResponse(actualLRPGroup))
+		return
+	}
+
 	actualLRP, evacuating := actualLRPGroup.Resolve()
+	response := serialization.ActualLRPProtoToResponse(actualLRP, evacuating)
+
+	w.Header().Set("ETag", response.ModificationTag.ETag())
+	writeCacheableJSONResponse(w, req, response)
+}
 
-	writeJSONResponse(w, http.StatusOK, serialization.ActualLRPProtoToResponse(actualLRP, evacuating))
+// groupsViewFromRequest reports whether the request asked for both the
+// instance and evacuating ActualLRP of each index with groups=true, rather
+// than the one which Resolve prefers.
+func groupsViewFromRequest(req *http.Request) (bool, error) {
+	groups := req.FormValue("groups")
+	if groups == "" {
+		return false, nil
+	}
+
+	value, err := strconv.ParseBool(groups)
+	if err != nil {
+		return false, fmt.Errorf("invalid groups: %s", groups)
+	}
+	return value, nil
+}
+
+func writeActualLRPGroupsResponse(w http.ResponseWriter, req *http.Request, actualLRPGroups []*models.ActualLRPGroup, groups bool) {
+	if groups {
+		responses := make([]receptor.ActualLRPGroupResponse, 0, len(actualLRPGroups))
+		for _, actualLRPGroup := range actualLRPGroups {
+			responses = append(responses, serialization.ActualLRPGroupProtoToResponse(actualLRPGroup))
+		}
+		writeCacheableJSONResponse(w, req, responses)
+		return
+	}
+
+	responses := make([]receptor.ActualLRPResponse, 0, len(actualLRPGroups))
+	for _, actualLRPGroup := range actualLRPGroups {
+		lrp, evacuating := actualLRPGroup.Resolve()
+		responses = append(responses, serialization.ActualLRPProtoToResponse(lrp, evacuating))
+	}
+	writeCacheableJSONResponse(w, req, responses)
 }
 
 func (h *ActualLRPHandler) KillByProcessGuidAndIndex(w http.ResponseWriter, req *http.Request) {
@@ -180,3 +261,173 @@ func (h *ActualLRPHandler) KillByProcessGuidAndIndex(w http.ResponseWriter, req
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// KillAllByProcessGuid kills every ActualLRP of a process, at most
+// max_in_flight at a time. Like killing a single index, this does not modify
+// the desired state, so the instances restart eventually.
+func (h *ActualLRPHandler) KillAllByProcessGuid(w http.ResponseWriter, req *http.Request) {
+	processGuid := req.FormValue(":process_guid")
+	logger := h.logger.Session("kill-all-by-process-guid", lager.Data{
+		"ProcessGuid": processGuid,
+	})
+
+	if processGuid == "" {
+		err := errors.New("process_guid missing from request")
+		logger.Error("missing-process-guid", err)
+		writeBadRequestResponse(w, receptor.InvalidRequest, err)
+		return
+	}
+
+	maxInFlight, err := maxInFlightFromRequest(req)
+	if err != nil {
+		logger.Error("invalid-max-in-flight", err)
+		writeBadRequestResponse(w, receptor.InvalidRequest, err)
+		return
+	}
+
+	actualLRPGroups, err := h.bbs.ActualLRPGroupsByProcessGuid(processGuid)
+	if err != nil {
+		logger.Error("failed-to-fetch-actual-lrp-groups-by-process-guid", err)
+		writeUnknownErrorResponse(w, err)
+		return
+	}
+
+	logger.Info("killing", lager.Data{"count": len(actualLRPGroups), "max-in-flight": maxInFlight})
+
+	actualLRPs := make([]*models.ActualLRP, len(actualLRPGroups))
+	for i, group := range actualLRPGroups {
+		actualLRPs[i], _ = group.Resolve()
+	}
+	sort.Sort(actualLRPsByIndex(actualLRPs))
+
+	failures := make([]error, len(actualLRPs))
+	throttle := make(chan struct{}, maxInFlight)
+	wg := sync.WaitGroup{}
+
+	for i := range actualLRPs {
+		wg.Add(1)
+		throttle <- struct{}{}
+
+		go func(i int) {
+			defer func() {
+				<-throttle
+				wg.Done()
+			}()
+
+			err := h.bbs.RetireActualLRP(&actualLRPs[i].ActualLRPKey)
+			if err != nil && models.ConvertError(err).Type != models.Error_ResourceNotFound {
+				logger.Error("failed-to-retire-actual-lrp", err, lager.Data{"index": actualLRPs[i].Index})
+				failures[i] = err
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	response := receptor.ActualLRPsKillResponse{
+		Killed: []int{},
+		Failed: []receptor.ActualLRPKillFailure{},
+	}
+	for i, actualLRP := range actualLRPs {
+		index := int(actualLRP.Index)
+		if failures[i] != nil {
+			response.Failed = append(response.Failed, receptor.ActualLRPKillFailure{
+				Index: index,
+				Error: receptor.Error{Type: receptor.UnknownError, Message: failures[i].Error()},
+			})
+			continue
+		}
+		response.Killed = append(response.Killed, index)
+	}
+
+	writeJSONResponse(w, http.StatusOK, response)
+}
+
+// KillByInstanceGuid kills a single ActualLRP, which may be the evacuating
+// one of its index.
+func (h *ActualLRPHandler) KillByInstanceGuid(w http.ResponseWriter, req *http.Request) {
+	processGuid := req.FormValue(":process_guid")
+	instanceGuid := req.FormValue(":instance_guid")
+	logger := h.logger.Session("kill-by-instance-guid", lager.Data{
+		"ProcessGuid":  processGuid,
+		"InstanceGuid": instanceGuid,
+	})
+
+	if processGuid == "" {
+		err := errors.New("process_guid missing from request")
+		logger.Error("missing-process-guid", err)
+		writeBadRequestResponse(w, receptor.InvalidRequest, err)
+		return
+	}
+
+	if instanceGuid == "" {
+		err := errors.New("instance_guid missing from request")
+		logger.Error("missing-instance-guid", err)
+		writeBadRequestResponse(w, receptor.InvalidRequest, err)
+		return
+	}
+
+	actualLRPGroups, err := h.bbs.ActualLRPGroupsByProcessGuid(processGuid)
+	if err != nil {
+		logger.Error("failed-to-fetch-actual-lrp-groups-by-process-guid", err)
+		writeUnknownErrorResponse(w, err)
+		return
+	}
+
+	for _, group := range actualLRPGroups {
+		if group.Instance != nil && group.Instance.InstanceGuid == instanceGuid {
+			err = h.bbs.RetireActualLRP(&group.Instance.ActualLRPKey)
+		} else if group.Evacuating != nil && group.Evacuating.InstanceGuid == instanceGuid {
+			err = h.bbs.RemoveEvacuatingActualLRP(&group.Evacuating.ActualLRPKey, &group.Evacuating.ActualLRPInstanceKey)
+		} else {
+			continue
+		}
+
+		if err != nil && models.ConvertError(err).Type != models.Error_ResourceNotFound {
+			logger.Error("failed-to-kill-actual-lrp", err)
+			writeUnknownErrorResponse(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	responseErr := fmt.Errorf("process-guid '%s' has no instance with guid '%s'", processGuid, instanceGuid)
+	logger.Error("no-instance-to-kill", responseErr)
+	writeJSONResponse(w, http.StatusNotFound, receptor.Error{
+		Type:    receptor.ActualLRPInstanceNotFound,
+		Message: responseErr.Error(),
+	})
+}
+
+type actualLRPsByIndex []*models.ActualLRP
+
+func (a actualLRPsByIndex) Len() int           { return len(a) }
+func (a actualLRPsByIndex) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a actualLRPsByIndex) Less(i, j int) bool { return a[i].Index < a[j].Index }
+
+func paginateActualLRPGroups(w http.ResponseWriter, req *http.Request, page pagination, groups []*models.ActualLRPGroup) []*models.ActualLRPGroup {
+	sort.Sort(actualLRPGroupsByKey(groups))
+
+	start, end := page.page(len(groups), func(i int) string { return actualLRPGroupSortKey(groups[i]) })
+	if end < len(groups) {
+		writeNextPageLink(w, req, actualLRPGroupSortKey(groups[end-1]))
+	}
+
+	return groups[start:end]
+}
+
+// actualLRPGroupSortKey orders groups by process guid and then by index.
+func actualLRPGroupSortKey(group *models.ActualLRPGroup) string {
+	lrp, _ := group.Resolve()
+	return fmt.Sprintf("%s\x00%010d", lrp.ProcessGuid, lrp.Index)
+}
+
+type actualLRPGroupsByKey []*models.ActualLRPGroup
+
+func (a actualLRPGroupsByKey) Len() int      { return len(a) }
+func (a actualLRPGroupsByKey) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a actualLRPGroupsByKey) Less(i, j int) bool {
+	return actualLRPGroupSortKey(a[i]) < actualLRPGroupSortKey(a[j])
+}
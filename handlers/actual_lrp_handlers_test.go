@@ -1,8 +1,10 @@
 package handlers_test
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -20,10 +22,11 @@ import (
 
 var _ = Describe("Actual LRP Handlers", func() {
 	var (
-		logger           lager.Logger
-		fakeBBS          *fake_bbs.FakeClient
-		responseRecorder *httptest.ResponseRecorder
-		handler          *handlers.ActualLRPHandler
+		logger            lager.Logger
+		fakeBBS           *fake_bbs.FakeClient
+		fakeServiceClient *fake_bbs.FakeServiceClient
+		responseRecorder  *httptest.ResponseRecorder
+		handler           *handlers.ActualLRPHandler
 
 		actualLRP1     *models.ActualLRP
 		actualLRP2     *models.ActualLRP
@@ -32,10 +35,11 @@ var _ = Describe("Actual LRP Handlers", func() {
 
 	BeforeEach(func() {
 		fakeBBS = new(fake_bbs.FakeClient)
+		fakeServiceClient = new(fake_bbs.FakeServiceClient)
 		logger = lager.NewLogger("test")
 		logger.RegisterSink(lager.NewWriterSink(GinkgoWriter, lager.DEBUG))
 		responseRecorder = httptest.NewRecorder()
-		handler = handlers.NewActualLRPHandler(fakeBBS, logger)
+		handler = handlers.NewActualLRPHandler(fakeBBS, fakeServiceClient, logger)
 
 		actualLRP1 = models.NewRunningActualLRP(
 			models.NewActualLRPKey(
@@ -135,6 +139,256 @@ var _ = Describe("Actual LRP Handlers", func() {
 					Expect(response).To(ConsistOf(expectedResponses))
 				})
 			})
+
+			Context("when groups=true is provided", func() {
+				It("returns both actual LRPs of each index", func() {
+					request, err := http.NewRequest("", "http://example.com?groups=true", nil)
+					Expect(err).NotTo(HaveOccurred())
+
+					handler.GetAll(responseRecorder, request)
+					response := []receptor.ActualLRPGroupResponse{}
+					err = json.Unmarshal(responseRecorder.Body.Bytes(), &response)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(response).To(ConsistOf(
+						serialization.ActualLRPGroupProtoToResponse(&models.ActualLRPGroup{Instance: actualLRP1}),
+						serialization.ActualLRPGroupProtoToResponse(&models.ActualLRPGroup{Instance: actualLRP2, Evacuating: evacuatingLRP2}),
+					))
+				})
+			})
+
+			Context("when a cell_id query param is provided", func() {
+				It("asks the BBS for the actual LRPs on that cell", func() {
+					request, err := http.NewRequest("", "http://example.com?domain=domain-1&cell_id=cell-id-1", nil)
+					Expect(err).NotTo(HaveOccurred())
+
+					handler.GetAll(responseRecorder, request)
+					Expect(fakeBBS.ActualLRPGroupsCallCount()).To(Equal(1))
+					Expect(fakeBBS.ActualLRPGroupsArgsForCall(0)).To(Equal(models.ActualLRPFilter{
+						Domain: "domain-1",
+						CellID: "cell-id-1",
+					}))
+				})
+			})
+
+			Context("when a state query param is provided", func() {
+				BeforeEach(func() {
+					fakeBBS.ActualLRPGroupsReturns([]*models.ActualLRPGroup{
+						{Instance: actualLRP1},
+						{Instance: actualLRP2},
+					}, nil)
+				})
+
+				It("returns only the actual LRPs in that state", func() {
+					request, err := http.NewRequest("", "http://example.com?state=CLAIMED", nil)
+					Expect(err).NotTo(HaveOccurred())
+
+					handler.GetAll(responseRecorder, request)
+					response := []receptor.ActualLRPResponse{}
+					err = json.Unmarshal(responseRecorder.Body.Bytes(), &response)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(response).To(Equal([]receptor.ActualLRPResponse{
+						serialization.ActualLRPProtoToResponse(actualLRP2, false),
+					}))
+				})
+
+				Context("when the state is not an actual LRP state", func() {
+					It("responds with 400 Bad Request", func() {
+						request, err := http.NewRequest("", "http://example.com?state=SLEEPING", nil)
+						Expect(err).NotTo(HaveOccurred())
+
+						handler.GetAll(responseRecorder, request)
+						Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+						Expect(fakeBBS.ActualLRPGroupsCallCount()).To(Equal(0))
+					})
+				})
+			})
+
+			Context("when a zone query param is provided", func() {
+				var request *http.Request
+
+				BeforeEach(func() {
+					var err error
+					request, err = http.NewRequest("", "http://example.com?zone=zone-0", nil)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				Context("when reading the cells succeeds", func() {
+					BeforeEach(func() {
+						capacity := models.NewCellCapacity(128, 1024, 6)
+						cellPresence0 := models.NewCellPresence("cell-id-0", "1.2.3.4", "zone-0", capacity, []string{}, []string{})
+						cellPresence1 := models.NewCellPresence("cell-id-1", "4.5.6.7", "zone-1", capacity, []string{}, []string{})
+						cellPresences := models.CellSet{}
+						cellPresences.Add(&cellPresence0)
+						cellPresences.Add(&cellPresence1)
+						fakeServiceClient.CellsReturns(cellPresences, nil)
+					})
+
+					It("returns only the actual LRPs on cells in that zone", func() {
+						handler.GetAll(responseRecorder, request)
+						response := []receptor.ActualLRPResponse{}
+						err := json.Unmarshal(responseRecorder.Body.Bytes(), &response)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(response).To(Equal([]receptor.ActualLRPResponse{
+							serialization.ActualLRPProtoToResponse(actualLRP1, false),
+						}))
+					})
+				})
+
+				Context("when reading the cells fails", func() {
+					BeforeEach(func() {
+						fakeServiceClient.CellsReturns(nil, errors.New("Something went wrong"))
+					})
+
+					It("responds with 500 Internal Error", func() {
+						handler.GetAll(responseRecorder, request)
+						Expect(responseRecorder.Code).To(Equal(http.StatusInternalServerError))
+					})
+				})
+			})
+
+			Context("when paginating", func() {
+				var pageToken string
+
+				BeforeEach(func() {
+					pageToken = base64.URLEncoding.EncodeToString([]byte("process-guid-0\x000000000001"))
+				})
+
+				It("returns the first page sorted by process guid and index with a link to the next page", func() {
+					request, err := http.NewRequest("", "http://example.com/v1/actual_lrps?limit=1", nil)
+					Expect(err).NotTo(HaveOccurred())
+
+					handler.GetAll(responseRecorder, request)
+					Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+
+					response := []receptor.ActualLRPResponse{}
+					err = json.Unmarshal(responseRecorder.Body.Bytes(), &response)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(response).To(Equal([]receptor.ActualLRPResponse{
+						serialization.ActualLRPProtoToResponse(actualLRP1, false),
+					}))
+
+					Expect(responseRecorder.Header().Get("Link")).To(Equal(
+						fmt.Sprintf(`</v1/actual_lrps?limit=1&page_token=%s>; rel="next"`, url.QueryEscape(pageToken)),
+					))
+				})
+
+				It("returns the page following the page token without a link on the last page", func() {
+					request, err := http.NewRequest("", "http://example.com/v1/actual_lrps?limit=1&page_token="+url.QueryEscape(pageToken), nil)
+					Expect(err).NotTo(HaveOccurred())
+
+					handler.GetAll(responseRecorder, request)
+					Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+
+					response := []receptor.ActualLRPResponse{}
+					err = json.Unmarshal(responseRecorder.Body.Bytes(), &response)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(response).To(Equal([]receptor.ActualLRPResponse{
+						serialization.ActualLRPProtoToResponse(evacuatingLRP2, true),
+					}))
+					Expect(responseRecorder.Header().Get("Link")).To(BeEmpty())
+				})
+
+				Context("when the page token is invalid", func() {
+					It("responds with a 400 Bad Request", func() {
+						request, err := http.NewRequest("", "http://example.com/v1/actual_lrps?page_token=%25%25%25", nil)
+						Expect(err).NotTo(HaveOccurred())
+
+						handler.GetAll(responseRecorder, request)
+						Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+						Expect(fakeBBS.ActualLRPGroupsCallCount()).To(Equal(0))
+
+						var receptorError receptor.Error
+						err = json.Unmarshal(responseRecorder.Body.Bytes(), &receptorError)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(receptorError.Type).To(Equal(receptor.InvalidRequest))
+					})
+				})
+			})
+
+			Context("when state, cell_id and zone query params are combined", func() {
+				var evacuatingGroup, replacedGroup *models.ActualLRPGroup
+
+				BeforeEach(func() {
+					newActualLRP := func(processGuid, cellID, state string) *models.ActualLRP {
+						return &models.ActualLRP{
+							ActualLRPKey:         models.NewActualLRPKey(processGuid, 0, "domain-0"),
+							ActualLRPInstanceKey: models.NewActualLRPInstanceKey("instance-guid-"+cellID, cellID),
+							State:                state,
+						}
+					}
+
+					// resolves to the evacuating ActualLRP, running on cell-a
+					evacuatingGroup = &models.ActualLRPGroup{
+						Instance:   newActualLRP("evacuating-guid", "cell-b", models.ActualLRPStateClaimed),
+						Evacuating: newActualLRP("evacuating-guid", "cell-a", models.ActualLRPStateRunning),
+					}
+					// resolves to the replacement, running on cell-b
+					replacedGroup = &models.ActualLRPGroup{
+						Instance:   newActualLRP("replaced-guid", "cell-b", models.ActualLRPStateRunning),
+						Evacuating: newActualLRP("replaced-guid", "cell-a", models.ActualLRPStateRunning),
+					}
+					fakeBBS.ActualLRPGroupsStub = nil
+					fakeBBS.ActualLRPGroupsReturns([]*models.ActualLRPGroup{evacuatingGroup, replacedGroup}, nil)
+
+					capacity := models.NewCellCapacity(128, 1024, 6)
+					cellPresenceA := models.NewCellPresence("cell-a", "1.2.3.4", "zone-a", capacity, []string{}, []string{})
+					cellPresenceB := models.NewCellPresence("cell-b", "4.5.6.7", "zone-b", capacity, []string{}, []string{})
+					cellPresences := models.CellSet{}
+					cellPresences.Add(&cellPresenceA)
+					cellPresences.Add(&cellPresenceB)
+					fakeServiceClient.CellsReturns(cellPresences, nil)
+				})
+
+				processGuids := func(query string) []string {
+					request, err := http.NewRequest("", "http://example.com?"+query, nil)
+					Expect(err).NotTo(HaveOccurred())
+
+					recorder := httptest.NewRecorder()
+					handler.GetAll(recorder, request)
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+
+					response := []receptor.ActualLRPResponse{}
+					err = json.Unmarshal(recorder.Body.Bytes(), &response)
+					Expect(err).NotTo(HaveOccurred())
+
+					guids := []string{}
+					for _, actualLRP := range response {
+						guids = append(guids, actualLRP.ProcessGuid)
+					}
+					return guids
+				}
+
+				It("matches the cell of the returned actual LRP, even though the BBS matches either", func() {
+					Expect(processGuids("cell_id=cell-a&state=RUNNING")).To(Equal([]string{"evacuating-guid"}))
+				})
+
+				It("matches the zone of the returned actual LRP", func() {
+					Expect(processGuids("zone=zone-b&state=RUNNING")).To(Equal([]string{"replaced-guid"}))
+				})
+
+				It("does not match the state of the actual LRP which is not returned", func() {
+					Expect(processGuids("zone=zone-b&state=CLAIMED")).To(BeEmpty())
+				})
+
+				It("applies every filter", func() {
+					Expect(processGuids("cell_id=cell-a&zone=zone-a&state=RUNNING")).To(Equal([]string{"evacuating-guid"}))
+					Expect(processGuids("cell_id=cell-a&zone=zone-b")).To(BeEmpty())
+				})
+			})
+
+			Context("when the groups query param is invalid", func() {
+				It("responds with 400 Bad Request", func() {
+					request, err := http.NewRequest("", "http://example.com?groups=sometimes", nil)
+					Expect(err).NotTo(HaveOccurred())
+
+					handler.GetAll(responseRecorder, request)
+					Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+					Expect(fakeBBS.ActualLRPGroupsCallCount()).To(Equal(0))
+				})
+			})
 		})
 
 		Context("when the BBS returns no lrps", func() {
@@ -244,6 +498,22 @@ var _ = Describe("Actual LRP Handlers", func() {
 					Expect(response).To(HaveLen(1))
 					Expect(response).To(ContainElement(serialization.ActualLRPProtoToResponse(evacuatingLRP2, true)))
 				})
+
+				Context("when groups=true is provided", func() {
+					BeforeEach(func() {
+						req.Form.Set("groups", "true")
+					})
+
+					It("returns both the instance and the evacuating actual LRP", func() {
+						response := []receptor.ActualLRPGroupResponse{}
+						err := json.Unmarshal(responseRecorder.Body.Bytes(), &response)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(response).To(HaveLen(1))
+						Expect(*response[0].Instance).To(Equal(serialization.ActualLRPProtoToResponse(actualLRP2, false)))
+						Expect(*response[0].Evacuating).To(Equal(serialization.ActualLRPProtoToResponse(evacuatingLRP2, true)))
+					})
+				})
 			})
 		})
 
@@ -347,6 +617,22 @@ var _ = Describe("Actual LRP Handlers", func() {
 				Expect(response).To(Equal(serialization.ActualLRPProtoToResponse(actualLRP2, false)))
 			})
 
+			It("responds with the modification tag as an ETag", func() {
+				expected := serialization.ActualLRPProtoToResponse(actualLRP2, false)
+				Expect(responseRecorder.Header().Get("ETag")).To(Equal(expected.ModificationTag.ETag()))
+			})
+
+			Context("when the If-None-Match header matches the ETag", func() {
+				BeforeEach(func() {
+					expected := serialization.ActualLRPProtoToResponse(actualLRP2, false)
+					req.Header.Set("If-None-Match", expected.ModificationTag.ETag())
+				})
+
+				It("responds with 304 Not Modified", func() {
+					Expect(responseRecorder.Code).To(Equal(http.StatusNotModified))
+				})
+			})
+
 			Context("when the LRP group contains an evacuating", func() {
 				BeforeEach(func() {
 					fakeBBS.ActualLRPGroupByProcessGuidAndIndexReturns(
@@ -362,6 +648,21 @@ var _ = Describe("Actual LRP Handlers", func() {
 
 					Expect(response).To(Equal(serialization.ActualLRPProtoToResponse(evacuatingLRP2, true)))
 				})
+
+				Context("when groups=true is provided", func() {
+					BeforeEach(func() {
+						req.Form.Set("groups", "true")
+					})
+
+					It("responds with both the instance and the evacuating LRP", func() {
+						response := receptor.ActualLRPGroupResponse{}
+						err := json.Unmarshal(responseRecorder.Body.Bytes(), &response)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(*response.Instance).To(Equal(serialization.ActualLRPProtoToResponse(actualLRP2, false)))
+						Expect(*response.Evacuating).To(Equal(serialization.ActualLRPProtoToResponse(evacuatingLRP2, true)))
+					})
+				})
 			})
 		})
 
@@ -587,4 +888,181 @@ var _ = Describe("Actual LRP Handlers", func() {
 			})
 		})
 	})
+
+	Describe("KillAllByProcessGuid", func() {
+		var req *http.Request
+
+		BeforeEach(func() {
+			req = newTestRequest("")
+			req.Form = url.Values{":process_guid": []string{"process-guid-1"}}
+
+			actualLRP3 := models.NewRunningActualLRP(
+				models.NewActualLRPKey("process-guid-1", 0, "domain-1"),
+				models.NewActualLRPInstanceKey("instance-guid-3", "cell-id-1"),
+				models.NewActualLRPNetInfo(""),
+				1234,
+			)
+			fakeBBS.ActualLRPGroupsByProcessGuidReturns([]*models.ActualLRPGroup{
+				{Instance: actualLRP2},
+				{Instance: actualLRP3},
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			handler.KillAllByProcessGuid(responseRecorder, req)
+		})
+
+		It("kills every instance of the process", func() {
+			Expect(fakeBBS.ActualLRPGroupsByProcessGuidArgsForCall(0)).To(Equal("process-guid-1"))
+			Expect(fakeBBS.RetireActualLRPCallCount()).To(Equal(2))
+
+			indices := []int32{}
+			for i := 0; i < 2; i++ {
+				indices = append(indices, fakeBBS.RetireActualLRPArgsForCall(i).Index)
+			}
+			Expect(indices).To(ConsistOf(int32(0), int32(2)))
+		})
+
+		It("responds with 200 and the killed indices", func() {
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+
+			var response receptor.ActualLRPsKillResponse
+			err := json.Unmarshal(responseRecorder.Body.Bytes(), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal(receptor.ActualLRPsKillResponse{
+				Killed: []int{0, 2},
+				Failed: []receptor.ActualLRPKillFailure{},
+			}))
+		})
+
+		Context("when killing an instance fails", func() {
+			BeforeEach(func() {
+				fakeBBS.RetireActualLRPStub = func(key *models.ActualLRPKey) error {
+					if key.Index == 2 {
+						return errors.New("oops")
+					}
+					return nil
+				}
+			})
+
+			It("reports the failure alongside the killed indices", func() {
+				var response receptor.ActualLRPsKillResponse
+				err := json.Unmarshal(responseRecorder.Body.Bytes(), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response).To(Equal(receptor.ActualLRPsKillResponse{
+					Killed: []int{0},
+					Failed: []receptor.ActualLRPKillFailure{{
+						Index: 2,
+						Error: receptor.Error{Type: receptor.UnknownError, Message: "oops"},
+					}},
+				}))
+			})
+		})
+
+		Context("when max_in_flight is invalid", func() {
+			BeforeEach(func() {
+				req.Form.Set("max_in_flight", "none")
+			})
+
+			It("responds with 400 without killing anything", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(fakeBBS.RetireActualLRPCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when fetching the actual LRPs fails", func() {
+			BeforeEach(func() {
+				fakeBBS.ActualLRPGroupsByProcessGuidReturns(nil, errors.New("oops"))
+			})
+
+			It("responds with 500", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+
+	Describe("KillByInstanceGuid", func() {
+		var (
+			req        *http.Request
+			evacuating *models.ActualLRP
+		)
+
+		BeforeEach(func() {
+			req = newTestRequest("")
+			req.Form = url.Values{":process_guid": []string{"process-guid-1"}}
+
+			evacuating = models.NewRunningActualLRP(
+				models.NewActualLRPKey("process-guid-1", 2, "domain-1"),
+				models.NewActualLRPInstanceKey("evacuating-instance-guid", "cell-id-2"),
+				models.NewActualLRPNetInfo(""),
+				3417,
+			)
+			fakeBBS.ActualLRPGroupsByProcessGuidReturns([]*models.ActualLRPGroup{
+				{Instance: actualLRP2, Evacuating: evacuating},
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			handler.KillByInstanceGuid(responseRecorder, req)
+		})
+
+		Context("when the instance guid names the instance", func() {
+			BeforeEach(func() {
+				req.Form.Set(":instance_guid", "instance-guid-1")
+			})
+
+			It("retires it", func() {
+				Expect(fakeBBS.RetireActualLRPCallCount()).To(Equal(1))
+				Expect(*fakeBBS.RetireActualLRPArgsForCall(0)).To(Equal(actualLRP2.ActualLRPKey))
+				Expect(fakeBBS.RemoveEvacuatingActualLRPCallCount()).To(Equal(0))
+			})
+
+			It("responds with 204 NO CONTENT", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusNoContent))
+			})
+		})
+
+		Context("when the instance guid names the evacuating instance", func() {
+			BeforeEach(func() {
+				req.Form.Set(":instance_guid", "evacuating-instance-guid")
+			})
+
+			It("removes it without touching the instance", func() {
+				Expect(fakeBBS.RemoveEvacuatingActualLRPCallCount()).To(Equal(1))
+				key, instanceKey := fakeBBS.RemoveEvacuatingActualLRPArgsForCall(0)
+				Expect(*key).To(Equal(evacuating.ActualLRPKey))
+				Expect(*instanceKey).To(Equal(evacuating.ActualLRPInstanceKey))
+				Expect(fakeBBS.RetireActualLRPCallCount()).To(Equal(0))
+			})
+
+			It("responds with 204 NO CONTENT", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusNoContent))
+			})
+		})
+
+		Context("when no instance has the guid", func() {
+			BeforeEach(func() {
+				req.Form.Set(":instance_guid", "unknown-guid")
+			})
+
+			It("responds with 404 and an ActualLRPInstanceNotFound error", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusNotFound))
+
+				var responseError receptor.Error
+				err := json.Unmarshal(responseRecorder.Body.Bytes(), &responseError)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(responseError).To(Equal(receptor.Error{
+					Type:    receptor.ActualLRPInstanceNotFound,
+					Message: "process-guid 'process-guid-1' has no instance with guid 'unknown-guid'",
+				}))
+			})
+		})
+
+		Context("when the instance guid is missing", func() {
+			It("responds with 400", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(fakeBBS.ActualLRPGroupsByProcessGuidCallCount()).To(Equal(0))
+			})
+		})
+	})
 })
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/bbs/models"
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/autoscaler"
+	"github.com/pivotal-golang/lager"
+)
+
+type AutoscalingPolicyHandler struct {
+	policies autoscaler.PolicyStore
+	logger   lager.Logger
+}
+
+func NewAutoscalingPolicyHandler(policies autoscaler.PolicyStore, logger lager.Logger) *AutoscalingPolicyHandler {
+	return &AutoscalingPolicyHandler{
+		policies: policies,
+		logger:   logger.Session("autoscaling-policy-handler"),
+	}
+}
+
+func (h *AutoscalingPolicyHandler) GetAll(w http.ResponseWriter, req *http.Request) {
+	logger := h.logger.Session("get-all")
+
+	policies, err := h.policies.List()
+	if err != nil {
+		logger.Error("failed-to-fetch-policies", err)
+		writeUnknownErrorResponse(w, err)
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, policies)
+}
+
+func (h *AutoscalingPolicyHandler) Get(w http.ResponseWriter, req *http.Request) {
+	processGuid := req.FormValue(":process_guid")
+	logger := h.logger.Session("get", lager.Data{
+		"ProcessGuid": processGuid,
+	})
+
+	policy, err := h.policies.Get(processGuid)
+	if err != nil {
+		writeAutoscalingPolicyError(w, logger, processGuid, err)
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, policy)
+}
+
+func (h *AutoscalingPolicyHandler) Set(w http.ResponseWriter, req *http.Request) {
+	processGuid := req.FormValue(":process_guid")
+	logger := h.logger.Session("set", lager.Data{
+		"ProcessGuid": processGuid,
+	})
+
+	if processGuid == "" {
+		err := errors.New("process_guid missing from request")
+		logger.Error("missing-process-guid", err)
+		writeBadRequestResponse(w, receptor.InvalidRequest, err)
+		return
+	}
+
+	policy := receptor.AutoscalingPolicy{}
+	err := json.NewDecoder(req.Body).Decode(&policy)
+	if err != nil {
+		logger.Error("invalid-json", err)
+		writeBadRequestResponse(w, receptor.InvalidJSON, err)
+		return
+	}
+	policy.ProcessGuid = processGuid
+
+	if fieldErrs := validateAutoscalingPolicy(policy); len(fieldErrs) > 0 {
+		validationErr := validationError(receptor.InvalidAutoscalingPolicy, fieldErrs)
+		logger.Error("invalid-policy", validationErr)
+		writeJSONResponse(w, http.StatusBadRequest, validationErr)
+		return
+	}
+
+	err = h.policies.Set(policy)
+	if err != nil {
+		writeAutoscalingPolicyError(w, logger, processGuid, err)
+		return
+	}
+
+	logger.Info("set")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AutoscalingPolicyHandler) Delete(w http.ResponseWriter, req *http.Request) {
+	processGuid := req.FormValue(":process_guid")
+	logger := h.logger.Session("delete", lager.Data{
+		"ProcessGuid": processGuid,
+	})
+
+	err := h.policies.Remove(processGuid)
+	if err != nil {
+		writeAutoscalingPolicyError(w, logger, processGuid, err)
+		return
+	}
+
+	logger.Info("deleted")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeAutoscalingPolicyError(w http.ResponseWriter, logger lager.Logger, processGuid string, err error) {
+	if err == autoscaler.ErrPolicyNotFound {
+		writeJSONResponse(w, http.StatusNotFound, receptor.Error{
+			Type:    receptor.AutoscalingPolicyNotFound,
+			Message: fmt.Sprintf("Desired LRP with guid '%s' has no autoscaling policy", processGuid),
+		})
+		return
+	}
+
+	bbsError := models.ConvertError(err)
+	switch bbsError.Type {
+	case models.Error_ResourceNotFound:
+		writeDesiredLRPNotFoundResponse(w, processGuid)
+	case models.Error_ResourceConflict:
+		logger.Error("failed-to-compare-and-swap", err)
+		writeCompareAndSwapFailedResponse(w, processGuid)
+	default:
+		logger.Error("unknown-error", err)
+		writeUnknownErrorResponse(w, err)
+	}
+}
@@ -0,0 +1,210 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	"github.com/cloudfoundry-incubator/bbs/models"
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/autoscaler"
+	"github.com/cloudfoundry-incubator/receptor/autoscaler/fake_autoscaler"
+	"github.com/cloudfoundry-incubator/receptor/handlers"
+	"github.com/pivotal-golang/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AutoscalingPolicyHandler", func() {
+	var (
+		logger           lager.Logger
+		fakePolicies     *fake_autoscaler.FakePolicyStore
+		responseRecorder *httptest.ResponseRecorder
+		handler          *handlers.AutoscalingPolicyHandler
+
+		policy receptor.AutoscalingPolicy
+	)
+
+	newRequest := func(body interface{}) *http.Request {
+		request := newTestRequest(body)
+		request.Form = url.Values{":process_guid": []string{"process-guid"}}
+		return request
+	}
+
+	BeforeEach(func() {
+		logger = lager.NewLogger("test")
+		logger.RegisterSink(lager.NewWriterSink(GinkgoWriter, lager.DEBUG))
+		fakePolicies = new(fake_autoscaler.FakePolicyStore)
+		responseRecorder = httptest.NewRecorder()
+		handler = handlers.NewAutoscalingPolicyHandler(fakePolicies, logger)
+
+		policy = receptor.AutoscalingPolicy{
+			ProcessGuid:  "process-guid",
+			MinInstances: 1,
+			MaxInstances: 3,
+			Rules: []receptor.AutoscalingRule{{
+				Metric:     receptor.AutoscalingMetricCrashRate,
+				Comparison: receptor.AutoscalingComparisonAbove,
+				Threshold:  0.5,
+				Adjustment: -1,
+			}},
+		}
+	})
+
+	Describe("Set", func() {
+		var body interface{}
+
+		BeforeEach(func() {
+			body = policy
+		})
+
+		JustBeforeEach(func() {
+			handler.Set(responseRecorder, newRequest(body))
+		})
+
+		It("stores the policy", func() {
+			Expect(fakePolicies.SetCallCount()).To(Equal(1))
+			Expect(fakePolicies.SetArgsForCall(0)).To(Equal(policy))
+		})
+
+		It("responds with 204 NO CONTENT", func() {
+			Expect(responseRecorder.Code).To(Equal(http.StatusNoContent))
+		})
+
+		Context("when the body names another process", func() {
+			BeforeEach(func() {
+				policy.ProcessGuid = "other-guid"
+				body = policy
+			})
+
+			It("stores the policy for the process in the path", func() {
+				Expect(fakePolicies.SetArgsForCall(0).ProcessGuid).To(Equal("process-guid"))
+			})
+		})
+
+		Context("when the policy is invalid", func() {
+			BeforeEach(func() {
+				policy.MaxInstances = 0
+				policy.Rules[0].Comparison = "sideways"
+				body = policy
+			})
+
+			It("responds with 400 and the offending fields", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(fakePolicies.SetCallCount()).To(Equal(0))
+
+				var responseError receptor.Error
+				err := json.Unmarshal(responseRecorder.Body.Bytes(), &responseError)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(responseError.Type).To(Equal(receptor.InvalidAutoscalingPolicy))
+				fields := []string{}
+				for _, fieldErr := range responseError.FieldErrors {
+					fields = append(fields, fieldErr.Field)
+				}
+				Expect(fields).To(ConsistOf("max_instances", "rules[0].comparison"))
+			})
+		})
+
+		Context("when the body is not valid JSON", func() {
+			BeforeEach(func() {
+				body = "{"
+			})
+
+			It("responds with 400", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("when the desired LRP does not exist", func() {
+			BeforeEach(func() {
+				fakePolicies.SetReturns(models.ErrResourceNotFound)
+			})
+
+			It("responds with 404 and a DesiredLRPNotFound error", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusNotFound))
+				Expect(responseRecorder.Body.String()).To(ContainSubstring(receptor.DesiredLRPNotFound))
+			})
+		})
+	})
+
+	Describe("Get", func() {
+		JustBeforeEach(func() {
+			handler.Get(responseRecorder, newRequest(""))
+		})
+
+		Context("when the desired LRP has a policy", func() {
+			BeforeEach(func() {
+				fakePolicies.GetReturns(policy, nil)
+			})
+
+			It("responds with the policy", func() {
+				Expect(fakePolicies.GetArgsForCall(0)).To(Equal("process-guid"))
+				Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+
+				var response receptor.AutoscalingPolicy
+				err := json.Unmarshal(responseRecorder.Body.Bytes(), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response).To(Equal(policy))
+			})
+		})
+
+		Context("when the desired LRP has no policy", func() {
+			BeforeEach(func() {
+				fakePolicies.GetReturns(receptor.AutoscalingPolicy{}, autoscaler.ErrPolicyNotFound)
+			})
+
+			It("responds with 404 and an AutoscalingPolicyNotFound error", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusNotFound))
+
+				var responseError receptor.Error
+				err := json.Unmarshal(responseRecorder.Body.Bytes(), &responseError)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(responseError).To(Equal(receptor.Error{
+					Type:    receptor.AutoscalingPolicyNotFound,
+					Message: "Desired LRP with guid 'process-guid' has no autoscaling policy",
+				}))
+			})
+		})
+	})
+
+	Describe("GetAll", func() {
+		It("responds with every policy", func() {
+			fakePolicies.ListReturns([]receptor.AutoscalingPolicy{policy}, nil)
+
+			handler.GetAll(responseRecorder, newTestRequest(""))
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+
+			var response []receptor.AutoscalingPolicy
+			err := json.Unmarshal(responseRecorder.Body.Bytes(), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal([]receptor.AutoscalingPolicy{policy}))
+		})
+
+		It("responds with 500 when the policies can not be fetched", func() {
+			fakePolicies.ListReturns(nil, errors.New("oops"))
+
+			handler.GetAll(responseRecorder, newTestRequest(""))
+			Expect(responseRecorder.Code).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
+	Describe("Delete", func() {
+		It("removes the policy", func() {
+			handler.Delete(responseRecorder, newRequest(""))
+
+			Expect(fakePolicies.RemoveArgsForCall(0)).To(Equal("process-guid"))
+			Expect(responseRecorder.Code).To(Equal(http.StatusNoContent))
+		})
+
+		It("responds with 404 when the desired LRP has no policy", func() {
+			fakePolicies.RemoveReturns(autoscaler.ErrPolicyNotFound)
+
+			handler.Delete(responseRecorder, newRequest(""))
+			Expect(responseRecorder.Code).To(Equal(http.StatusNotFound))
+		})
+	})
+})
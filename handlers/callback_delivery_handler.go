@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/callback"
+	"github.com/pivotal-golang/lager"
+)
+
+type CallbackDeliveryHandler struct {
+	store  callback.StatusStore
+	logger lager.Logger
+}
+
+func NewCallbackDeliveryHandler(store callback.StatusStore, logger lager.Logger) *CallbackDeliveryHandler {
+	return &CallbackDeliveryHandler{
+		store:  store,
+		logger: logger.Session("callback-delivery-handler"),
+	}
+}
+
+func (h *CallbackDeliveryHandler) GetByTaskGuid(w http.ResponseWriter, req *http.Request) {
+	guid := req.FormValue(":task_guid")
+	logger := h.logger.Session("get-by-task-guid", lager.Data{
+		"TaskGuid": guid,
+	})
+
+	if guid == "" {
+		err := errors.New("task_guid missing from request")
+		logger.Error("missing-task-guid", err)
+		writeBadRequestResponse(w, receptor.InvalidRequest, err)
+		return
+	}
+
+	status, err := h.store.Get(guid)
+	if err == callback.ErrStatusNotFound {
+		writeJSONResponse(w, http.StatusNotFound, receptor.Error{
+			Type:    receptor.CallbackDeliveryNotFound,
+			Message: fmt.Sprintf("no callback delivery for task with guid '%s'", guid),
+		})
+		return
+	}
+	if err != nil {
+		logger.Error("failed-to-fetch-status", err)
+		writeUnknownErrorResponse(w, err)
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, status)
+}
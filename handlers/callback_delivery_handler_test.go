@@ -0,0 +1,106 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/callback"
+	"github.com/cloudfoundry-incubator/receptor/callback/fake_callback"
+	"github.com/cloudfoundry-incubator/receptor/handlers"
+	"github.com/pivotal-golang/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CallbackDeliveryHandler", func() {
+	var (
+		logger           lager.Logger
+		store            *fake_callback.FakeStatusStore
+		responseRecorder *httptest.ResponseRecorder
+		handler          *handlers.CallbackDeliveryHandler
+		request          *http.Request
+	)
+
+	BeforeEach(func() {
+		logger = lager.NewLogger("test")
+		logger.RegisterSink(lager.NewWriterSink(GinkgoWriter, lager.DEBUG))
+		store = new(fake_callback.FakeStatusStore)
+		store.GetReturns(receptor.CallbackDeliveryResponse{}, callback.ErrStatusNotFound)
+		responseRecorder = httptest.NewRecorder()
+		handler = handlers.NewCallbackDeliveryHandler(store, logger)
+
+		request = newTestRequest("")
+		request.Form = url.Values{":task_guid": []string{"some-task-guid"}}
+	})
+
+	JustBeforeEach(func() {
+		handler.GetByTaskGuid(responseRecorder, request)
+	})
+
+	Context("when the task has a callback delivery", func() {
+		var status receptor.CallbackDeliveryResponse
+
+		BeforeEach(func() {
+			status = receptor.CallbackDeliveryResponse{
+				TaskGuid:      "some-task-guid",
+				URL:           "http://example.com/callback",
+				State:         receptor.CallbackDeliveryDelivered,
+				Attempts:      2,
+				LastAttemptAt: 1138,
+			}
+			store.GetReturns(status, nil)
+		})
+
+		It("responds with the delivery status", func() {
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+
+			var response receptor.CallbackDeliveryResponse
+			err := json.Unmarshal(responseRecorder.Body.Bytes(), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal(status))
+
+			Expect(store.GetArgsForCall(0)).To(Equal("some-task-guid"))
+		})
+	})
+
+	Context("when the task has no callback delivery", func() {
+		It("responds with 404 NOT FOUND", func() {
+			Expect(responseRecorder.Code).To(Equal(http.StatusNotFound))
+
+			var responseError receptor.Error
+			err := json.Unmarshal(responseRecorder.Body.Bytes(), &responseError)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(responseError.Type).To(Equal(receptor.CallbackDeliveryNotFound))
+		})
+	})
+
+	Context("when fetching the delivery status fails", func() {
+		BeforeEach(func() {
+			store.GetReturns(receptor.CallbackDeliveryResponse{}, errors.New("oops"))
+		})
+
+		It("responds with 500 INTERNAL SERVER ERROR", func() {
+			Expect(responseRecorder.Code).To(Equal(http.StatusInternalServerError))
+
+			var responseError receptor.Error
+			err := json.Unmarshal(responseRecorder.Body.Bytes(), &responseError)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(responseError.Type).To(Equal(receptor.UnknownError))
+		})
+	})
+
+	Context("when the task guid is not provided", func() {
+		BeforeEach(func() {
+			request.Form = url.Values{}
+		})
+
+		It("responds with 400 BAD REQUEST", func() {
+			Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+		})
+	})
+})
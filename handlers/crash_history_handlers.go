@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/crashhistory"
+	"github.com/pivotal-golang/lager"
+)
+
+type CrashHistoryHandler struct {
+	store  crashhistory.Store
+	logger lager.Logger
+}
+
+func NewCrashHistoryHandler(store crashhistory.Store, logger lager.Logger) *CrashHistoryHandler {
+	return &CrashHistoryHandler{
+		store:  store,
+		logger: logger.Session("crash-history-handler"),
+	}
+}
+
+func (h *CrashHistoryHandler) GetByProcessGuidAndIndex(w http.ResponseWriter, req *http.Request) {
+	processGuid := req.FormValue(":process_guid")
+	indexString := req.FormValue(":index")
+	logger := h.logger.Session("get-by-process-guid-and-index", lager.Data{
+		"ProcessGuid": processGuid,
+		"Index":       indexString,
+	})
+
+	if processGuid == "" {
+		err := errors.New("process_guid missing from request")
+		logger.Error("missing-process-guid", err)
+		writeBadRequestResponse(w, receptor.InvalidRequest, err)
+		return
+	}
+
+	index, err := strconv.Atoi(indexString)
+	if err != nil {
+		err = errors.New("index not a number")
+		logger.Error("invalid-index", err)
+		writeBadRequestResponse(w, receptor.InvalidRequest, err)
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, h.store.History(processGuid, index))
+}
+
+func (h *CrashHistoryHandler) GetCrashing(w http.ResponseWriter, req *http.Request) {
+	writeJSONResponse(w, http.StatusOK, h.store.Crashing())
+}
@@ -0,0 +1,93 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/crashhistory/fake_crashhistory"
+	"github.com/cloudfoundry-incubator/receptor/handlers"
+	"github.com/pivotal-golang/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CrashHistoryHandler", func() {
+	var (
+		fakeStore        *fake_crashhistory.FakeStore
+		responseRecorder *httptest.ResponseRecorder
+		handler          *handlers.CrashHistoryHandler
+	)
+
+	BeforeEach(func() {
+		logger := lager.NewLogger("test")
+		logger.RegisterSink(lager.NewWriterSink(GinkgoWriter, lager.DEBUG))
+		fakeStore = new(fake_crashhistory.FakeStore)
+		responseRecorder = httptest.NewRecorder()
+		handler = handlers.NewCrashHistoryHandler(fakeStore, logger)
+	})
+
+	Describe("GetByProcessGuidAndIndex", func() {
+		var (
+			req     *http.Request
+			crashes []receptor.ActualLRPCrashResponse
+		)
+
+		BeforeEach(func() {
+			req = newTestRequest("")
+			req.Form = url.Values{
+				":process_guid": []string{"some-guid"},
+				":index":        []string{"2"},
+			}
+
+			crashes = []receptor.ActualLRPCrashResponse{{InstanceGuid: "some-instance-guid", CrashCount: 1, CrashedAt: 10}}
+			fakeStore.HistoryReturns(crashes)
+		})
+
+		JustBeforeEach(func() {
+			handler.GetByProcessGuidAndIndex(responseRecorder, req)
+		})
+
+		It("responds with the crashes of the instance", func() {
+			processGuid, index := fakeStore.HistoryArgsForCall(0)
+			Expect(processGuid).To(Equal("some-guid"))
+			Expect(index).To(Equal(2))
+
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+
+			var response []receptor.ActualLRPCrashResponse
+			err := json.Unmarshal(responseRecorder.Body.Bytes(), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal(crashes))
+		})
+
+		Context("when the index is not a number", func() {
+			BeforeEach(func() {
+				req.Form.Set(":index", "two")
+			})
+
+			It("responds with 400 without looking up the history", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(fakeStore.HistoryCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("GetCrashing", func() {
+		It("responds with the instances in a crash loop", func() {
+			crashing := []receptor.CrashingActualLRPResponse{{ProcessGuid: "some-guid", Index: 1, RecentCrashes: 3}}
+			fakeStore.CrashingReturns(crashing)
+
+			handler.GetCrashing(responseRecorder, newTestRequest(""))
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+
+			var response []receptor.CrashingActualLRPResponse
+			err := json.Unmarshal(responseRecorder.Body.Bytes(), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal(crashing))
+		})
+	})
+})
@@ -0,0 +1,311 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cloudfoundry-incubator/bbs/models"
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/pivotal-golang/lager"
+)
+
+const (
+	maxBulkDesiredLRPConcurrency = 20
+
+	restartPollInterval = time.Second
+	restartTimeout      = 2 * time.Minute
+)
+
+// ScaleAll sets the instance count of every DesiredLRP in a domain. Scaling
+// a stopped DesiredLRP forgets the instance count saved by Stop.
+func (h *DesiredLRPHandler) ScaleAll(w http.ResponseWriter, req *http.Request) {
+	domain := req.FormValue(":domain")
+	logger := h.logger.Session("scale-all", lager.Data{
+		"domain": domain,
+	})
+
+	scaleRequest := receptor.DesiredLRPsScaleRequest{}
+	err := json.NewDecoder(req.Body).Decode(&scaleRequest)
+	if err != nil {
+		logger.Error("invalid-json", err)
+		writeBadRequestResponse(w, receptor.InvalidJSON, err)
+		return
+	}
+
+	if scaleRequest.Instances < 0 {
+		err := errors.New("instances must not be negative")
+		logger.Error("invalid-instances", err)
+		writeBadRequestResponse(w, receptor.InvalidRequest, err)
+		return
+	}
+
+	instances := int32(scaleRequest.Instances)
+	h.forEachDesiredLRP(w, req, logger, domain, func(desiredLRP *models.DesiredLRP) *receptor.Error {
+		err := h.stoppedLRPs.Forget(desiredLRP.ProcessGuid)
+		if err != nil {
+			logger.Error("failed-to-forget-instances", err, lager.Data{"process-guid": desiredLRP.ProcessGuid})
+			return bulkDesiredLRPError(desiredLRP.ProcessGuid, err)
+		}
+
+		err = h.bbs.UpdateDesiredLRP(desiredLRP.ProcessGuid, &models.DesiredLRPUpdate{Instances: &instances})
+		if err != nil {
+			logger.Error("failed-to-update-desired-lrp", err, lager.Data{"process-guid": desiredLRP.ProcessGuid})
+			return bulkDesiredLRPError(desiredLRP.ProcessGuid, err)
+		}
+		return nil
+	})
+}
+
+// RestartAll kills every ActualLRP of every DesiredLRP in a domain, leaving
+// the convergence to start them again. The instances of each DesiredLRP are
+// killed one at a time: after killing a running instance, RestartAll waits for
+// its replacement to be running before killing the next one. RestartAll stops
+// killing instances once the client disconnects.
+func (h *DesiredLRPHandler) RestartAll(w http.ResponseWriter, req *http.Request) {
+	domain := req.FormValue(":domain")
+	logger := h.logger.Session("restart-all", lager.Data{
+		"domain": domain,
+	})
+
+	cancelled := make(chan struct{})
+	if closeNotifier, ok := w.(http.CloseNotifier); ok {
+		closed := closeNotifier.CloseNotify()
+		done := make(chan struct{})
+		defer close(done)
+
+		go func() {
+			select {
+			case <-closed:
+				logger.Info("client-disconnected")
+				close(cancelled)
+			case <-done:
+			}
+		}()
+	}
+
+	h.forEachDesiredLRP(w, req, logger, domain, func(desiredLRP *models.DesiredLRP) *receptor.Error {
+		groups, err := h.bbs.ActualLRPGroupsByProcessGuid(desiredLRP.ProcessGuid)
+		if err != nil {
+			logger.Error("failed-to-fetch-actual-lrps", err, lager.Data{"process-guid": desiredLRP.ProcessGuid})
+			return bulkDesiredLRPError(desiredLRP.ProcessGuid, err)
+		}
+
+		for i, group := range groups {
+			actualLRP, _ := group.Resolve()
+			if actualLRP == nil {
+				continue
+			}
+
+			select {
+			case <-cancelled:
+				return restartCancelledError(desiredLRP.ProcessGuid)
+			default:
+			}
+
+			err := h.bbs.RetireActualLRP(&actualLRP.ActualLRPKey)
+			if err != nil {
+				if models.ConvertError(err).Type == models.Error_ResourceNotFound {
+					continue
+				}
+
+				logger.Error("failed-to-retire-actual-lrp", err, lager.Data{
+					"process-guid": desiredLRP.ProcessGuid,
+					"index":        actualLRP.Index,
+				})
+				return bulkDesiredLRPError(desiredLRP.ProcessGuid, err)
+			}
+
+			if actualLRP.State != models.ActualLRPStateRunning || i == len(groups)-1 {
+				continue
+			}
+
+			restartErr := h.waitForReplacement(logger, actualLRP, cancelled)
+			if restartErr != nil {
+				return restartErr
+			}
+		}
+		return nil
+	})
+}
+
+// waitForReplacement polls the index of a retired ActualLRP until another
+// instance is running at it, giving up after restartTimeout or once cancelled
+// is closed.
+func (h *DesiredLRPHandler) waitForReplacement(logger lager.Logger, retired *models.ActualLRP, cancelled <-chan struct{}) *receptor.Error {
+	logger = logger.Session("wait-for-replacement", lager.Data{
+		"process-guid": retired.ProcessGuid,
+		"index":        retired.Index,
+	})
+
+	deadline := h.clock.Now().Add(restartTimeout)
+	for {
+		group, err := h.bbs.ActualLRPGroupByProcessGuidAndIndex(retired.ProcessGuid, int(retired.Index))
+		if err != nil && models.ConvertError(err).Type != models.Error_ResourceNotFound {
+			logger.Error("failed-to-fetch-actual-lrp", err)
+			return bulkDesiredLRPError(retired.ProcessGuid, err)
+		}
+
+		if err == nil {
+			actualLRP, _ := group.Resolve()
+			if actualLRP != nil && actualLRP.State == models.ActualLRPStateRunning && actualLRP.InstanceGuid != retired.InstanceGuid {
+				return nil
+			}
+		}
+
+		if !h.clock.Now().Before(deadline) {
+			logger.Info("timed-out")
+			return &receptor.Error{
+				Type:    receptor.ActualLRPRestartTimedOut,
+				Message: fmt.Sprintf("Instance %d of desired LRP with guid '%s' was not running again within %s", retired.Index, retired.ProcessGuid, restartTimeout),
+			}
+		}
+
+		timer := h.clock.NewTimer(restartPollInterval)
+		select {
+		case <-cancelled:
+			timer.Stop()
+			return restartCancelledError(retired.ProcessGuid)
+		case <-timer.C():
+		}
+	}
+}
+
+func restartCancelledError(processGuid string) *receptor.Error {
+	return &receptor.Error{
+		Type:    receptor.ActualLRPRestartCancelled,
+		Message: fmt.Sprintf("Restart of desired LRP with guid '%s' was cancelled", processGuid),
+	}
+}
+
+// DeleteAll deletes every DesiredLRP in a domain.
+func (h *DesiredLRPHandler) DeleteAll(w http.ResponseWriter, req *http.Request) {
+	domain := req.FormValue(":domain")
+	logger := h.logger.Session("delete-all", lager.Data{
+		"domain": domain,
+	})
+
+	h.forEachDesiredLRP(w, req, logger, domain, func(desiredLRP *models.DesiredLRP) *receptor.Error {
+		err := h.bbs.RemoveDesiredLRP(desiredLRP.ProcessGuid)
+		if err != nil {
+			logger.Error("failed-to-remove-desired-lrp", err, lager.Data{"process-guid": desiredLRP.ProcessGuid})
+			return bulkDesiredLRPError(desiredLRP.ProcessGuid, err)
+		}
+		return nil
+	})
+}
+
+// forEachDesiredLRP applies action to the DesiredLRPs of a domain, at most
+// max_in_flight of them at a time, and responds with the outcome for each.
+func (h *DesiredLRPHandler) forEachDesiredLRP(
+	w http.ResponseWriter,
+	req *http.Request,
+	logger lager.Logger,
+	domain string,
+	action func(*models.DesiredLRP) *receptor.Error,
+) {
+	if domain == "" {
+		err := errors.New("domain missing from request")
+		logger.Error("missing-domain", err)
+		writeBadRequestResponse(w, receptor.InvalidRequest, err)
+		return
+	}
+
+	maxInFlight, err := maxInFlightFromRequest(req)
+	if err != nil {
+		logger.Error("invalid-max-in-flight", err)
+		writeBadRequestResponse(w, receptor.InvalidRequest, err)
+		return
+	}
+
+	desiredLRPs, err := h.bbs.DesiredLRPs(models.DesiredLRPFilter{Domain: domain})
+	if err != nil {
+		logger.Error("failed-to-fetch-desired-lrps", err)
+		writeUnknownErrorResponse(w, err)
+		return
+	}
+
+	logger.Info("starting", lager.Data{"count": len(desiredLRPs), "max-in-flight": maxInFlight})
+
+	failures := make([]*receptor.Error, len(desiredLRPs))
+	throttle := make(chan struct{}, maxInFlight)
+	wg := sync.WaitGroup{}
+
+	for i := range desiredLRPs {
+		wg.Add(1)
+		throttle <- struct{}{}
+
+		go func(i int) {
+			defer func() {
+				<-throttle
+				wg.Done()
+			}()
+
+			failures[i] = action(desiredLRPs[i])
+		}(i)
+	}
+
+	wg.Wait()
+
+	response := receptor.DesiredLRPsBulkResponse{
+		Succeeded: []string{},
+		Failed:    []receptor.DesiredLRPBulkFailure{},
+	}
+	for i, desiredLRP := range desiredLRPs {
+		if failures[i] != nil {
+			response.Failed = append(response.Failed, receptor.DesiredLRPBulkFailure{
+				ProcessGuid: desiredLRP.ProcessGuid,
+				Error:       *failures[i],
+			})
+			continue
+		}
+		response.Succeeded = append(response.Succeeded, desiredLRP.ProcessGuid)
+	}
+
+	logger.Info("finished", lager.Data{"succeeded": len(response.Succeeded), "failed": len(response.Failed)})
+	writeJSONResponse(w, http.StatusOK, response)
+}
+
+// maxInFlightFromRequest reads the optional max_in_flight parameter, capped
+// at maxBulkDesiredLRPConcurrency.
+func maxInFlightFromRequest(req *http.Request) (int, error) {
+	maxInFlightString := req.FormValue("max_in_flight")
+	if maxInFlightString == "" {
+		return maxBulkDesiredLRPConcurrency, nil
+	}
+
+	maxInFlight, err := strconv.Atoi(maxInFlightString)
+	if err != nil || maxInFlight < 1 {
+		return 0, fmt.Errorf("invalid max_in_flight: %s", maxInFlightString)
+	}
+
+	if maxInFlight > maxBulkDesiredLRPConcurrency {
+		maxInFlight = maxBulkDesiredLRPConcurrency
+	}
+	return maxInFlight, nil
+}
+
+func bulkDesiredLRPError(processGuid string, err error) *receptor.Error {
+	bbsError := models.ConvertError(err)
+	switch bbsError.Type {
+	case models.Error_ResourceNotFound:
+		return &receptor.Error{
+			Type:    receptor.DesiredLRPNotFound,
+			Message: fmt.Sprintf("Desired LRP with guid '%s' not found", processGuid),
+		}
+	case models.Error_ResourceConflict:
+		return &receptor.Error{
+			Type:    receptor.ResourceConflict,
+			Message: fmt.Sprintf("Desired LRP with guid '%s' failed to update", processGuid),
+		}
+	default:
+		return &receptor.Error{
+			Type:    receptor.UnknownError,
+			Message: err.Error(),
+		}
+	}
+}
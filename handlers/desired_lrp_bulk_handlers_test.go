@@ -0,0 +1,520 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/cloudfoundry-incubator/bbs/fake_bbs"
+	"github.com/cloudfoundry-incubator/bbs/models"
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/handlers"
+	"github.com/cloudfoundry-incubator/receptor/store/fake_store"
+	"github.com/pivotal-golang/clock/fakeclock"
+	"github.com/pivotal-golang/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Desired LRP Bulk Handlers", func() {
+	var (
+		logger           lager.Logger
+		fakeBBS          *fake_bbs.FakeClient
+		fakeStoppedLRPs  *fake_store.FakeStoppedLRPStore
+		fakeClock        *fakeclock.FakeClock
+		responseRecorder *httptest.ResponseRecorder
+		handler          *handlers.DesiredLRPHandler
+		req              *http.Request
+	)
+
+	BeforeEach(func() {
+		fakeBBS = new(fake_bbs.FakeClient)
+		fakeStoppedLRPs = new(fake_store.FakeStoppedLRPStore)
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+		logger = lager.NewLogger("test")
+		logger.RegisterSink(lager.NewWriterSink(GinkgoWriter, lager.DEBUG))
+		responseRecorder = httptest.NewRecorder()
+		handler = handlers.NewDesiredLRPHandler(fakeBBS, fakeStoppedLRPs, fakeClock, logger)
+
+		fakeBBS.DesiredLRPsReturns([]*models.DesiredLRP{
+			{ProcessGuid: "process-guid-0", Domain: "the-domain", Instances: 2},
+			{ProcessGuid: "process-guid-1", Domain: "the-domain", Instances: 1},
+		}, nil)
+	})
+
+	bulkResponse := func() receptor.DesiredLRPsBulkResponse {
+		response := receptor.DesiredLRPsBulkResponse{}
+		err := json.Unmarshal(responseRecorder.Body.Bytes(), &response)
+		Expect(err).NotTo(HaveOccurred())
+		return response
+	}
+
+	Describe("ScaleAll", func() {
+		BeforeEach(func() {
+			req = newTestRequest(`{"instances":5}`)
+			req.Form = url.Values{":domain": []string{"the-domain"}}
+		})
+
+		JustBeforeEach(func() {
+			handler.ScaleAll(responseRecorder, req)
+		})
+
+		It("fetches the desired LRPs of the domain", func() {
+			Expect(fakeBBS.DesiredLRPsCallCount()).To(Equal(1))
+			Expect(fakeBBS.DesiredLRPsArgsForCall(0)).To(Equal(models.DesiredLRPFilter{Domain: "the-domain"}))
+		})
+
+		It("scales each of them", func() {
+			Expect(fakeBBS.UpdateDesiredLRPCallCount()).To(Equal(2))
+
+			processGuids := []string{}
+			for i := 0; i < 2; i++ {
+				processGuid, update := fakeBBS.UpdateDesiredLRPArgsForCall(i)
+				Expect(*update.Instances).To(BeEquivalentTo(5))
+				Expect(update.Routes).To(BeNil())
+				processGuids = append(processGuids, processGuid)
+			}
+			Expect(processGuids).To(ConsistOf("process-guid-0", "process-guid-1"))
+		})
+
+		It("responds with 200 and the processes that were scaled", func() {
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+			Expect(bulkResponse()).To(Equal(receptor.DesiredLRPsBulkResponse{
+				Succeeded: []string{"process-guid-0", "process-guid-1"},
+				Failed:    []receptor.DesiredLRPBulkFailure{},
+			}))
+		})
+
+		It("forgets the instance counts recorded when they were stopped", func() {
+			Expect(fakeStoppedLRPs.ForgetCallCount()).To(Equal(2))
+
+			processGuids := []string{}
+			for i := 0; i < 2; i++ {
+				processGuids = append(processGuids, fakeStoppedLRPs.ForgetArgsForCall(i))
+			}
+			Expect(processGuids).To(ConsistOf("process-guid-0", "process-guid-1"))
+		})
+
+		Context("when forgetting the recorded instance count of one of them fails", func() {
+			BeforeEach(func() {
+				fakeStoppedLRPs.ForgetStub = func(processGuid string) error {
+					if processGuid == "process-guid-1" {
+						return errors.New("oops")
+					}
+					return nil
+				}
+			})
+
+			It("does not scale it", func() {
+				Expect(fakeBBS.UpdateDesiredLRPCallCount()).To(Equal(1))
+				processGuid, _ := fakeBBS.UpdateDesiredLRPArgsForCall(0)
+				Expect(processGuid).To(Equal("process-guid-0"))
+			})
+
+			It("reports it as failed", func() {
+				response := bulkResponse()
+				Expect(response.Succeeded).To(Equal([]string{"process-guid-0"}))
+				Expect(response.Failed).To(HaveLen(1))
+				Expect(response.Failed[0].ProcessGuid).To(Equal("process-guid-1"))
+			})
+		})
+
+		Context("when updating one of them fails", func() {
+			BeforeEach(func() {
+				fakeBBS.UpdateDesiredLRPStub = func(processGuid string, _ *models.DesiredLRPUpdate) error {
+					if processGuid == "process-guid-1" {
+						return models.ErrResourceNotFound
+					}
+					return nil
+				}
+			})
+
+			It("reports the failure alongside the successes", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+				Expect(bulkResponse()).To(Equal(receptor.DesiredLRPsBulkResponse{
+					Succeeded: []string{"process-guid-0"},
+					Failed: []receptor.DesiredLRPBulkFailure{{
+						ProcessGuid: "process-guid-1",
+						Error: receptor.Error{
+							Type:    receptor.DesiredLRPNotFound,
+							Message: "Desired LRP with guid 'process-guid-1' not found",
+						},
+					}},
+				}))
+			})
+		})
+
+		Context("when the instance count is negative", func() {
+			BeforeEach(func() {
+				req = newTestRequest(`{"instances":-1}`)
+				req.Form = url.Values{":domain": []string{"the-domain"}}
+			})
+
+			It("responds with 400 without updating anything", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(fakeBBS.UpdateDesiredLRPCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the request is not valid JSON", func() {
+			BeforeEach(func() {
+				req = newTestRequest(`{`)
+				req.Form = url.Values{":domain": []string{"the-domain"}}
+			})
+
+			It("responds with 400", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("when max_in_flight is given", func() {
+			var (
+				lock        sync.Mutex
+				inFlight    int
+				maxInFlight int
+			)
+
+			BeforeEach(func() {
+				desiredLRPs := []*models.DesiredLRP{}
+				for _, guid := range []string{"a", "b", "c", "d", "e", "f"} {
+					desiredLRPs = append(desiredLRPs, &models.DesiredLRP{ProcessGuid: guid})
+				}
+				fakeBBS.DesiredLRPsReturns(desiredLRPs, nil)
+
+				inFlight, maxInFlight = 0, 0
+				fakeBBS.UpdateDesiredLRPStub = func(string, *models.DesiredLRPUpdate) error {
+					lock.Lock()
+					inFlight++
+					if inFlight > maxInFlight {
+						maxInFlight = inFlight
+					}
+					lock.Unlock()
+
+					time.Sleep(10 * time.Millisecond)
+
+					lock.Lock()
+					inFlight--
+					lock.Unlock()
+					return nil
+				}
+
+				req.Form.Set("max_in_flight", "2")
+			})
+
+			It("updates no more than that many at a time", func() {
+				Expect(fakeBBS.UpdateDesiredLRPCallCount()).To(Equal(6))
+				Expect(maxInFlight).To(Equal(2))
+			})
+		})
+
+		Context("when max_in_flight is invalid", func() {
+			BeforeEach(func() {
+				req.Form.Set("max_in_flight", "0")
+			})
+
+			It("responds with 400", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(fakeBBS.DesiredLRPsCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("RestartAll", func() {
+		var (
+			actualLRPs   map[string][]*models.ActualLRPGroup
+			clientClosed chan bool
+		)
+
+		BeforeEach(func() {
+			clientClosed = make(chan bool, 1)
+
+			req = newTestRequest("")
+			req.Form = url.Values{":domain": []string{"the-domain"}}
+
+			actualLRPs = map[string][]*models.ActualLRPGroup{
+				"process-guid-0": {
+					{Instance: &models.ActualLRP{ActualLRPKey: models.NewActualLRPKey("process-guid-0", 0, "the-domain")}},
+					{Instance: &models.ActualLRP{ActualLRPKey: models.NewActualLRPKey("process-guid-0", 1, "the-domain")}},
+				},
+				"process-guid-1": {
+					{Instance: &models.ActualLRP{ActualLRPKey: models.NewActualLRPKey("process-guid-1", 0, "the-domain")}},
+				},
+			}
+			fakeBBS.ActualLRPGroupsByProcessGuidStub = func(processGuid string) ([]*models.ActualLRPGroup, error) {
+				return actualLRPs[processGuid], nil
+			}
+		})
+
+		JustBeforeEach(func() {
+			handler.RestartAll(closeNotifyingRecorder{responseRecorder, clientClosed}, req)
+		})
+
+		It("kills every instance of every desired LRP", func() {
+			Expect(fakeBBS.RetireActualLRPCallCount()).To(Equal(3))
+
+			retired := []models.ActualLRPKey{}
+			for i := 0; i < 3; i++ {
+				retired = append(retired, *fakeBBS.RetireActualLRPArgsForCall(i))
+			}
+			Expect(retired).To(ConsistOf(
+				models.NewActualLRPKey("process-guid-0", 0, "the-domain"),
+				models.NewActualLRPKey("process-guid-0", 1, "the-domain"),
+				models.NewActualLRPKey("process-guid-1", 0, "the-domain"),
+			))
+		})
+
+		It("responds with 200 and the processes that were restarted", func() {
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+			Expect(bulkResponse().Succeeded).To(Equal([]string{"process-guid-0", "process-guid-1"}))
+		})
+
+		Context("when an instance has already gone away", func() {
+			BeforeEach(func() {
+				fakeBBS.RetireActualLRPReturns(models.ErrResourceNotFound)
+			})
+
+			It("still counts the restart as a success", func() {
+				Expect(bulkResponse().Failed).To(BeEmpty())
+			})
+		})
+
+		Context("when killing an instance fails", func() {
+			BeforeEach(func() {
+				fakeBBS.RetireActualLRPStub = func(key *models.ActualLRPKey) error {
+					if key.ProcessGuid == "process-guid-0" {
+						return errors.New("oops")
+					}
+					return nil
+				}
+			})
+
+			It("stops restarting that process and reports the failure", func() {
+				response := bulkResponse()
+				Expect(response.Succeeded).To(Equal([]string{"process-guid-1"}))
+				Expect(response.Failed).To(Equal([]receptor.DesiredLRPBulkFailure{{
+					ProcessGuid: "process-guid-0",
+					Error:       receptor.Error{Type: receptor.UnknownError, Message: "oops"},
+				}}))
+				Expect(fakeBBS.RetireActualLRPCallCount()).To(Equal(2))
+			})
+		})
+		Context("when the instances are running", func() {
+			var (
+				lock         sync.Mutex
+				retiredAfter map[int32]int
+				polls        int
+				replacement  *models.ActualLRPGroup
+				pollErr      error
+
+				stopIncrementing chan struct{}
+			)
+
+			BeforeEach(func() {
+				retiredAfter = map[int32]int{}
+				polls = 0
+				pollErr = nil
+
+				for _, groups := range actualLRPs {
+					for _, group := range groups {
+						group.Instance.State = models.ActualLRPStateRunning
+						group.Instance.InstanceGuid = "old-instance-guid"
+					}
+				}
+
+				replacement = &models.ActualLRPGroup{Instance: &models.ActualLRP{
+					ActualLRPKey:         models.NewActualLRPKey("process-guid-0", 0, "the-domain"),
+					ActualLRPInstanceKey: models.NewActualLRPInstanceKey("new-instance-guid", "cell-id"),
+					State:                models.ActualLRPStateRunning,
+				}}
+
+				fakeBBS.RetireActualLRPStub = func(key *models.ActualLRPKey) error {
+					lock.Lock()
+					defer lock.Unlock()
+					if key.ProcessGuid == "process-guid-0" {
+						retiredAfter[key.Index] = polls
+					}
+					return nil
+				}
+				fakeBBS.ActualLRPGroupByProcessGuidAndIndexStub = func(processGuid string, index int) (*models.ActualLRPGroup, error) {
+					lock.Lock()
+					defer lock.Unlock()
+					polls++
+					return replacement, pollErr
+				}
+
+				stopIncrementing = make(chan struct{})
+				go func(stop <-chan struct{}, clock *fakeclock.FakeClock) {
+					for {
+						select {
+						case <-stop:
+							return
+						case <-time.After(time.Millisecond):
+							clock.Increment(time.Second)
+						}
+					}
+				}(stopIncrementing, fakeClock)
+			})
+
+			AfterEach(func() {
+				close(stopIncrementing)
+			})
+
+			It("waits for the replacement of each instance to be running before killing the next one", func() {
+				Expect(bulkResponse().Failed).To(BeEmpty())
+
+				Expect(fakeBBS.ActualLRPGroupByProcessGuidAndIndexCallCount()).To(Equal(1))
+				processGuid, index := fakeBBS.ActualLRPGroupByProcessGuidAndIndexArgsForCall(0)
+				Expect(processGuid).To(Equal("process-guid-0"))
+				Expect(index).To(Equal(0))
+
+				Expect(retiredAfter).To(Equal(map[int32]int{0: 0, 1: 1}))
+			})
+
+			Context("when the replacement is not running yet", func() {
+				BeforeEach(func() {
+					fakeBBS.ActualLRPGroupByProcessGuidAndIndexStub = func(processGuid string, index int) (*models.ActualLRPGroup, error) {
+						lock.Lock()
+						defer lock.Unlock()
+						polls++
+						if polls < 3 {
+							return nil, models.ErrResourceNotFound
+						}
+						return replacement, nil
+					}
+				})
+
+				It("keeps polling until it is", func() {
+					Expect(bulkResponse().Failed).To(BeEmpty())
+					Expect(retiredAfter[1]).To(Equal(3))
+				})
+			})
+
+			Context("when the replacement is never running", func() {
+				BeforeEach(func() {
+					replacement.Instance.State = models.ActualLRPStateClaimed
+				})
+
+				It("gives up on that process and reports the failure", func() {
+					response := bulkResponse()
+					Expect(response.Succeeded).To(Equal([]string{"process-guid-1"}))
+					Expect(response.Failed).To(HaveLen(1))
+					Expect(response.Failed[0].ProcessGuid).To(Equal("process-guid-0"))
+					Expect(response.Failed[0].Error.Type).To(Equal(receptor.ActualLRPRestartTimedOut))
+
+					Expect(retiredAfter).NotTo(HaveKey(int32(1)))
+				})
+			})
+
+			Context("when the old instance is still the one running", func() {
+				BeforeEach(func() {
+					replacement.Instance.InstanceGuid = "old-instance-guid"
+				})
+
+				It("does not count it as the replacement", func() {
+					Expect(bulkResponse().Failed).To(HaveLen(1))
+				})
+			})
+
+			Context("when fetching the replacement fails", func() {
+				BeforeEach(func() {
+					pollErr = errors.New("oops")
+				})
+
+				It("stops restarting that process and reports the failure", func() {
+					response := bulkResponse()
+					Expect(response.Succeeded).To(Equal([]string{"process-guid-1"}))
+					Expect(response.Failed).To(Equal([]receptor.DesiredLRPBulkFailure{{
+						ProcessGuid: "process-guid-0",
+						Error:       receptor.Error{Type: receptor.UnknownError, Message: "oops"},
+					}}))
+				})
+			})
+
+			Context("when the client disconnects while waiting for a replacement", func() {
+				BeforeEach(func() {
+					replacement.Instance.State = models.ActualLRPStateClaimed
+
+					closed := clientClosed
+					fakeBBS.ActualLRPGroupByProcessGuidAndIndexStub = func(processGuid string, index int) (*models.ActualLRPGroup, error) {
+						lock.Lock()
+						defer lock.Unlock()
+						polls++
+						if polls == 1 {
+							closed <- true
+						}
+						return replacement, nil
+					}
+				})
+
+				It("stops killing the instances of that process", func() {
+					response := bulkResponse()
+					Expect(response.Failed).NotTo(BeEmpty())
+					Expect(response.Failed[0].ProcessGuid).To(Equal("process-guid-0"))
+					Expect(response.Failed[0].Error.Type).To(Equal(receptor.ActualLRPRestartCancelled))
+
+					Expect(retiredAfter).NotTo(HaveKey(int32(1)))
+				})
+			})
+		})
+	})
+
+	Describe("DeleteAll", func() {
+		BeforeEach(func() {
+			req = newTestRequest("")
+			req.Form = url.Values{":domain": []string{"the-domain"}}
+		})
+
+		JustBeforeEach(func() {
+			handler.DeleteAll(responseRecorder, req)
+		})
+
+		It("removes every desired LRP in the domain", func() {
+			Expect(fakeBBS.RemoveDesiredLRPCallCount()).To(Equal(2))
+			Expect([]string{
+				fakeBBS.RemoveDesiredLRPArgsForCall(0),
+				fakeBBS.RemoveDesiredLRPArgsForCall(1),
+			}).To(ConsistOf("process-guid-0", "process-guid-1"))
+		})
+
+		It("responds with 200 and the processes that were deleted", func() {
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+			Expect(bulkResponse().Succeeded).To(Equal([]string{"process-guid-0", "process-guid-1"}))
+		})
+
+		Context("when the domain is missing", func() {
+			BeforeEach(func() {
+				req.Form = url.Values{}
+			})
+
+			It("responds with 400 without fetching anything", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(fakeBBS.DesiredLRPsCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when fetching the desired LRPs fails", func() {
+			BeforeEach(func() {
+				fakeBBS.DesiredLRPsReturns(nil, errors.New("oops"))
+			})
+
+			It("responds with 500", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusInternalServerError))
+				Expect(fakeBBS.RemoveDesiredLRPCallCount()).To(Equal(0))
+			})
+		})
+	})
+})
+
+type closeNotifyingRecorder struct {
+	*httptest.ResponseRecorder
+	closed chan bool
+}
+
+func (r closeNotifyingRecorder) CloseNotify() <-chan bool {
+	return r.closed
+}
@@ -5,23 +5,30 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 
 	"github.com/cloudfoundry-incubator/bbs"
 	"github.com/cloudfoundry-incubator/bbs/models"
 	"github.com/cloudfoundry-incubator/receptor"
 	"github.com/cloudfoundry-incubator/receptor/serialization"
+	"github.com/cloudfoundry-incubator/receptor/store"
+	"github.com/pivotal-golang/clock"
 	"github.com/pivotal-golang/lager"
 )
 
 type DesiredLRPHandler struct {
-	bbs    bbs.Client
-	logger lager.Logger
+	bbs         bbs.Client
+	stoppedLRPs store.StoppedLRPStore
+	clock       clock.Clock
+	logger      lager.Logger
 }
 
-func NewDesiredLRPHandler(bbs bbs.Client, logger lager.Logger) *DesiredLRPHandler {
+func NewDesiredLRPHandler(bbs bbs.Client, stoppedLRPs store.StoppedLRPStore, clock clock.Clock, logger lager.Logger) *DesiredLRPHandler {
 	return &DesiredLRPHandler{
-		bbs:    bbs,
-		logger: logger.Session("desired-lrp-handler"),
+		bbs:         bbs,
+		stoppedLRPs: stoppedLRPs,
+		clock:       clock,
+		logger:      logger.Session("desired-lrp-handler"),
 	}
 }
 
@@ -36,6 +43,25 @@ func (h *DesiredLRPHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	dryRun, err := dryRunFromRequest(r)
+	if err != nil {
+		log.Error("invalid-dry-run", err)
+		writeBadRequestResponse(w, receptor.InvalidRequest, err)
+		return
+	}
+
+	if dryRun {
+		writeValidationResponse(w, validateDesiredLRPRequest(desireLRPRequest))
+		return
+	}
+
+	if fieldErrs := validateDesiredLRPRequest(desireLRPRequest); len(fieldErrs) > 0 {
+		validationErr := validationError(receptor.InvalidLRP, fieldErrs)
+		log.Error("lrp-request-invalid", validationErr)
+		writeJSONResponse(w, http.StatusBadRequest, validationErr)
+		return
+	}
+
 	desiredLRP := serialization.DesiredLRPFromRequest(desireLRPRequest)
 
 	err = h.bbs.DesireLRP(desiredLRP)
@@ -73,6 +99,13 @@ func (h *DesiredLRPHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	summary, err := summaryViewFromRequest(r)
+	if err != nil {
+		logger.Error("invalid-view", err)
+		writeBadRequestResponse(w, receptor.InvalidRequest, err)
+		return
+	}
+
 	desiredLRP, err := h.bbs.DesiredLRPByProcessGuid(processGuid)
 	if err != nil {
 		bbsError := models.ConvertError(err)
@@ -86,7 +119,15 @@ func (h *DesiredLRPHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	writeJSONResponse(w, http.StatusOK, serialization.DesiredLRPProtoToResponse(desiredLRP))
+	response := serialization.DesiredLRPProtoToResponse(desiredLRP)
+	w.Header().Set("ETag", response.ModificationTag.ETag())
+
+	if summary {
+		writeCacheableJSONResponse(w, r, serialization.DesiredLRPProtoToSummaryResponse(desiredLRP))
+		return
+	}
+
+	writeCacheableJSONResponse(w, r, response)
 }
 
 func (h *DesiredLRPHandler) Update(w http.ResponseWriter, r *http.Request) {
@@ -113,6 +154,15 @@ func (h *DesiredLRPHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	update := serialization.DesiredLRPUpdateFromRequest(desireLRPRequest)
 
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" {
+		h.updateIfMatch(w, logger, processGuid, ifMatch, update)
+		return
+	}
+
+	if !h.forgetStoppedInstances(w, logger, processGuid, update) {
+		return
+	}
+
 	updateAttempts := 0
 	for updateAttempts < 2 {
 		err = h.bbs.UpdateDesiredLRP(processGuid, update)
@@ -147,6 +197,200 @@ func (h *DesiredLRPHandler) Update(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// updateIfMatch applies the update only when the current ModificationTag of
+// the DesiredLRP satisfies the If-Match header. This is best-effort: the BBS
+// cannot make an update conditional on a ModificationTag, so an update which
+// lands between the check and UpdateDesiredLRP is overwritten rather than
+// failing the precondition. Compare-and-swap failures are not retried, as they
+// mean the DesiredLRP is being changed concurrently.
+func (h *DesiredLRPHandler) updateIfMatch(w http.ResponseWriter, logger lager.Logger, processGuid, ifMatch string, update *models.DesiredLRPUpdate) {
+	desiredLRP, err := h.bbs.DesiredLRPByProcessGuid(processGuid)
+	if err != nil {
+		bbsError := models.ConvertError(err)
+		if bbsError.Type == models.Error_ResourceNotFound {
+			logger.Error("desired-lrp-not-found", err)
+			writeDesiredLRPNotFoundResponse(w, processGuid)
+			return
+		}
+
+		logger.Error("unknown-error", err)
+		writeUnknownErrorResponse(w, err)
+		return
+	}
+
+	current := serialization.DesiredLRPProtoToSummaryResponse(desiredLRP)
+	etag := current.ModificationTag.ETag()
+	if !ifMatchSatisfied(ifMatch, etag) {
+		logger.Info("modification-tag-mismatch", lager.Data{"if-match": ifMatch, "etag": etag})
+		writePreconditionFailedResponse(w, processGuid)
+		return
+	}
+
+	if !h.forgetStoppedInstances(w, logger, processGuid, update) {
+		return
+	}
+
+	err = h.bbs.UpdateDesiredLRP(processGuid, update)
+	if err != nil {
+		bbsError := models.ConvertError(err)
+		switch bbsError.Type {
+		case models.Error_ResourceNotFound:
+			logger.Error("desired-lrp-not-found", err)
+			writeDesiredLRPNotFoundResponse(w, processGuid)
+			return
+		case models.Error_ResourceConflict:
+			logger.Error("failed-to-compare-and-swap", err)
+			writePreconditionFailedResponse(w, processGuid)
+			return
+		default:
+			logger.Error("unknown-error", err)
+			writeUnknownErrorResponse(w, err)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Stop scales the DesiredLRP to zero, recording its instance count so that
+// Start can restore it. Stopping a stopped DesiredLRP does nothing.
+func (h *DesiredLRPHandler) Stop(w http.ResponseWriter, req *http.Request) {
+	processGuid := req.FormValue(":process_guid")
+	logger := h.logger.Session("stop", lager.Data{
+		"ProcessGuid": processGuid,
+	})
+
+	desiredLRP, ok := h.fetchDesiredLRP(w, logger, processGuid)
+	if !ok {
+		return
+	}
+
+	err := h.stoppedLRPs.Record(desiredLRP, desiredLRP.Instances)
+	if err == store.ErrAlreadyStopped {
+		logger.Info("already-stopped")
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if err != nil {
+		logger.Error("failed-to-record-instances", err)
+		writeUnknownErrorResponse(w, err)
+		return
+	}
+
+	instances := int32(0)
+	err = h.bbs.UpdateDesiredLRP(processGuid, &models.DesiredLRPUpdate{Instances: &instances})
+	if err != nil {
+		forgetErr := h.stoppedLRPs.Forget(processGuid)
+		if forgetErr != nil {
+			logger.Error("failed-to-forget-instances", forgetErr)
+		}
+
+		writeUpdateErrorResponse(w, logger, processGuid, err)
+		return
+	}
+
+	logger.Info("stopped", lager.Data{"instances": desiredLRP.Instances})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Start restores the instance count recorded by Stop.
+func (h *DesiredLRPHandler) Start(w http.ResponseWriter, req *http.Request) {
+	processGuid := req.FormValue(":process_guid")
+	logger := h.logger.Session("start", lager.Data{
+		"ProcessGuid": processGuid,
+	})
+
+	desiredLRP, ok := h.fetchDesiredLRP(w, logger, processGuid)
+	if !ok {
+		return
+	}
+
+	instances, err := h.stoppedLRPs.Claim(desiredLRP)
+	if err == store.ErrNotStopped {
+		writeJSONResponse(w, http.StatusConflict, receptor.Error{
+			Type:    receptor.DesiredLRPNotStopped,
+			Message: fmt.Sprintf("Desired LRP with guid '%s' is not stopped", processGuid),
+		})
+		return
+	}
+	if err != nil {
+		logger.Error("failed-to-claim-instances", err)
+		writeUnknownErrorResponse(w, err)
+		return
+	}
+
+	err = h.bbs.UpdateDesiredLRP(processGuid, &models.DesiredLRPUpdate{Instances: &instances})
+	if err != nil {
+		recordErr := h.stoppedLRPs.Record(desiredLRP, instances)
+		if recordErr != nil {
+			logger.Error("failed-to-record-instances", recordErr)
+		}
+
+		writeUpdateErrorResponse(w, logger, processGuid, err)
+		return
+	}
+
+	logger.Info("started", lager.Data{"instances": instances})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// forgetStoppedInstances discards the instance count recorded by Stop before
+// an update sets the instance count, so that a later Start does not restore a
+// stale one.
+func (h *DesiredLRPHandler) forgetStoppedInstances(w http.ResponseWriter, logger lager.Logger, processGuid string, update *models.DesiredLRPUpdate) bool {
+	if update.Instances == nil {
+		return true
+	}
+
+	err := h.stoppedLRPs.Forget(processGuid)
+	if err != nil {
+		logger.Error("failed-to-forget-instances", err)
+		writeUnknownErrorResponse(w, err)
+		return false
+	}
+	return true
+}
+
+func (h *DesiredLRPHandler) fetchDesiredLRP(w http.ResponseWriter, logger lager.Logger, processGuid string) (*models.DesiredLRP, bool) {
+	if processGuid == "" {
+		err := errors.New("process_guid missing from request")
+		logger.Error("missing-process-guid", err)
+		writeBadRequestResponse(w, receptor.InvalidRequest, err)
+		return nil, false
+	}
+
+	desiredLRP, err := h.bbs.DesiredLRPByProcessGuid(processGuid)
+	if err != nil {
+		bbsError := models.ConvertError(err)
+		if bbsError.Type == models.Error_ResourceNotFound {
+			logger.Error("desired-lrp-not-found", err)
+			writeDesiredLRPNotFoundResponse(w, processGuid)
+			return nil, false
+		}
+
+		logger.Error("failed-to-fetch-desired-lrp", err)
+		writeUnknownErrorResponse(w, err)
+		return nil, false
+	}
+
+	return desiredLRP, true
+}
+
+func writeUpdateErrorResponse(w http.ResponseWriter, logger lager.Logger, processGuid string, err error) {
+	bbsError := models.ConvertError(err)
+	switch bbsError.Type {
+	case models.Error_ResourceNotFound:
+		logger.Error("desired-lrp-not-found", err)
+		writeDesiredLRPNotFoundResponse(w, processGuid)
+	case models.Error_ResourceConflict:
+		logger.Error("failed-to-compare-and-swap", err)
+		writeCompareAndSwapFailedResponse(w, processGuid)
+	default:
+		logger.Error("failed-to-update-desired-lrp", err)
+		writeUnknownErrorResponse(w, err)
+	}
+}
+
 func (h *DesiredLRPHandler) Delete(w http.ResponseWriter, req *http.Request) {
 	processGuid := req.FormValue(":process_guid")
 	logger := h.logger.Session("delete", lager.Data{
@@ -182,13 +426,65 @@ func (h *DesiredLRPHandler) GetAll(w http.ResponseWriter, req *http.Request) {
 		"domain": domain,
 	})
 
+	page, err := paginationFromRequest(req)
+	if err != nil {
+		logger.Error("invalid-pagination", err)
+		writeBadRequestResponse(w, receptor.InvalidRequest, err)
+		return
+	}
+
+	summary, err := summaryViewFromRequest(req)
+	if err != nil {
+		logger.Error("invalid-view", err)
+		writeBadRequestResponse(w, receptor.InvalidRequest, err)
+		return
+	}
+
 	filter := models.DesiredLRPFilter{Domain: domain}
 	desiredLRPs, err := h.bbs.DesiredLRPs(filter)
+	if err == nil {
+		desiredLRPs = paginateDesiredLRPs(w, req, page, desiredLRPs)
+	}
 
-	writeDesiredLRPProtoResponse(w, logger, desiredLRPs, err)
+	if summary {
+		writeDesiredLRPSummaryResponse(w, req, logger, desiredLRPs, err)
+		return
+	}
+
+	writeDesiredLRPProtoResponse(w, req, logger, desiredLRPs, err)
 }
 
-func writeDesiredLRPProtoResponse(w http.ResponseWriter, logger lager.Logger, desiredLRPs []*models.DesiredLRP, err error) {
+// summaryViewFromRequest reports whether the request asked for the
+// DesiredLRPSummaryResponse projection with view=summary.
+func summaryViewFromRequest(req *http.Request) (bool, error) {
+	switch view := req.FormValue("view"); view {
+	case "", "full":
+		return false, nil
+	case "summary":
+		return true, nil
+	default:
+		return false, fmt.Errorf("unknown view: %s", view)
+	}
+}
+
+func paginateDesiredLRPs(w http.ResponseWriter, req *http.Request, page pagination, desiredLRPs []*models.DesiredLRP) []*models.DesiredLRP {
+	sort.Sort(desiredLRPsByProcessGuid(desiredLRPs))
+
+	start, end := page.page(len(desiredLRPs), func(i int) string { return desiredLRPs[i].ProcessGuid })
+	if end < len(desiredLRPs) {
+		writeNextPageLink(w, req, desiredLRPs[end-1].ProcessGuid)
+	}
+
+	return desiredLRPs[start:end]
+}
+
+type desiredLRPsByProcessGuid []*models.DesiredLRP
+
+func (d desiredLRPsByProcessGuid) Len() int           { return len(d) }
+func (d desiredLRPsByProcessGuid) Swap(i, j int)      { d[i], d[j] = d[j], d[i] }
+func (d desiredLRPsByProcessGuid) Less(i, j int) bool { return d[i].ProcessGuid < d[j].ProcessGuid }
+
+func writeDesiredLRPProtoResponse(w http.ResponseWriter, req *http.Request, logger lager.Logger, desiredLRPs []*models.DesiredLRP, err error) {
 	if err != nil {
 		logger.Error("failed-to-fetch-desired-lrps", err)
 		writeUnknownErrorResponse(w, err)
@@ -200,7 +496,22 @@ func writeDesiredLRPProtoResponse(w http.ResponseWriter, logger lager.Logger, de
 		responses = append(responses, serialization.DesiredLRPProtoToResponse(desiredLRP))
 	}
 
-	writeJSONResponse(w, http.StatusOK, responses)
+	writeCacheableJSONResponse(w, req, responses)
+}
+
+func writeDesiredLRPSummaryResponse(w http.ResponseWriter, req *http.Request, logger lager.Logger, desiredLRPs []*models.DesiredLRP, err error) {
+	if err != nil {
+		logger.Error("failed-to-fetch-desired-lrps", err)
+		writeUnknownErrorResponse(w, err)
+		return
+	}
+
+	responses := make([]receptor.DesiredLRPSummaryResponse, 0, len(desiredLRPs))
+	for _, desiredLRP := range desiredLRPs {
+		responses = append(responses, serialization.DesiredLRPProtoToSummaryResponse(desiredLRP))
+	}
+
+	writeCacheableJSONResponse(w, req, responses)
 }
 
 func writeCompareAndSwapFailedResponse(w http.ResponseWriter, processGuid string) {
@@ -210,6 +521,13 @@ func writeCompareAndSwapFailedResponse(w http.ResponseWriter, processGuid string
 	})
 }
 
+func writePreconditionFailedResponse(w http.ResponseWriter, processGuid string) {
+	writeJSONResponse(w, http.StatusPreconditionFailed, receptor.Error{
+		Type:    receptor.PreconditionFailed,
+		Message: fmt.Sprintf("Desired LRP with guid '%s' has been modified", processGuid),
+	})
+}
+
 func writeDesiredLRPNotFoundResponse(w http.ResponseWriter, processGuid string) {
 	writeJSONResponse(w, http.StatusNotFound, receptor.Error{
 		Type:    receptor.DesiredLRPNotFound,
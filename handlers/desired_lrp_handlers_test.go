@@ -1,16 +1,22 @@
 package handlers_test
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"time"
 
 	"github.com/cloudfoundry-incubator/bbs/fake_bbs"
 	"github.com/cloudfoundry-incubator/bbs/models"
 	"github.com/cloudfoundry-incubator/receptor"
 	"github.com/cloudfoundry-incubator/receptor/handlers"
+	"github.com/cloudfoundry-incubator/receptor/store"
+	"github.com/cloudfoundry-incubator/receptor/store/fake_store"
+	"github.com/pivotal-golang/clock/fakeclock"
 	"github.com/pivotal-golang/lager"
 
 	. "github.com/onsi/ginkgo"
@@ -21,23 +27,27 @@ var _ = Describe("Desired LRP Handlers", func() {
 	var (
 		logger           lager.Logger
 		fakeBBS          *fake_bbs.FakeClient
+		fakeStoppedLRPs  *fake_store.FakeStoppedLRPStore
+		fakeClock        *fakeclock.FakeClock
 		responseRecorder *httptest.ResponseRecorder
 		handler          *handlers.DesiredLRPHandler
 	)
 
 	BeforeEach(func() {
 		fakeBBS = new(fake_bbs.FakeClient)
+		fakeStoppedLRPs = new(fake_store.FakeStoppedLRPStore)
+		fakeClock = fakeclock.NewFakeClock(time.Now())
 		logger = lager.NewLogger("test")
 		logger.RegisterSink(lager.NewWriterSink(GinkgoWriter, lager.DEBUG))
 		responseRecorder = httptest.NewRecorder()
-		handler = handlers.NewDesiredLRPHandler(fakeBBS, logger)
+		handler = handlers.NewDesiredLRPHandler(fakeBBS, fakeStoppedLRPs, fakeClock, logger)
 	})
 
 	Describe("Create", func() {
 		validCreateLRPRequest := receptor.DesiredLRPCreateRequest{
 			ProcessGuid: "the-process-guid",
 			Domain:      "the-domain",
-			RootFS:      "the-rootfs",
+			RootFS:      "preloaded:the-rootfs",
 			Privileged:  true,
 			Instances:   1,
 			Routes:      receptor.RoutingInfo{},
@@ -50,7 +60,7 @@ var _ = Describe("Desired LRP Handlers", func() {
 		expectedDesiredLRP := &models.DesiredLRP{
 			ProcessGuid: "the-process-guid",
 			Domain:      "the-domain",
-			RootFs:      "the-rootfs",
+			RootFs:      "preloaded:the-rootfs",
 			Privileged:  true,
 			Instances:   1,
 			Action: models.WrapAction(&models.RunAction{
@@ -61,6 +71,67 @@ var _ = Describe("Desired LRP Handlers", func() {
 			ModificationTag: &models.ModificationTag{},
 		}
 
+		Context("when dry_run is requested", func() {
+			var createRequest receptor.DesiredLRPCreateRequest
+			var validation receptor.ValidationResponse
+
+			BeforeEach(func() {
+				createRequest = validCreateLRPRequest
+			})
+
+			JustBeforeEach(func() {
+				request := newTestRequest(createRequest)
+				request.URL.RawQuery = "dry_run=true"
+				handler.Create(responseRecorder, request)
+
+				validation = receptor.ValidationResponse{}
+				err := json.Unmarshal(responseRecorder.Body.Bytes(), &validation)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("does not desire the LRP", func() {
+				Expect(fakeBBS.DesireLRPCallCount()).To(BeZero())
+			})
+
+			Context("when the request is valid", func() {
+				It("responds with 200 and no errors", func() {
+					Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+					Expect(validation.Valid).To(BeTrue())
+					Expect(validation.Errors).To(BeEmpty())
+				})
+			})
+
+			Context("when the request is invalid", func() {
+				BeforeEach(func() {
+					createRequest.ProcessGuid = ""
+					createRequest.RootFS = "the-rootfs"
+					createRequest.Instances = -1
+					createRequest.CPUWeight = 101
+					createRequest.Ports = []uint16{8080, 0, 8080}
+					createRequest.EnvironmentVariables = []receptor.EnvironmentVariable{{Value: "nameless"}}
+				})
+
+				It("reports every invalid field", func() {
+					Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+					Expect(validation.Valid).To(BeFalse())
+
+					fields := map[string]string{}
+					for _, fieldErr := range validation.Errors {
+						fields[fieldErr.Field] = fieldErr.Code
+					}
+					Expect(fields).To(Equal(map[string]string{
+						"process_guid": receptor.FieldRequired,
+						"rootfs":       receptor.FieldInvalid,
+						"instances":    receptor.FieldOutOfRange,
+						"cpu_weight":   receptor.FieldOutOfRange,
+						"ports[1]":     receptor.FieldOutOfRange,
+						"ports[2]":     receptor.FieldDuplicate,
+						"env[0].name":  receptor.FieldRequired,
+					}))
+				})
+			})
+		})
+
 		Context("when everything succeeds", func() {
 			BeforeEach(func(done Done) {
 				defer close(done)
@@ -109,6 +180,35 @@ var _ = Describe("Desired LRP Handlers", func() {
 			})
 		})
 
+		Context("when the desired LRP fails validation", func() {
+			BeforeEach(func() {
+				invalidRequest := validCreateLRPRequest
+				invalidRequest.Domain = ""
+				invalidRequest.Ports = []uint16{0}
+
+				handler.Create(responseRecorder, newTestRequest(invalidRequest))
+			})
+
+			It("does not desire the LRP", func() {
+				Expect(fakeBBS.DesireLRPCallCount()).To(BeZero())
+			})
+
+			It("responds with 400 and the invalid fields", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+
+				var receptorError receptor.Error
+				err := json.Unmarshal(responseRecorder.Body.Bytes(), &receptorError)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(receptorError.Type).To(Equal(receptor.InvalidLRP))
+				Expect(receptorError.Message).To(Equal("domain is required; port must be between 1 and 65535"))
+				Expect(receptorError.FieldErrors).To(Equal([]receptor.FieldError{
+					{Field: "domain", Code: receptor.FieldRequired, Message: "domain is required"},
+					{Field: "ports[0]", Code: receptor.FieldOutOfRange, Message: "port must be between 1 and 65535"},
+				}))
+			})
+		})
+
 		Context("when the desired LRP is invalid", func() {
 			BeforeEach(func(done Done) {
 				fakeBBS.DesireLRPReturns(models.ErrBadRequest)
@@ -199,6 +299,7 @@ var _ = Describe("Desired LRP Handlers", func() {
 						User: "me",
 						Path: "the-path",
 					}),
+					ModificationTag: &models.ModificationTag{Epoch: "some-epoch", Index: 1},
 				}, nil)
 			})
 
@@ -218,6 +319,66 @@ var _ = Describe("Desired LRP Handlers", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(response.ProcessGuid).To(Equal("process-guid-0"))
 			})
+
+			It("responds with the modification tag as an ETag", func() {
+				Expect(responseRecorder.Header().Get("ETag")).To(Equal(`"some-epoch:1"`))
+			})
+
+			Context("when the If-None-Match header matches the ETag", func() {
+				BeforeEach(func() {
+					req.Header.Set("If-None-Match", `"some-epoch:1"`)
+				})
+
+				It("responds with 304 Not Modified and no body", func() {
+					Expect(responseRecorder.Code).To(Equal(http.StatusNotModified))
+					Expect(responseRecorder.Body.Len()).To(BeZero())
+				})
+			})
+
+			Context("when the If-None-Match header holds the ETag as a weak entity tag", func() {
+				BeforeEach(func() {
+					req.Header.Set("If-None-Match", `W/"some-epoch:1"`)
+				})
+
+				It("responds with 304 Not Modified", func() {
+					Expect(responseRecorder.Code).To(Equal(http.StatusNotModified))
+				})
+			})
+
+			Context("when the If-None-Match header does not match the ETag", func() {
+				BeforeEach(func() {
+					req.Header.Set("If-None-Match", `"some-epoch:0"`)
+				})
+
+				It("responds with 200 Status OK", func() {
+					Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+				})
+			})
+
+			Context("when the summary view is requested", func() {
+				BeforeEach(func() {
+					req.Form.Set("view", "summary")
+				})
+
+				It("returns a desired lrp summary response", func() {
+					var response map[string]interface{}
+					err := json.Unmarshal(responseRecorder.Body.Bytes(), &response)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(response["process_guid"]).To(Equal("process-guid-0"))
+					Expect(response["domain"]).To(Equal("domain-1"))
+					Expect(response).NotTo(HaveKey("action"))
+				})
+			})
+
+			Context("when an unknown view is requested", func() {
+				BeforeEach(func() {
+					req.Form.Set("view", "bogus")
+				})
+
+				It("responds with 400 Bad Request", func() {
+					Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+				})
+			})
 		})
 
 		Context("when reading from the BBS fails", func() {
@@ -332,6 +493,39 @@ var _ = Describe("Desired LRP Handlers", func() {
 			It("responds with an empty body", func() {
 				Expect(responseRecorder.Body.String()).To(Equal(""))
 			})
+
+			It("forgets the instance count recorded when the desired LRP was stopped", func() {
+				Expect(fakeStoppedLRPs.ForgetCallCount()).To(Equal(1))
+				Expect(fakeStoppedLRPs.ForgetArgsForCall(0)).To(Equal(expectedProcessGuid))
+			})
+		})
+
+		Context("when the instance count is not updated", func() {
+			BeforeEach(func() {
+				req = newTestRequest(receptor.DesiredLRPUpdateRequest{Annotation: &annotation})
+				req.Form = url.Values{":process_guid": []string{expectedProcessGuid}}
+				handler.Update(responseRecorder, req)
+			})
+
+			It("keeps the instance count recorded when the desired LRP was stopped", func() {
+				Expect(fakeStoppedLRPs.ForgetCallCount()).To(Equal(0))
+				Expect(fakeBBS.UpdateDesiredLRPCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when forgetting the instance count recorded by stop fails", func() {
+			BeforeEach(func() {
+				fakeStoppedLRPs.ForgetReturns(errors.New("oops"))
+				handler.Update(responseRecorder, req)
+			})
+
+			It("does not call UpdateDesiredLRP on the BBS", func() {
+				Expect(fakeBBS.UpdateDesiredLRPCallCount()).To(Equal(0))
+			})
+
+			It("responds with 500 INTERNAL ERROR", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusInternalServerError))
+			})
 		})
 
 		Context("when the :process_guid is blank", func() {
@@ -430,6 +624,105 @@ var _ = Describe("Desired LRP Handlers", func() {
 			})
 		})
 
+		Context("when an If-Match header is provided", func() {
+			BeforeEach(func() {
+				fakeBBS.DesiredLRPByProcessGuidReturns(&models.DesiredLRP{
+					ProcessGuid:     expectedProcessGuid,
+					ModificationTag: &models.ModificationTag{Epoch: "some-epoch", Index: 3},
+				}, nil)
+			})
+
+			JustBeforeEach(func() {
+				handler.Update(responseRecorder, req)
+			})
+
+			Context("when the modification tag matches", func() {
+				BeforeEach(func() {
+					req.Header.Set("If-Match", `"some-epoch:3"`)
+				})
+
+				It("calls UpdateDesiredLRP on the BBS", func() {
+					Expect(fakeBBS.UpdateDesiredLRPCallCount()).To(Equal(1))
+					processGuid, update := fakeBBS.UpdateDesiredLRPArgsForCall(0)
+					Expect(processGuid).To(Equal(expectedProcessGuid))
+					Expect(update).To(Equal(expectedUpdate))
+				})
+
+				It("responds with 204 NO CONTENT", func() {
+					Expect(responseRecorder.Code).To(Equal(http.StatusNoContent))
+				})
+
+				Context("when the BBS returns a Compare and Swap error", func() {
+					BeforeEach(func() {
+						fakeBBS.UpdateDesiredLRPReturns(models.ErrResourceConflict)
+					})
+
+					It("does not retry", func() {
+						Expect(fakeBBS.UpdateDesiredLRPCallCount()).To(Equal(1))
+					})
+
+					It("responds with 412 PRECONDITION FAILED", func() {
+						Expect(responseRecorder.Code).To(Equal(http.StatusPreconditionFailed))
+					})
+				})
+			})
+
+			Context("when the header holds a weak entity tag", func() {
+				BeforeEach(func() {
+					req.Header.Set("If-Match", `W/"some-epoch:3"`)
+				})
+
+				It("does not count it as a match", func() {
+					Expect(fakeBBS.UpdateDesiredLRPCallCount()).To(Equal(0))
+					Expect(responseRecorder.Code).To(Equal(http.StatusPreconditionFailed))
+				})
+			})
+
+			Context("when the header lists the modification tag among others", func() {
+				BeforeEach(func() {
+					req.Header.Set("If-Match", `"some-epoch:2", "some-epoch:3"`)
+				})
+
+				It("calls UpdateDesiredLRP on the BBS", func() {
+					Expect(fakeBBS.UpdateDesiredLRPCallCount()).To(Equal(1))
+				})
+			})
+
+			Context("when the modification tag does not match", func() {
+				BeforeEach(func() {
+					req.Header.Set("If-Match", `"some-epoch:2"`)
+				})
+
+				It("does not call UpdateDesiredLRP on the BBS", func() {
+					Expect(fakeBBS.UpdateDesiredLRPCallCount()).To(Equal(0))
+				})
+
+				It("responds with 412 PRECONDITION FAILED", func() {
+					Expect(responseRecorder.Code).To(Equal(http.StatusPreconditionFailed))
+				})
+
+				It("responds with a relevant error message", func() {
+					expectedBody, _ := json.Marshal(receptor.Error{
+						Type:    receptor.PreconditionFailed,
+						Message: "Desired LRP with guid 'some-guid' has been modified",
+					})
+
+					Expect(responseRecorder.Body.String()).To(Equal(string(expectedBody)))
+				})
+			})
+
+			Context("when the LRP does not exist", func() {
+				BeforeEach(func() {
+					req.Header.Set("If-Match", `"some-epoch:3"`)
+					fakeBBS.DesiredLRPByProcessGuidReturns(nil, models.ErrResourceNotFound)
+				})
+
+				It("responds with 404 NOT FOUND", func() {
+					Expect(responseRecorder.Code).To(Equal(http.StatusNotFound))
+				})
+			})
+		})
+
 		Context("when the BBS indicates the LRP was not found", func() {
 			BeforeEach(func(done Done) {
 				defer close(done)
@@ -487,6 +780,243 @@ var _ = Describe("Desired LRP Handlers", func() {
 		})
 	})
 
+	Describe("Stop", func() {
+		var (
+			req        *http.Request
+			desiredLRP *models.DesiredLRP
+		)
+
+		BeforeEach(func() {
+			req = newTestRequest("")
+			req.Form = url.Values{":process_guid": []string{"process-guid-0"}}
+
+			desiredLRP = &models.DesiredLRP{
+				ProcessGuid: "process-guid-0",
+				Instances:   3,
+			}
+			fakeBBS.DesiredLRPByProcessGuidReturns(desiredLRP, nil)
+		})
+
+		JustBeforeEach(func() {
+			handler.Stop(responseRecorder, req)
+		})
+
+		It("records the instance count of the desired LRP", func() {
+			Expect(fakeStoppedLRPs.RecordCallCount()).To(Equal(1))
+			recorded, instances := fakeStoppedLRPs.RecordArgsForCall(0)
+			Expect(recorded).To(Equal(desiredLRP))
+			Expect(instances).To(BeEquivalentTo(3))
+		})
+
+		It("scales the desired LRP to zero, leaving its routes alone", func() {
+			Expect(fakeBBS.UpdateDesiredLRPCallCount()).To(Equal(1))
+			processGuid, update := fakeBBS.UpdateDesiredLRPArgsForCall(0)
+			Expect(processGuid).To(Equal("process-guid-0"))
+			Expect(*update.Instances).To(BeEquivalentTo(0))
+			Expect(update.Routes).To(BeNil())
+		})
+
+		It("responds with 204 NO CONTENT", func() {
+			Expect(responseRecorder.Code).To(Equal(http.StatusNoContent))
+		})
+
+		Context("when the desired LRP is already stopped", func() {
+			BeforeEach(func() {
+				fakeStoppedLRPs.RecordReturns(store.ErrAlreadyStopped)
+			})
+
+			It("does not update the desired LRP", func() {
+				Expect(fakeBBS.UpdateDesiredLRPCallCount()).To(Equal(0))
+			})
+
+			It("responds with 204 NO CONTENT", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusNoContent))
+			})
+		})
+
+		Context("when recording the instance count fails", func() {
+			BeforeEach(func() {
+				fakeStoppedLRPs.RecordReturns(errors.New("oops"))
+			})
+
+			It("does not update the desired LRP", func() {
+				Expect(fakeBBS.UpdateDesiredLRPCallCount()).To(Equal(0))
+			})
+
+			It("responds with 500 INTERNAL SERVER ERROR", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		Context("when the desired LRP does not exist", func() {
+			BeforeEach(func() {
+				fakeBBS.DesiredLRPByProcessGuidReturns(nil, models.ErrResourceNotFound)
+			})
+
+			It("does not record anything", func() {
+				Expect(fakeStoppedLRPs.RecordCallCount()).To(Equal(0))
+			})
+
+			It("responds with 404 NOT FOUND", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		Context("when the update conflicts with a concurrent one", func() {
+			BeforeEach(func() {
+				fakeBBS.UpdateDesiredLRPReturns(models.ErrResourceConflict)
+			})
+
+			It("forgets the recorded instance count", func() {
+				Expect(fakeStoppedLRPs.ForgetCallCount()).To(Equal(1))
+				Expect(fakeStoppedLRPs.ForgetArgsForCall(0)).To(Equal("process-guid-0"))
+			})
+
+			It("responds with a ResourceConflict error", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusInternalServerError))
+
+				var responseError receptor.Error
+				err := json.Unmarshal(responseRecorder.Body.Bytes(), &responseError)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(responseError.Type).To(Equal(receptor.ResourceConflict))
+			})
+		})
+
+		Context("when the update fails", func() {
+			BeforeEach(func() {
+				fakeBBS.UpdateDesiredLRPReturns(errors.New("oops"))
+			})
+
+			It("forgets the recorded instance count", func() {
+				Expect(fakeStoppedLRPs.ForgetCallCount()).To(Equal(1))
+			})
+
+			It("responds with 500 INTERNAL SERVER ERROR", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		Context("when the process guid is not provided", func() {
+			BeforeEach(func() {
+				req.Form = url.Values{}
+			})
+
+			It("does not fetch the desired LRP", func() {
+				Expect(fakeBBS.DesiredLRPByProcessGuidCallCount()).To(Equal(0))
+			})
+
+			It("responds with 400 BAD REQUEST", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
+	Describe("Start", func() {
+		var (
+			req        *http.Request
+			desiredLRP *models.DesiredLRP
+		)
+
+		BeforeEach(func() {
+			req = newTestRequest("")
+			req.Form = url.Values{":process_guid": []string{"process-guid-0"}}
+
+			desiredLRP = &models.DesiredLRP{
+				ProcessGuid: "process-guid-0",
+				Instances:   0,
+			}
+			fakeBBS.DesiredLRPByProcessGuidReturns(desiredLRP, nil)
+			fakeStoppedLRPs.ClaimReturns(3, nil)
+		})
+
+		JustBeforeEach(func() {
+			handler.Start(responseRecorder, req)
+		})
+
+		It("claims the recorded instance count", func() {
+			Expect(fakeStoppedLRPs.ClaimCallCount()).To(Equal(1))
+			Expect(fakeStoppedLRPs.ClaimArgsForCall(0)).To(Equal(desiredLRP))
+		})
+
+		It("restores the recorded instance count, leaving the routes alone", func() {
+			Expect(fakeBBS.UpdateDesiredLRPCallCount()).To(Equal(1))
+			processGuid, update := fakeBBS.UpdateDesiredLRPArgsForCall(0)
+			Expect(processGuid).To(Equal("process-guid-0"))
+			Expect(*update.Instances).To(BeEquivalentTo(3))
+			Expect(update.Routes).To(BeNil())
+		})
+
+		It("responds with 204 NO CONTENT", func() {
+			Expect(responseRecorder.Code).To(Equal(http.StatusNoContent))
+		})
+
+		Context("when the desired LRP is not stopped", func() {
+			BeforeEach(func() {
+				fakeStoppedLRPs.ClaimReturns(0, store.ErrNotStopped)
+			})
+
+			It("does not update the desired LRP", func() {
+				Expect(fakeBBS.UpdateDesiredLRPCallCount()).To(Equal(0))
+			})
+
+			It("responds with 409 CONFLICT", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusConflict))
+			})
+
+			It("returns a DesiredLRPNotStopped error", func() {
+				var responseError receptor.Error
+				err := json.Unmarshal(responseRecorder.Body.Bytes(), &responseError)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(responseError).To(Equal(receptor.Error{
+					Type:    receptor.DesiredLRPNotStopped,
+					Message: "Desired LRP with guid 'process-guid-0' is not stopped",
+				}))
+			})
+		})
+
+		Context("when claiming the recorded instance count fails", func() {
+			BeforeEach(func() {
+				fakeStoppedLRPs.ClaimReturns(0, errors.New("oops"))
+			})
+
+			It("does not update the desired LRP", func() {
+				Expect(fakeBBS.UpdateDesiredLRPCallCount()).To(Equal(0))
+			})
+
+			It("responds with 500 INTERNAL SERVER ERROR", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		Context("when the desired LRP does not exist", func() {
+			BeforeEach(func() {
+				fakeBBS.DesiredLRPByProcessGuidReturns(nil, models.ErrResourceNotFound)
+			})
+
+			It("responds with 404 NOT FOUND", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		Context("when the update fails", func() {
+			BeforeEach(func() {
+				fakeBBS.UpdateDesiredLRPReturns(errors.New("oops"))
+			})
+
+			It("records the claimed instance count again", func() {
+				Expect(fakeStoppedLRPs.RecordCallCount()).To(Equal(1))
+				recorded, instances := fakeStoppedLRPs.RecordArgsForCall(0)
+				Expect(recorded).To(Equal(desiredLRP))
+				Expect(instances).To(BeEquivalentTo(3))
+			})
+
+			It("responds with 500 INTERNAL SERVER ERROR", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+
 	Describe("Delete", func() {
 		var req *http.Request
 
@@ -663,6 +1193,97 @@ var _ = Describe("Desired LRP Handlers", func() {
 					Expect(response[1].ProcessGuid).To(Equal("process-guid-2"))
 				})
 			})
+
+			It("responds with an ETag", func() {
+				handler.GetAll(responseRecorder, newTestRequest(""))
+				Expect(responseRecorder.Header().Get("ETag")).NotTo(BeEmpty())
+			})
+
+			Context("when the If-None-Match header matches the ETag of the collection", func() {
+				It("responds with 304 Not Modified", func() {
+					handler.GetAll(responseRecorder, newTestRequest(""))
+					etag := responseRecorder.Header().Get("ETag")
+
+					request := newTestRequest("")
+					request.Header.Set("If-None-Match", etag)
+					conditionalRecorder := httptest.NewRecorder()
+					handler.GetAll(conditionalRecorder, request)
+
+					Expect(conditionalRecorder.Code).To(Equal(http.StatusNotModified))
+					Expect(conditionalRecorder.Body.Len()).To(BeZero())
+				})
+			})
+
+			Context("when the summary view is requested", func() {
+				It("returns desired lrp summary responses", func() {
+					request, err := http.NewRequest("", "http://example.com?view=summary", nil)
+					Expect(err).NotTo(HaveOccurred())
+
+					handler.GetAll(responseRecorder, request)
+					Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+
+					response := []receptor.DesiredLRPSummaryResponse{}
+					err = json.Unmarshal(responseRecorder.Body.Bytes(), &response)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(response).To(Equal([]receptor.DesiredLRPSummaryResponse{
+						{ProcessGuid: "process-guid-1", Domain: "domain-1"},
+						{ProcessGuid: "process-guid-2", Domain: "domain-2"},
+					}))
+				})
+			})
+
+			Context("when paginating", func() {
+				It("returns the first page sorted by process guid with a link to the next page", func() {
+					request, err := http.NewRequest("", "http://example.com/v1/desired_lrps?limit=1", nil)
+					Expect(err).NotTo(HaveOccurred())
+
+					handler.GetAll(responseRecorder, request)
+					Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+
+					response := []receptor.DesiredLRPResponse{}
+					err = json.Unmarshal(responseRecorder.Body.Bytes(), &response)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(response).To(HaveLen(1))
+					Expect(response[0].ProcessGuid).To(Equal("process-guid-1"))
+
+					pageToken := base64.URLEncoding.EncodeToString([]byte("process-guid-1"))
+					Expect(responseRecorder.Header().Get("Link")).To(Equal(
+						fmt.Sprintf(`</v1/desired_lrps?limit=1&page_token=%s>; rel="next"`, url.QueryEscape(pageToken)),
+					))
+				})
+
+				It("returns the page following the page token without a link on the last page", func() {
+					pageToken := base64.URLEncoding.EncodeToString([]byte("process-guid-1"))
+					request, err := http.NewRequest("", "http://example.com/v1/desired_lrps?limit=1&page_token="+url.QueryEscape(pageToken), nil)
+					Expect(err).NotTo(HaveOccurred())
+
+					handler.GetAll(responseRecorder, request)
+					Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+
+					response := []receptor.DesiredLRPResponse{}
+					err = json.Unmarshal(responseRecorder.Body.Bytes(), &response)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(response).To(HaveLen(1))
+					Expect(response[0].ProcessGuid).To(Equal("process-guid-2"))
+					Expect(responseRecorder.Header().Get("Link")).To(BeEmpty())
+				})
+
+				Context("when the page token is invalid", func() {
+					It("responds with a 400 Bad Request", func() {
+						request, err := http.NewRequest("", "http://example.com/v1/desired_lrps?page_token=%25%25%25", nil)
+						Expect(err).NotTo(HaveOccurred())
+
+						handler.GetAll(responseRecorder, request)
+						Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+						Expect(fakeBBS.DesiredLRPsCallCount()).To(Equal(0))
+
+						var receptorError receptor.Error
+						err = json.Unmarshal(responseRecorder.Body.Bytes(), &receptorError)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(receptorError.Type).To(Equal(receptor.InvalidRequest))
+					})
+				})
+			})
 		})
 
 		Context("when the BBS returns no lrps", func() {
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// writeCacheableJSONResponse writes jsonObj along with an ETag, or responds
+// with 304 Not Modified if the request's If-None-Match header already names
+// that ETag. Unless the handler has set an ETag, such as one derived from a
+// ModificationTag, it is a digest of the encoded response.
+func writeCacheableJSONResponse(w http.ResponseWriter, req *http.Request, jsonObj interface{}) {
+	jsonBytes, err := json.Marshal(jsonObj)
+	if err != nil {
+		panic("Unable to encode JSON: " + err.Error())
+	}
+
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		etag = fmt.Sprintf(`"%x"`, sha1.Sum(jsonBytes))
+		w.Header().Set("ETag", etag)
+	}
+
+	if ifNoneMatch := req.Header.Get("If-None-Match"); ifNoneMatch != "" && ifNoneMatchSatisfied(ifNoneMatch, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	writeJSONBytes(w, http.StatusOK, jsonBytes)
+}
+
+// ifMatchSatisfied reports whether etag satisfies an If-Match header, which
+// holds either "*" or a comma separated list of entity tags. If-Match uses the
+// strong comparison, so a weak entity tag never satisfies it.
+func ifMatchSatisfied(header, etag string) bool {
+	return etagListContains(header, etag, false)
+}
+
+// ifNoneMatchSatisfied reports whether etag is named by an If-None-Match
+// header. If-None-Match uses the weak comparison, which ignores the W/ prefix.
+func ifNoneMatchSatisfied(header, etag string) bool {
+	return etagListContains(header, etag, true)
+}
+
+func etagListContains(header, etag string, weak bool) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if weak {
+			candidate = strings.TrimPrefix(candidate, "W/")
+		}
+
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -5,65 +5,73 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
-	"github.com/cloudfoundry-incubator/bbs"
-	"github.com/cloudfoundry-incubator/bbs/events"
-	"github.com/cloudfoundry-incubator/bbs/models"
 	"github.com/cloudfoundry-incubator/receptor"
-	"github.com/cloudfoundry-incubator/receptor/serialization"
+	"github.com/cloudfoundry-incubator/receptor/event"
 	"github.com/pivotal-golang/lager"
 	"github.com/vito/go-sse/sse"
 )
 
 type EventStreamHandler struct {
-	bbs    bbs.Client
-	logger lager.Logger
+	hub               event.Hub
+	heartbeatInterval time.Duration
+	logger            lager.Logger
 }
 
-func NewEventStreamHandler(bbs bbs.Client, logger lager.Logger) *EventStreamHandler {
+func NewEventStreamHandler(hub event.Hub, heartbeatInterval time.Duration, logger lager.Logger) *EventStreamHandler {
 	return &EventStreamHandler{
-		bbs:    bbs,
-		logger: logger,
+		hub:               hub,
+		heartbeatInterval: heartbeatInterval,
+		logger:            logger,
 	}
 }
 
 func (h *EventStreamHandler) EventStream(w http.ResponseWriter, req *http.Request) {
 	logger := h.logger.Session("event-stream-handler")
 
-	closeNotifier := w.(http.CloseNotifier).CloseNotify()
-	sourceChan := make(chan events.EventSource)
-
-	flusher := w.(http.Flusher)
+	filter, err := eventFilterFromRequest(req)
+	if err != nil {
+		logger.Error("invalid-event-filter", err)
+		writeBadRequestResponse(w, receptor.InvalidRequest, err)
+		return
+	}
 
-	go func() {
-		source, err := h.bbs.SubscribeToEvents()
-		if err != nil {
-			logger.Error("failed-to-subscribe-to-events", err)
-			close(sourceChan)
+	var source event.Source
+	if lastEventID := req.Header.Get("Last-Event-ID"); lastEventID != "" {
+		epoch, id, parseErr := parseEventID(lastEventID)
+		if parseErr != nil {
+			logger.Error("invalid-last-event-id", parseErr)
+			writeBadRequestResponse(w, receptor.InvalidRequest, fmt.Errorf("invalid Last-Event-ID: %s", lastEventID))
 			return
 		}
-		sourceChan <- source
-	}()
 
-	var source events.EventSource
-
-	select {
-	case source = <-sourceChan:
-		if source == nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			return
+		if epoch == h.hub.Epoch() {
+			source, err = h.hub.SubscribeSince(id)
+		} else {
+			logger.Info("last-event-id-from-another-epoch", lager.Data{"last-event-id": lastEventID})
+			source, err = h.hub.SubscribeWithResync()
 		}
-	case <-closeNotifier:
+	} else {
+		source, err = h.hub.Subscribe()
+	}
+	if err != nil {
+		logger.Error("failed-to-subscribe-to-events", err)
+		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	defer source.Close()
 
+	closeNotifier := w.(http.CloseNotifier).CloseNotify()
 	go func() {
 		<-closeNotifier
 		source.Close()
 	}()
 
+	flusher := w.(http.Flusher)
+
 	w.Header().Add("Content-Type", "text/event-stream; charset=utf-8")
 	w.Header().Add("Cache-Control", "no-cache, no-store, must-revalidate")
 	w.Header().Add("Connection", "keep-alive")
@@ -72,64 +80,201 @@ func (h *EventStreamHandler) EventStream(w http.ResponseWriter, req *http.Reques
 
 	flusher.Flush()
 
-	eventID := 0
-	for {
-		bbsEvent, err := source.Next()
-		if err != nil {
-			logger.Error("failed-to-get-next-event", err)
-			return
+	messages := make(chan event.Message)
+	errs := make(chan error, 1)
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		for {
+			message, err := source.Next()
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			select {
+			case messages <- message:
+			case <-done:
+				return
+			}
 		}
+	}()
+
+	// heartbeats carry the ID of the last event received, including events
+	// dropped by the filter, so that a client with a narrow filter resumes
+	// from the latest ID rather than one which has fallen out of the replay
+	// buffer
+	lastEventID := req.Header.Get("Last-Event-ID")
+
+	var heartbeats <-chan time.Time
+	if h.heartbeatInterval > 0 {
+		ticker := time.NewTicker(h.heartbeatInterval)
+		defer ticker.Stop()
+		heartbeats = ticker.C
 
-		event, err := NewEventFromBBS(bbsEvent)
+		err := writeEvent(w, lastEventID, receptor.NewHeartbeatEvent(h.heartbeatInterval))
 		if err != nil {
-			logger.Error("failed-to-marshal-event", err)
+			logger.Error("failed-to-write-heartbeat", err)
 			return
 		}
-		payload, err := json.Marshal(event)
-		if err != nil {
-			logger.Error("failed-to-marshal-event", err)
+	}
+
+	for {
+		select {
+		case message := <-messages:
+			lastEventID = formatEventID(h.hub.Epoch(), message.ID)
+			if !eventMatchesFilter(message.Event, filter) {
+				continue
+			}
+
+			err := writeEvent(w, lastEventID, message.Event)
+			if err != nil {
+				logger.Error("failed-to-write-event", err)
+				return
+			}
+
+		case <-heartbeats:
+			err := writeEvent(w, lastEventID, receptor.NewHeartbeatEvent(h.heartbeatInterval))
+			if err != nil {
+				logger.Error("failed-to-write-heartbeat", err)
+				return
+			}
+
+		case err := <-errs:
+			logger.Error("failed-to-get-next-event", err)
 			return
 		}
+	}
+}
 
-		err = sse.Event{
-			ID:   strconv.Itoa(eventID),
-			Name: string(event.EventType()),
-			Data: payload,
-		}.Write(w)
-		if err != nil {
-			break
+// Event IDs are sent as <epoch>-<id>, so that an ID assigned by another hub,
+// e.g. before the receptor restarted, is not taken for one of this hub's.
+func formatEventID(epoch string, id uint64) string {
+	return epoch + "-" + strconv.FormatUint(id, 10)
+}
+
+// parseEventID splits an event ID into its epoch and hub ID. An ID without an
+// epoch, as sent by older receptors, has an empty epoch.
+func parseEventID(eventID string) (string, uint64, error) {
+	epoch := ""
+	if i := strings.LastIndex(eventID, "-"); i >= 0 {
+		epoch, eventID = eventID[:i], eventID[i+1:]
+	}
+
+	id, err := strconv.ParseUint(eventID, 10, 64)
+	return epoch, id, err
+}
+
+func writeEvent(w http.ResponseWriter, id string, event receptor.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	err = sse.Event{
+		ID:   id,
+		Name: string(event.EventType()),
+		Data: payload,
+	}.Write(w)
+	if err != nil {
+		return err
+	}
+
+	w.(http.Flusher).Flush()
+	return nil
+}
+
+func eventFilterFromRequest(req *http.Request) (receptor.EventFilter, error) {
+	filter := receptor.EventFilter{
+		Domain:      req.FormValue("domain"),
+		ProcessGuid: req.FormValue("process_guid"),
+	}
+
+	for _, types := range req.Form["types"] {
+		for _, eventType := range strings.Split(types, ",") {
+			if eventType == "" {
+				continue
+			}
+
+			if !isKnownEventType(receptor.EventType(eventType)) {
+				return receptor.EventFilter{}, fmt.Errorf("unknown event type: %s", eventType)
+			}
+			filter.Types = append(filter.Types, receptor.EventType(eventType))
 		}
+	}
+
+	return filter, nil
+}
+
+func isKnownEventType(eventType receptor.EventType) bool {
+	switch eventType {
+	case receptor.EventTypeDesiredLRPCreated,
+		receptor.EventTypeDesiredLRPChanged,
+		receptor.EventTypeDesiredLRPRemoved,
+		receptor.EventTypeActualLRPCreated,
+		receptor.EventTypeActualLRPChanged,
+		receptor.EventTypeActualLRPRemoved,
+		receptor.EventTypeTaskCreated,
+		receptor.EventTypeTaskChanged,
+		receptor.EventTypeTaskRemoved,
+		receptor.EventTypeResyncRequired:
+		return true
+	}
+	return false
+}
 
-		flusher.Flush()
+func eventMatchesFilter(event receptor.Event, filter receptor.EventFilter) bool {
+	if event.EventType() == receptor.EventTypeResyncRequired {
+		return true
+	}
+
+	if len(filter.Types) > 0 {
+		matched := false
+		for _, eventType := range filter.Types {
+			if event.EventType() == eventType {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
 
-		eventID++
+	domain, processGuid := eventDomainAndProcessGuid(event)
+	if filter.Domain != "" && filter.Domain != domain {
+		return false
+	}
+	if filter.ProcessGuid != "" && filter.ProcessGuid != processGuid {
+		return false
 	}
+
+	return true
 }
 
-func NewEventFromBBS(bbsEvent models.Event) (receptor.Event, error) {
-	switch bbsEvent := bbsEvent.(type) {
-	case *models.ActualLRPCreatedEvent:
-		actualLRP, evacuating := bbsEvent.ActualLrpGroup.Resolve()
-		return receptor.NewActualLRPCreatedEvent(serialization.ActualLRPProtoToResponse(actualLRP, evacuating)), nil
-	case *models.ActualLRPChangedEvent:
-		before, evacuating := bbsEvent.Before.Resolve()
-		after, evacuating := bbsEvent.After.Resolve()
-		return receptor.NewActualLRPChangedEvent(
-			serialization.ActualLRPProtoToResponse(before, evacuating),
-			serialization.ActualLRPProtoToResponse(after, evacuating),
-		), nil
-	case *models.ActualLRPRemovedEvent:
-		actualLRP, evacuating := bbsEvent.ActualLrpGroup.Resolve()
-		return receptor.NewActualLRPRemovedEvent(serialization.ActualLRPProtoToResponse(actualLRP, evacuating)), nil
-	case *models.DesiredLRPCreatedEvent:
-		return receptor.NewDesiredLRPCreatedEvent(serialization.DesiredLRPProtoToResponse(bbsEvent.DesiredLrp)), nil
-	case *models.DesiredLRPChangedEvent:
-		return receptor.NewDesiredLRPChangedEvent(
-			serialization.DesiredLRPProtoToResponse(bbsEvent.Before),
-			serialization.DesiredLRPProtoToResponse(bbsEvent.After),
-		), nil
-	case *models.DesiredLRPRemovedEvent:
-		return receptor.NewDesiredLRPRemovedEvent(serialization.DesiredLRPProtoToResponse(bbsEvent.DesiredLrp)), nil
-	}
-	return nil, fmt.Errorf("unknown event type: %#v", bbsEvent)
+// eventDomainAndProcessGuid returns the domain and process guid an event
+// refers to. Task events have no process guid.
+func eventDomainAndProcessGuid(event receptor.Event) (string, string) {
+	switch event := event.(type) {
+	case receptor.DesiredLRPCreatedEvent:
+		return event.DesiredLRPResponse.Domain, event.DesiredLRPResponse.ProcessGuid
+	case receptor.DesiredLRPChangedEvent:
+		return event.After.Domain, event.After.ProcessGuid
+	case receptor.DesiredLRPRemovedEvent:
+		return event.DesiredLRPResponse.Domain, event.DesiredLRPResponse.ProcessGuid
+	case receptor.ActualLRPCreatedEvent:
+		return event.ActualLRPResponse.Domain, event.ActualLRPResponse.ProcessGuid
+	case receptor.ActualLRPChangedEvent:
+		return event.After.Domain, event.After.ProcessGuid
+	case receptor.ActualLRPRemovedEvent:
+		return event.ActualLRPResponse.Domain, event.ActualLRPResponse.ProcessGuid
+	case receptor.TaskCreatedEvent:
+		return event.TaskResponse.Domain, ""
+	case receptor.TaskChangedEvent:
+		return event.After.Domain, ""
+	case receptor.TaskRemovedEvent:
+		return event.TaskResponse.Domain, ""
+	}
+	return "", ""
 }
@@ -6,15 +6,13 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"time"
 
-	"github.com/cloudfoundry-incubator/bbs/events"
-	"github.com/cloudfoundry-incubator/bbs/events/eventfakes"
-	"github.com/cloudfoundry-incubator/bbs/fake_bbs"
-	"github.com/cloudfoundry-incubator/bbs/models"
 	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/event"
+	"github.com/cloudfoundry-incubator/receptor/event/eventfakes"
 	"github.com/cloudfoundry-incubator/receptor/handlers"
-	"github.com/cloudfoundry-incubator/receptor/serialization"
 	"github.com/pivotal-golang/lager"
 	"github.com/vito/go-sse/sse"
 
@@ -24,8 +22,9 @@ import (
 
 var _ = Describe("Event Stream Handlers", func() {
 	var (
-		logger  lager.Logger
-		fakeBBS *fake_bbs.FakeClient
+		logger            lager.Logger
+		hub               event.Hub
+		heartbeatInterval time.Duration
 
 		handler *handlers.EventStreamHandler
 
@@ -33,14 +32,18 @@ var _ = Describe("Event Stream Handlers", func() {
 	)
 
 	BeforeEach(func() {
-		fakeBBS = new(fake_bbs.FakeClient)
+		hub = event.NewHub(2)
+		heartbeatInterval = 0
 		logger = lager.NewLogger("test")
 		logger.RegisterSink(lager.NewWriterSink(GinkgoWriter, lager.DEBUG))
+	})
 
-		handler = handlers.NewEventStreamHandler(fakeBBS, logger)
+	JustBeforeEach(func() {
+		handler = handlers.NewEventStreamHandler(hub, heartbeatInterval, logger)
 	})
 
 	AfterEach(func(done Done) {
+		hub.Close()
 		if server != nil {
 			server.Close()
 		}
@@ -50,11 +53,15 @@ var _ = Describe("Event Stream Handlers", func() {
 	Describe("EventStream", func() {
 		var (
 			request         *http.Request
+			query           url.Values
+			lastEventID     string
 			responseChan    chan *http.Response
 			eventStreamDone chan struct{}
 		)
 
 		BeforeEach(func() {
+			query = url.Values{}
+			lastEventID = ""
 			responseChan = make(chan *http.Response)
 			eventStreamDone = make(chan struct{})
 			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -65,8 +72,11 @@ var _ = Describe("Event Stream Handlers", func() {
 
 		JustBeforeEach(func() {
 			var err error
-			request, err = http.NewRequest("GET", server.URL, nil)
+			request, err = http.NewRequest("GET", server.URL+"?"+query.Encode(), nil)
 			Expect(err).NotTo(HaveOccurred())
+			if lastEventID != "" {
+				request.Header.Set("Last-Event-ID", lastEventID)
+			}
 			go func() {
 				defer GinkgoRecover()
 				response, _ := http.DefaultClient.Do(request)
@@ -76,7 +86,9 @@ var _ = Describe("Event Stream Handlers", func() {
 
 		Context("when failing to subscribe to the event stream", func() {
 			BeforeEach(func() {
-				fakeBBS.SubscribeToEventsReturns(nil, models.ErrUnknownError)
+				fakeHub := new(eventfakes.FakeHub)
+				fakeHub.SubscribeReturns(nil, errors.New("boom"))
+				hub = fakeHub
 			})
 
 			It("returns an internal server error", func() {
@@ -86,116 +98,345 @@ var _ = Describe("Event Stream Handlers", func() {
 			})
 		})
 
-		Context("when connection is closed before subscription is complete", func() {
-			var subscribe chan bool
+		Context("when the types filter contains an unknown event type", func() {
+			var fakeHub *eventfakes.FakeHub
 
 			BeforeEach(func() {
-				subscribe = make(chan bool)
-				fakeBBS.SubscribeToEventsStub = func() (events.EventSource, error) {
-					<-subscribe
-					return nil, errors.New("broken")
-				}
+				fakeHub = new(eventfakes.FakeHub)
+				hub = fakeHub
+				query.Set("types", "desired_lrp_created,bogus_event")
 			})
 
-			It("returns an internal server error", func() {
-				Eventually(fakeBBS.SubscribeToEventsCallCount).Should(Equal(1))
-				http.DefaultTransport.(*http.Transport).CancelRequest(request)
+			It("returns a bad request error", func() {
+				response := &http.Response{}
+				Eventually(responseChan).Should(Receive(&response))
+				Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+
+				var receptorError receptor.Error
+				err := json.NewDecoder(response.Body).Decode(&receptorError)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(receptorError.Type).To(Equal(receptor.InvalidRequest))
+			})
 
-				// should not timeout
-				server.Close()
+			It("does not subscribe to the hub", func() {
 				Eventually(responseChan).Should(Receive())
+				Expect(fakeHub.SubscribeCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the Last-Event-ID header is not a number", func() {
+			BeforeEach(func() {
+				lastEventID = "not-a-number"
+			})
+
+			It("returns a bad request error", func() {
+				response := &http.Response{}
+				Eventually(responseChan).Should(Receive(&response))
+				Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
 			})
 		})
 
 		Context("when successfully subscribing to the event stream", func() {
-			var eventSource *eventfakes.FakeEventSource
-			var eventChannel chan models.Event
+			var desiredLRP receptor.DesiredLRPResponse
 
 			BeforeEach(func() {
-				eventChannel = make(chan models.Event, 2)
-				eventSource = new(eventfakes.FakeEventSource)
-				eventSource.NextStub = func() (models.Event, error) {
-					select {
-					case event := <-eventChannel:
-						return event, nil
-					case <-time.After(time.Second):
-					}
-					return nil, errors.New("timeout waiting for events")
+				desiredLRP = receptor.DesiredLRPResponse{
+					ProcessGuid: "some-guid",
+					Domain:      "some-domain",
+					RootFS:      "some-rootfs",
 				}
-				fakeBBS.SubscribeToEventsReturns(eventSource, nil)
 			})
 
-			It("emits events from the stream to the connection", func(done Done) {
+			It("emits events from the hub to the connection", func(done Done) {
 				response := &http.Response{}
 				Eventually(responseChan).Should(Receive(&response))
 				reader := sse.NewReadCloser(response.Body)
 
-				desiredLRP := &models.DesiredLRP{
-					ProcessGuid: "some-guid",
-					Domain:      "some-domain",
-					RootFs:      "some-rootfs",
-					Action: models.WrapAction(&models.RunAction{
-						Path: "true",
-						User: "user",
-					}),
-				}
-				eventChannel <- models.NewDesiredLRPCreatedEvent(desiredLRP)
+				desiredLRPEvent := receptor.NewDesiredLRPCreatedEvent(desiredLRP)
+				hub.Emit(desiredLRPEvent)
 
-				data, err := json.Marshal(receptor.NewDesiredLRPCreatedEvent(serialization.DesiredLRPProtoToResponse(desiredLRP)))
+				data, err := json.Marshal(desiredLRPEvent)
 				Expect(err).NotTo(HaveOccurred())
 
 				event, err := reader.Next()
 				Expect(err).NotTo(HaveOccurred())
-				Expect(event.ID).To(Equal("0"))
+				Expect(event.ID).To(Equal(hub.Epoch() + "-1"))
 				Expect(event.Name).To(Equal(string(receptor.EventTypeDesiredLRPCreated)))
 				Expect(event.Data).To(MatchJSON(data))
 
-				actualLRP := models.NewUnclaimedActualLRP(models.NewActualLRPKey("some-guid", 3, "some-domain"), 0)
-				actualLRPGroup := &models.ActualLRPGroup{Instance: actualLRP}
-				eventChannel <- models.NewActualLRPCreatedEvent(actualLRPGroup)
+				actualLRPEvent := receptor.NewActualLRPCreatedEvent(receptor.ActualLRPResponse{
+					ProcessGuid: "some-guid",
+					Index:       3,
+					Domain:      "some-domain",
+					State:       receptor.ActualLRPStateUnclaimed,
+				})
+				hub.Emit(actualLRPEvent)
 
-				data, err = json.Marshal(receptor.NewActualLRPCreatedEvent(serialization.ActualLRPProtoToResponse(actualLRP, false)))
+				data, err = json.Marshal(actualLRPEvent)
 				Expect(err).NotTo(HaveOccurred())
 
 				event, err = reader.Next()
 				Expect(err).NotTo(HaveOccurred())
-				Expect(event.ID).To(Equal("1"))
+				Expect(event.ID).To(Equal(hub.Epoch() + "-2"))
 				Expect(event.Name).To(Equal(string(receptor.EventTypeActualLRPCreated)))
 				Expect(event.Data).To(MatchJSON(data))
-				eventChannel <- eventfakes.FakeEvent{"B"}
 
 				close(done)
 			})
 
-			It("returns Content-Type as text/event-stream", func() {
+			It("emits task events from the hub to the connection", func(done Done) {
 				response := &http.Response{}
 				Eventually(responseChan).Should(Receive(&response))
-				Expect(response.Header.Get("Content-Type")).To(Equal("text/event-stream; charset=utf-8"))
-				Expect(response.Header.Get("Cache-Control")).To(Equal("no-cache, no-store, must-revalidate"))
-				Expect(response.Header.Get("Connection")).To(Equal("keep-alive"))
+				reader := sse.NewReadCloser(response.Body)
+
+				task := receptor.TaskResponse{
+					TaskGuid: "some-task-guid",
+					Domain:   "some-domain",
+					State:    receptor.TaskStatePending,
+				}
+				runningTask := task
+				runningTask.State = receptor.TaskStateRunning
+				runningTask.CellID = "some-cell"
+
+				taskEvent := receptor.NewTaskChangedEvent(task, runningTask)
+				hub.Emit(taskEvent)
+
+				data, err := json.Marshal(taskEvent)
+				Expect(err).NotTo(HaveOccurred())
+
+				event, err := reader.Next()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(event.Name).To(Equal(string(receptor.EventTypeTaskChanged)))
+				Expect(event.Data).To(MatchJSON(data))
+
+				close(done)
+			})
+
+			Context("when filtering the stream", func() {
+				var (
+					otherDomainLRP receptor.DesiredLRPResponse
+					otherGuidLRP   receptor.DesiredLRPResponse
+				)
+
+				BeforeEach(func() {
+					otherDomainLRP = receptor.DesiredLRPResponse{ProcessGuid: "some-guid", Domain: "other-domain"}
+					otherGuidLRP = receptor.DesiredLRPResponse{ProcessGuid: "other-guid", Domain: "some-domain"}
+				})
+
+				Context("by domain and process guid", func() {
+					BeforeEach(func() {
+						query.Set("domain", "some-domain")
+						query.Set("process_guid", "some-guid")
+					})
+
+					It("only emits events for the matching domain and process guid", func() {
+						response := &http.Response{}
+						Eventually(responseChan).Should(Receive(&response))
+						reader := sse.NewReadCloser(response.Body)
+
+						hub.Emit(receptor.NewDesiredLRPCreatedEvent(otherDomainLRP))
+						hub.Emit(receptor.NewDesiredLRPCreatedEvent(otherGuidLRP))
+						hub.Emit(receptor.NewDesiredLRPCreatedEvent(desiredLRP))
+
+						data, err := json.Marshal(receptor.NewDesiredLRPCreatedEvent(desiredLRP))
+						Expect(err).NotTo(HaveOccurred())
+
+						event, err := reader.Next()
+						Expect(err).NotTo(HaveOccurred())
+						Expect(event.ID).To(Equal(hub.Epoch() + "-3"))
+						Expect(event.Data).To(MatchJSON(data))
+					})
+
+					It("always emits resync required events", func() {
+						response := &http.Response{}
+						Eventually(responseChan).Should(Receive(&response))
+						reader := sse.NewReadCloser(response.Body)
+
+						hub.Emit(receptor.NewResyncRequiredEvent())
+
+						event, err := reader.Next()
+						Expect(err).NotTo(HaveOccurred())
+						Expect(event.Name).To(Equal(string(receptor.EventTypeResyncRequired)))
+					})
+				})
+
+				Context("by event type", func() {
+					BeforeEach(func() {
+						query.Set("types", string(receptor.EventTypeDesiredLRPRemoved))
+					})
+
+					It("only emits events of the requested types", func() {
+						response := &http.Response{}
+						Eventually(responseChan).Should(Receive(&response))
+						reader := sse.NewReadCloser(response.Body)
+
+						hub.Emit(receptor.NewDesiredLRPCreatedEvent(desiredLRP))
+						hub.Emit(receptor.NewDesiredLRPRemovedEvent(desiredLRP))
+
+						event, err := reader.Next()
+						Expect(err).NotTo(HaveOccurred())
+						Expect(event.Name).To(Equal(string(receptor.EventTypeDesiredLRPRemoved)))
+					})
+				})
+			})
+
+			Context("when resuming with a Last-Event-ID", func() {
+				BeforeEach(func() {
+					hub.Emit(receptor.NewDesiredLRPCreatedEvent(desiredLRP))
+					hub.Emit(receptor.NewDesiredLRPChangedEvent(desiredLRP, desiredLRP))
+					hub.Emit(receptor.NewDesiredLRPRemovedEvent(desiredLRP))
+				})
+
+				Context("when the missed events are still retained", func() {
+					BeforeEach(func() {
+						lastEventID = hub.Epoch() + "-1"
+					})
+
+					It("replays the missed events", func() {
+						response := &http.Response{}
+						Eventually(responseChan).Should(Receive(&response))
+						reader := sse.NewReadCloser(response.Body)
+
+						event, err := reader.Next()
+						Expect(err).NotTo(HaveOccurred())
+						Expect(event.ID).To(Equal(hub.Epoch() + "-2"))
+						Expect(event.Name).To(Equal(string(receptor.EventTypeDesiredLRPChanged)))
+
+						event, err = reader.Next()
+						Expect(err).NotTo(HaveOccurred())
+						Expect(event.ID).To(Equal(hub.Epoch() + "-3"))
+						Expect(event.Name).To(Equal(string(receptor.EventTypeDesiredLRPRemoved)))
+					})
+				})
+
+				Context("when the missed events are no longer retained", func() {
+					BeforeEach(func() {
+						lastEventID = hub.Epoch() + "-0"
+					})
+
+					It("emits a resync required event", func() {
+						response := &http.Response{}
+						Eventually(responseChan).Should(Receive(&response))
+						reader := sse.NewReadCloser(response.Body)
+
+						event, err := reader.Next()
+						Expect(err).NotTo(HaveOccurred())
+						Expect(event.ID).To(Equal(hub.Epoch() + "-3"))
+						Expect(event.Name).To(Equal(string(receptor.EventTypeResyncRequired)))
+					})
+				})
+
+				Context("when the Last-Event-ID is from another epoch", func() {
+					BeforeEach(func() {
+						lastEventID = "another-epoch-2"
+					})
+
+					It("emits a resync required event with an ID from this epoch", func() {
+						response := &http.Response{}
+						Eventually(responseChan).Should(Receive(&response))
+						reader := sse.NewReadCloser(response.Body)
+
+						event, err := reader.Next()
+						Expect(err).NotTo(HaveOccurred())
+						Expect(event.ID).To(Equal(hub.Epoch() + "-3"))
+						Expect(event.Name).To(Equal(string(receptor.EventTypeResyncRequired)))
+					})
+				})
+
+				Context("when the Last-Event-ID has no epoch", func() {
+					BeforeEach(func() {
+						lastEventID = "2"
+					})
+
+					It("emits a resync required event", func() {
+						response := &http.Response{}
+						Eventually(responseChan).Should(Receive(&response))
+						reader := sse.NewReadCloser(response.Body)
+
+						event, err := reader.Next()
+						Expect(err).NotTo(HaveOccurred())
+						Expect(event.Name).To(Equal(string(receptor.EventTypeResyncRequired)))
+					})
+				})
 			})
 
-			Context("when the source provides an unmarshalable event", func() {
-				It("closes the event stream to the client", func(done Done) {
+			Context("when heartbeats are enabled", func() {
+				BeforeEach(func() {
+					heartbeatInterval = 50 * time.Millisecond
+				})
+
+				It("sends heartbeats announcing the interval", func() {
 					response := &http.Response{}
 					Eventually(responseChan).Should(Receive(&response))
-					eventChannel <- eventfakes.UnmarshalableEvent{Fn: func() {}}
+					reader := sse.NewReadCloser(response.Body)
 
+					for i := 0; i < 2; i++ {
+						event, err := reader.Next()
+						Expect(err).NotTo(HaveOccurred())
+						Expect(event.Name).To(Equal(string(receptor.EventTypeHeartbeat)))
+						Expect(event.Data).To(MatchJSON(`{"interval_ms":50}`))
+					}
+				})
+
+				It("repeats the ID of the last event sent", func() {
+					response := &http.Response{}
+					Eventually(responseChan).Should(Receive(&response))
 					reader := sse.NewReadCloser(response.Body)
-					_, err := reader.Next()
-					Expect(err).To(Equal(io.EOF))
-					close(done)
+
+					hub.Emit(receptor.NewDesiredLRPCreatedEvent(desiredLRP))
+
+					Eventually(func() string {
+						event, err := reader.Next()
+						Expect(err).NotTo(HaveOccurred())
+						return event.Name
+					}).Should(Equal(string(receptor.EventTypeDesiredLRPCreated)))
+
+					event, err := reader.Next()
+					Expect(err).NotTo(HaveOccurred())
+					Expect(event.Name).To(Equal(string(receptor.EventTypeHeartbeat)))
+					Expect(event.ID).To(Equal(hub.Epoch() + "-1"))
 				})
-			})
 
-			Context("when the event source returns an error", func() {
-				BeforeEach(func() {
-					eventSource.NextReturns(nil, models.ErrUnknownError)
+				Context("when the stream is filtered", func() {
+					BeforeEach(func() {
+						query.Set("types", string(receptor.EventTypeDesiredLRPRemoved))
+					})
+
+					It("carries the ID of the last event dropped by the filter", func() {
+						response := &http.Response{}
+						Eventually(responseChan).Should(Receive(&response))
+						reader := sse.NewReadCloser(response.Body)
+
+						event, err := reader.Next()
+						Expect(err).NotTo(HaveOccurred())
+						Expect(event.Name).To(Equal(string(receptor.EventTypeHeartbeat)))
+
+						hub.Emit(receptor.NewDesiredLRPCreatedEvent(desiredLRP))
+
+						Eventually(func() string {
+							event, err := reader.Next()
+							Expect(err).NotTo(HaveOccurred())
+							Expect(event.Name).To(Equal(string(receptor.EventTypeHeartbeat)))
+							return event.ID
+						}).Should(Equal(hub.Epoch() + "-1"))
+					})
 				})
+			})
 
+			It("returns Content-Type as text/event-stream", func() {
+				response := &http.Response{}
+				Eventually(responseChan).Should(Receive(&response))
+				Expect(response.Header.Get("Content-Type")).To(Equal("text/event-stream; charset=utf-8"))
+				Expect(response.Header.Get("Cache-Control")).To(Equal("no-cache, no-store, must-revalidate"))
+				Expect(response.Header.Get("Connection")).To(Equal("keep-alive"))
+			})
+
+			Context("when the hub is closed", func() {
 				It("closes the client event stream", func() {
 					response := &http.Response{}
 					Eventually(responseChan).Should(Receive(&response))
+					Expect(hub.Close()).To(Succeed())
+
 					reader := sse.NewReadCloser(response.Body)
 					_, err := reader.Next()
 					Expect(err).To(Equal(io.EOF))
@@ -2,21 +2,33 @@ package handlers
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/cloudfoundry-incubator/bbs"
 	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/autoscaler"
+	"github.com/cloudfoundry-incubator/receptor/callback"
+	"github.com/cloudfoundry-incubator/receptor/crashhistory"
+	"github.com/cloudfoundry-incubator/receptor/event"
+	"github.com/cloudfoundry-incubator/receptor/rollingupdate"
+	"github.com/cloudfoundry-incubator/receptor/store"
+	"github.com/pivotal-golang/clock"
 	"github.com/pivotal-golang/lager"
 	"github.com/tedsuo/rata"
 )
 
-func New(bbs bbs.Client, serviceClient bbs.ServiceClient, logger lager.Logger, username, password string, corsEnabled bool, artifactLocator ArtifactLocator, versionFilesLocator VersionFilesLocator) http.Handler {
-	taskHandler := NewTaskHandler(bbs, logger)
-	desiredLRPHandler := NewDesiredLRPHandler(bbs, logger)
-	actualLRPHandler := NewActualLRPHandler(bbs, logger)
+func New(bbs bbs.Client, serviceClient bbs.ServiceClient, hub event.Hub, heartbeatInterval time.Duration, callbackStatuses callback.StatusStore, rollingUpdates rollingupdate.Coordinator, stoppedLRPs store.StoppedLRPStore, autoscalingPolicies autoscaler.PolicyStore, crashHistory crashhistory.Store, logger lager.Logger, username, password string, corsEnabled bool, artifactLocator ArtifactLocator, versionFilesLocator VersionFilesLocator) http.Handler {
+	taskHandler := NewTaskHandler(bbs, hub, logger)
+	callbackDeliveryHandler := NewCallbackDeliveryHandler(callbackStatuses, logger)
+	desiredLRPHandler := NewDesiredLRPHandler(bbs, stoppedLRPs, clock.NewClock(), logger)
+	rollingUpdateHandler := NewRollingUpdateHandler(rollingUpdates, logger)
+	autoscalingPolicyHandler := NewAutoscalingPolicyHandler(autoscalingPolicies, logger)
+	actualLRPHandler := NewActualLRPHandler(bbs, serviceClient, logger)
+	crashHistoryHandler := NewCrashHistoryHandler(crashHistory, logger)
 	cellHandler := NewCellHandler(serviceClient, logger)
 	domainHandler := NewDomainHandler(bbs, logger)
 	syncHandler := NewSyncHandler(artifactLocator, logger)
-	eventStreamHandler := NewEventStreamHandler(bbs, logger)
+	eventStreamHandler := NewEventStreamHandler(hub, heartbeatInterval, logger)
 	authCookieHandler := NewAuthCookieHandler(logger)
 	versionHandler := NewVersionHandler(versionFilesLocator)
 
@@ -29,11 +41,18 @@ func New(bbs bbs.Client, serviceClient bbs.ServiceClient, logger lager.Logger, u
 
 	actions := rata.Handlers{
 		// Tasks
-		receptor.CreateTaskRoute: auth(taskHandler.Create),
-		receptor.TasksRoute:      auth(taskHandler.GetAll),
-		receptor.GetTaskRoute:    auth(taskHandler.GetByGuid),
-		receptor.DeleteTaskRoute: auth(taskHandler.Delete),
-		receptor.CancelTaskRoute: auth(taskHandler.Cancel),
+		receptor.CreateTaskRoute:      auth(taskHandler.Create),
+		receptor.CreateTasksBulkRoute: auth(taskHandler.CreateBulk),
+		receptor.TasksRoute:           auth(taskHandler.GetAll),
+		receptor.GetTaskRoute:         auth(taskHandler.GetByGuid),
+		receptor.GetTaskResultRoute:   auth(taskHandler.GetResult),
+		receptor.DeleteTaskRoute:      auth(taskHandler.Delete),
+		receptor.DeleteTasksRoute:     auth(taskHandler.DeleteAll),
+		receptor.CancelTaskRoute:      auth(taskHandler.Cancel),
+		receptor.RetryTaskRoute:       auth(taskHandler.Retry),
+
+		// Completion Callbacks
+		receptor.TaskCallbackDeliveryRoute: auth(callbackDeliveryHandler.GetByTaskGuid),
 
 		// DesiredLRPs
 		receptor.CreateDesiredLRPRoute: auth(desiredLRPHandler.Create),
@@ -41,12 +60,34 @@ func New(bbs bbs.Client, serviceClient bbs.ServiceClient, logger lager.Logger, u
 		receptor.UpdateDesiredLRPRoute: auth(desiredLRPHandler.Update),
 		receptor.DeleteDesiredLRPRoute: auth(desiredLRPHandler.Delete),
 		receptor.DesiredLRPsRoute:      auth(desiredLRPHandler.GetAll),
+		receptor.StopDesiredLRPRoute:   auth(desiredLRPHandler.Stop),
+		receptor.StartDesiredLRPRoute:  auth(desiredLRPHandler.Start),
+
+		// DesiredLRPs by Domain
+		receptor.ScaleDesiredLRPsByDomainRoute:   auth(desiredLRPHandler.ScaleAll),
+		receptor.RestartDesiredLRPsByDomainRoute: auth(desiredLRPHandler.RestartAll),
+		receptor.DeleteDesiredLRPsByDomainRoute:  auth(desiredLRPHandler.DeleteAll),
+
+		// Rolling Updates
+		receptor.StartRollingUpdateRoute: auth(rollingUpdateHandler.Start),
+		receptor.GetRollingUpdateRoute:   auth(rollingUpdateHandler.Get),
+		receptor.AbortRollingUpdateRoute: auth(rollingUpdateHandler.Abort),
+
+		// Autoscaling Policies
+		receptor.AutoscalingPoliciesRoute:     auth(autoscalingPolicyHandler.GetAll),
+		receptor.GetAutoscalingPolicyRoute:    auth(autoscalingPolicyHandler.Get),
+		receptor.SetAutoscalingPolicyRoute:    auth(autoscalingPolicyHandler.Set),
+		receptor.DeleteAutoscalingPolicyRoute: auth(autoscalingPolicyHandler.Delete),
 
 		// ActualLRPs
 		receptor.ActualLRPsRoute:                         auth(actualLRPHandler.GetAll),
 		receptor.ActualLRPsByProcessGuidRoute:            auth(actualLRPHandler.GetAllByProcessGuid),
 		receptor.ActualLRPByProcessGuidAndIndexRoute:     auth(actualLRPHandler.GetByProcessGuidAndIndex),
 		receptor.KillActualLRPByProcessGuidAndIndexRoute: auth(actualLRPHandler.KillByProcessGuidAndIndex),
+		receptor.KillActualLRPsByProcessGuidRoute:        auth(actualLRPHandler.KillAllByProcessGuid),
+		receptor.KillActualLRPByInstanceGuidRoute:        auth(actualLRPHandler.KillByInstanceGuid),
+		receptor.ActualLRPCrashesRoute:                   auth(crashHistoryHandler.GetByProcessGuidAndIndex),
+		receptor.CrashingActualLRPsRoute:                 auth(crashHistoryHandler.GetCrashing),
 
 		// Cells
 		receptor.CellsRoute: auth(cellHandler.GetAll),
@@ -28,6 +28,10 @@ func writeJSONResponse(w http.ResponseWriter, statusCode int, jsonObj interface{
 		panic("Unable to encode JSON: " + err.Error())
 	}
 
+	writeJSONBytes(w, statusCode, jsonBytes)
+}
+
+func writeJSONBytes(w http.ResponseWriter, statusCode int, jsonBytes []byte) {
 	w.Header().Set("Content-Length", strconv.Itoa(len(jsonBytes)))
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
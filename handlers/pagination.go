@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+// pagination describes the page requested through the limit and page_token
+// query parameters. Page tokens are the opaque sort key of the last item of
+// the previous page.
+type pagination struct {
+	limit int
+	after string
+}
+
+func paginationFromRequest(req *http.Request) (pagination, error) {
+	var p pagination
+
+	if limit := req.FormValue("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value <= 0 {
+			return pagination{}, errors.New("limit must be a positive integer")
+		}
+		p.limit = value
+	}
+
+	if pageToken := req.FormValue("page_token"); pageToken != "" {
+		after, err := base64.URLEncoding.DecodeString(pageToken)
+		if err != nil {
+			return pagination{}, fmt.Errorf("invalid page_token: %s", pageToken)
+		}
+		p.after = string(after)
+	}
+
+	return p, nil
+}
+
+// page returns the bounds of the requested page within n items whose sort
+// keys, in ascending order, are given by key.
+func (p pagination) page(n int, key func(int) string) (start, end int) {
+	if p.after != "" {
+		start = sort.Search(n, func(i int) bool { return key(i) > p.after })
+	}
+
+	end = n
+	if p.limit > 0 && start+p.limit < n {
+		end = start + p.limit
+	}
+
+	return start, end
+}
+
+// writeNextPageLink points the client at the page following the item with
+// the given sort key, preserving the other query parameters of the request.
+func writeNextPageLink(w http.ResponseWriter, req *http.Request, lastKey string) {
+	query := url.Values{}
+	for key, values := range req.URL.Query() {
+		query[key] = values
+	}
+	query.Set("page_token", base64.URLEncoding.EncodeToString([]byte(lastKey)))
+
+	next := url.URL{Path: req.URL.Path, RawQuery: query.Encode()}
+	w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, next.String()))
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/bbs/models"
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/rollingupdate"
+	"github.com/pivotal-golang/lager"
+)
+
+type RollingUpdateHandler struct {
+	coordinator rollingupdate.Coordinator
+	logger      lager.Logger
+}
+
+func NewRollingUpdateHandler(coordinator rollingupdate.Coordinator, logger lager.Logger) *RollingUpdateHandler {
+	return &RollingUpdateHandler{
+		coordinator: coordinator,
+		logger:      logger.Session("rolling-update-handler"),
+	}
+}
+
+func (h *RollingUpdateHandler) Start(w http.ResponseWriter, req *http.Request) {
+	processGuid := req.FormValue(":process_guid")
+	logger := h.logger.Session("start", lager.Data{
+		"ProcessGuid": processGuid,
+	})
+
+	if processGuid == "" {
+		err := errors.New("process_guid missing from request")
+		logger.Error("missing-process-guid", err)
+		writeBadRequestResponse(w, receptor.InvalidRequest, err)
+		return
+	}
+
+	request := receptor.RollingUpdateRequest{}
+	err := json.NewDecoder(req.Body).Decode(&request)
+	if err != nil {
+		logger.Error("invalid-json", err)
+		writeBadRequestResponse(w, receptor.InvalidJSON, err)
+		return
+	}
+
+	// the coordinator generates the successor's guid when none is given
+	successor := request.Successor
+	if successor.ProcessGuid == "" {
+		successor.ProcessGuid = processGuid
+	}
+	if fieldErrs := validateDesiredLRPRequest(successor); len(fieldErrs) > 0 {
+		validationErr := validationError(receptor.InvalidLRP, fieldErrs)
+		logger.Error("successor-invalid", validationErr)
+		writeJSONResponse(w, http.StatusBadRequest, validationErr)
+		return
+	}
+
+	status, err := h.coordinator.Start(processGuid, request)
+	if err == rollingupdate.ErrInProgress {
+		writeJSONResponse(w, http.StatusConflict, receptor.Error{
+			Type:    receptor.RollingUpdateInProgress,
+			Message: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		bbsError := models.ConvertError(err)
+		switch bbsError.Type {
+		case models.Error_ResourceNotFound:
+			writeDesiredLRPNotFoundResponse(w, processGuid)
+		case models.Error_ResourceExists:
+			writeDesiredLRPAlreadyExistsResponse(w, status.SuccessorProcessGuid)
+		case models.Error_InvalidRequest:
+			writeBadRequestResponse(w, receptor.InvalidLRP, err)
+		default:
+			writeUnknownErrorResponse(w, err)
+		}
+		return
+	}
+
+	writeJSONResponse(w, http.StatusAccepted, status)
+}
+
+func (h *RollingUpdateHandler) Get(w http.ResponseWriter, req *http.Request) {
+	processGuid := req.FormValue(":process_guid")
+	logger := h.logger.Session("get", lager.Data{
+		"ProcessGuid": processGuid,
+	})
+
+	status, err := h.coordinator.Get(processGuid)
+	switch err {
+	case nil:
+		writeJSONResponse(w, http.StatusOK, status)
+	case rollingupdate.ErrNotFound:
+		writeRollingUpdateNotFoundResponse(w, processGuid)
+	default:
+		logger.Error("failed-to-fetch-update", err)
+		writeUnknownErrorResponse(w, err)
+	}
+}
+
+func (h *RollingUpdateHandler) Abort(w http.ResponseWriter, req *http.Request) {
+	processGuid := req.FormValue(":process_guid")
+	logger := h.logger.Session("abort", lager.Data{
+		"ProcessGuid": processGuid,
+	})
+
+	status, err := h.coordinator.Abort(processGuid)
+	switch err {
+	case nil:
+		logger.Info("aborting")
+		writeJSONResponse(w, http.StatusAccepted, status)
+	case rollingupdate.ErrNotFound:
+		writeRollingUpdateNotFoundResponse(w, processGuid)
+	case rollingupdate.ErrNotInProgress:
+		writeJSONResponse(w, http.StatusConflict, receptor.Error{
+			Type:    receptor.RollingUpdateNotAbortable,
+			Message: fmt.Sprintf("rolling update of desired LRP with guid '%s' is %s", processGuid, status.State),
+		})
+	default:
+		logger.Error("failed-to-abort", err)
+		writeUnknownErrorResponse(w, err)
+	}
+}
+
+func writeRollingUpdateNotFoundResponse(w http.ResponseWriter, processGuid string) {
+	writeJSONResponse(w, http.StatusNotFound, receptor.Error{
+		Type:    receptor.RollingUpdateNotFound,
+		Message: fmt.Sprintf("no rolling update of desired LRP with guid '%s'", processGuid),
+	})
+}
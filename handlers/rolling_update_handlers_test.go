@@ -0,0 +1,226 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	"github.com/cloudfoundry-incubator/bbs/models"
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/handlers"
+	"github.com/cloudfoundry-incubator/receptor/rollingupdate"
+	"github.com/cloudfoundry-incubator/receptor/rollingupdate/fake_rollingupdate"
+	"github.com/pivotal-golang/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RollingUpdateHandler", func() {
+	var (
+		logger           lager.Logger
+		fakeCoordinator  *fake_rollingupdate.FakeCoordinator
+		responseRecorder *httptest.ResponseRecorder
+		handler          *handlers.RollingUpdateHandler
+
+		status receptor.RollingUpdateResponse
+	)
+
+	newRequest := func(body interface{}) *http.Request {
+		request := newTestRequest(body)
+		request.Form = url.Values{":process_guid": []string{"old-guid"}}
+		return request
+	}
+
+	BeforeEach(func() {
+		logger = lager.NewLogger("test")
+		logger.RegisterSink(lager.NewWriterSink(GinkgoWriter, lager.DEBUG))
+		fakeCoordinator = new(fake_rollingupdate.FakeCoordinator)
+		responseRecorder = httptest.NewRecorder()
+		handler = handlers.NewRollingUpdateHandler(fakeCoordinator, logger)
+
+		status = receptor.RollingUpdateResponse{
+			ProcessGuid:          "old-guid",
+			SuccessorProcessGuid: "new-guid",
+			State:                receptor.RollingUpdateStateInProgress,
+			Instances:            2,
+			BatchSize:            1,
+		}
+	})
+
+	Describe("Start", func() {
+		var request receptor.RollingUpdateRequest
+
+		BeforeEach(func() {
+			request = receptor.RollingUpdateRequest{
+				Successor: receptor.DesiredLRPCreateRequest{
+					Domain: "some-domain",
+					RootFS: "preloaded:some-stack",
+					Action: models.WrapAction(&models.RunAction{User: "me", Path: "/bin/server"}),
+				},
+				BatchSize: 1,
+			}
+
+			fakeCoordinator.StartReturns(status, nil)
+		})
+
+		JustBeforeEach(func() {
+			handler.Start(responseRecorder, newRequest(request))
+		})
+
+		It("starts the update", func() {
+			Expect(fakeCoordinator.StartCallCount()).To(Equal(1))
+			processGuid, actualRequest := fakeCoordinator.StartArgsForCall(0)
+			Expect(processGuid).To(Equal("old-guid"))
+			Expect(actualRequest).To(Equal(request))
+		})
+
+		It("responds with 202 and the progress of the update", func() {
+			Expect(responseRecorder.Code).To(Equal(http.StatusAccepted))
+
+			var response receptor.RollingUpdateResponse
+			err := json.Unmarshal(responseRecorder.Body.Bytes(), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal(status))
+		})
+
+		Context("when the successor is invalid", func() {
+			BeforeEach(func() {
+				request.Successor.RootFS = ""
+			})
+
+			It("responds with 400 without starting the update", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(fakeCoordinator.StartCallCount()).To(BeZero())
+
+				var receptorError receptor.Error
+				err := json.Unmarshal(responseRecorder.Body.Bytes(), &receptorError)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(receptorError.Type).To(Equal(receptor.InvalidLRP))
+				Expect(receptorError.FieldErrors[0].Field).To(Equal("rootfs"))
+			})
+		})
+
+		Context("when an update is already in progress", func() {
+			BeforeEach(func() {
+				fakeCoordinator.StartReturns(receptor.RollingUpdateResponse{}, rollingupdate.ErrInProgress)
+			})
+
+			It("responds with 409", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusConflict))
+			})
+		})
+
+		Context("when the desired LRP does not exist", func() {
+			BeforeEach(func() {
+				fakeCoordinator.StartReturns(receptor.RollingUpdateResponse{}, models.ErrResourceNotFound)
+			})
+
+			It("responds with 404", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		Context("when the successor already exists", func() {
+			BeforeEach(func() {
+				request.Successor.ProcessGuid = ""
+				fakeCoordinator.StartReturns(receptor.RollingUpdateResponse{
+					ProcessGuid:          "old-guid",
+					SuccessorProcessGuid: "old-guid-1234",
+				}, models.ErrResourceExists)
+			})
+
+			It("responds with 409 naming the successor's resolved guid", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusConflict))
+
+				var receptorError receptor.Error
+				err := json.Unmarshal(responseRecorder.Body.Bytes(), &receptorError)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(receptorError.Type).To(Equal(receptor.DesiredLRPAlreadyExists))
+				Expect(receptorError.Message).To(ContainSubstring("old-guid-1234"))
+			})
+		})
+
+		Context("when starting the update fails", func() {
+			BeforeEach(func() {
+				fakeCoordinator.StartReturns(receptor.RollingUpdateResponse{}, errors.New("boom"))
+			})
+
+			It("responds with 500", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+
+	Describe("Get", func() {
+		Context("when the update exists", func() {
+			BeforeEach(func() {
+				fakeCoordinator.GetReturns(status, nil)
+			})
+
+			It("responds with its progress", func() {
+				handler.Get(responseRecorder, newRequest(""))
+				Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+				Expect(fakeCoordinator.GetArgsForCall(0)).To(Equal("old-guid"))
+
+				var response receptor.RollingUpdateResponse
+				err := json.Unmarshal(responseRecorder.Body.Bytes(), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response).To(Equal(status))
+			})
+		})
+
+		Context("when the update does not exist", func() {
+			BeforeEach(func() {
+				fakeCoordinator.GetReturns(receptor.RollingUpdateResponse{}, rollingupdate.ErrNotFound)
+			})
+
+			It("responds with 404", func() {
+				handler.Get(responseRecorder, newRequest(""))
+				Expect(responseRecorder.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		Context("when fetching the update fails", func() {
+			BeforeEach(func() {
+				fakeCoordinator.GetReturns(receptor.RollingUpdateResponse{}, errors.New("boom"))
+			})
+
+			It("responds with 500", func() {
+				handler.Get(responseRecorder, newRequest(""))
+				Expect(responseRecorder.Code).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+
+	Describe("Abort", func() {
+		It("aborts the update", func() {
+			fakeCoordinator.AbortReturns(status, nil)
+
+			handler.Abort(responseRecorder, newRequest(""))
+			Expect(responseRecorder.Code).To(Equal(http.StatusAccepted))
+			Expect(fakeCoordinator.AbortArgsForCall(0)).To(Equal("old-guid"))
+		})
+
+		Context("when the update has finished", func() {
+			It("responds with 409", func() {
+				status.State = receptor.RollingUpdateStateCompleted
+				fakeCoordinator.AbortReturns(status, rollingupdate.ErrNotInProgress)
+
+				handler.Abort(responseRecorder, newRequest(""))
+				Expect(responseRecorder.Code).To(Equal(http.StatusConflict))
+			})
+		})
+
+		Context("when the update does not exist", func() {
+			It("responds with 404", func() {
+				fakeCoordinator.AbortReturns(receptor.RollingUpdateResponse{}, rollingupdate.ErrNotFound)
+
+				handler.Abort(responseRecorder, newRequest(""))
+				Expect(responseRecorder.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+})
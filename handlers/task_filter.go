@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/cloudfoundry-incubator/bbs/models"
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/serialization"
+)
+
+func taskFilterFromRequest(req *http.Request) (receptor.TaskFilter, error) {
+	filter := receptor.TaskFilter{
+		Domain: req.FormValue("domain"),
+		State:  req.FormValue("state"),
+		CellID: req.FormValue("cell_id"),
+	}
+
+	if omitResult := req.FormValue("omit_result"); omitResult != "" {
+		var err error
+		filter.OmitResult, err = strconv.ParseBool(omitResult)
+		if err != nil {
+			return receptor.TaskFilter{}, fmt.Errorf("invalid omit_result: %s", omitResult)
+		}
+	}
+
+	if completedBefore := req.FormValue("completed_before"); completedBefore != "" {
+		var err error
+		filter.CompletedBefore, err = strconv.ParseInt(completedBefore, 10, 64)
+		if err != nil || filter.CompletedBefore <= 0 {
+			return receptor.TaskFilter{}, fmt.Errorf("invalid completed_before: %s", completedBefore)
+		}
+	}
+
+	switch filter.State {
+	case "",
+		receptor.TaskStatePending,
+		receptor.TaskStateRunning,
+		receptor.TaskStateCompleted,
+		receptor.TaskStateResolving:
+	default:
+		return receptor.TaskFilter{}, fmt.Errorf("invalid task state: %s", filter.State)
+	}
+
+	return filter, nil
+}
+
+// filterTasks applies the parts of the filter which the BBS does not support
+// querying by.
+func filterTasks(tasks []*models.Task, filter receptor.TaskFilter) []*models.Task {
+	if filter.State == "" && filter.CellID == "" && filter.CompletedBefore == 0 {
+		return tasks
+	}
+
+	filtered := make([]*models.Task, 0, len(tasks))
+	for _, task := range tasks {
+		if filter.CellID != "" && task.CellId != filter.CellID {
+			continue
+		}
+		if filter.State != "" && serialization.TaskStateToResponseState(task.State) != filter.State {
+			continue
+		}
+		if filter.CompletedBefore != 0 && !completedBefore(task, filter.CompletedBefore) {
+			continue
+		}
+		filtered = append(filtered, task)
+	}
+
+	return filtered
+}
+
+func completedBefore(task *models.Task, timestamp int64) bool {
+	return task.FirstCompletedAt != 0 && task.FirstCompletedAt < timestamp
+}
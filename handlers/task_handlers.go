@@ -4,24 +4,37 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
-	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/cloudfoundry-incubator/bbs"
 	"github.com/cloudfoundry-incubator/bbs/models"
 	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/event"
 	"github.com/cloudfoundry-incubator/receptor/serialization"
 	"github.com/pivotal-golang/lager"
 )
 
+const (
+	maxBulkTaskConcurrency = 20
+	maxTaskWait            = 5 * time.Minute
+	maxTaskResultSize      = 1024 * 1024
+)
+
 type TaskHandler struct {
 	bbs    bbs.Client
+	hub    event.Hub
 	logger lager.Logger
 }
 
-func NewTaskHandler(bbs bbs.Client, logger lager.Logger) *TaskHandler {
+func NewTaskHandler(bbs bbs.Client, hub event.Hub, logger lager.Logger) *TaskHandler {
 	return &TaskHandler{
 		bbs:    bbs,
+		hub:    hub,
 		logger: logger.Session("task-handler"),
 	}
 }
@@ -40,19 +53,84 @@ func (h *TaskHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	task, err := serialization.TaskFromRequest(taskRequest)
-	if err == nil {
-		if task.GetCompletionCallbackUrl() != "" {
-			_, err = url.ParseRequestURI(task.GetCompletionCallbackUrl())
-		}
+	dryRun, err := dryRunFromRequest(r)
+	if err != nil {
+		log.Error("invalid-dry-run", err)
+		writeBadRequestResponse(w, receptor.InvalidRequest, err)
+		return
 	}
+
+	if dryRun {
+		writeValidationResponse(w, validateTaskRequest(taskRequest))
+		return
+	}
+
+	status, createErr := h.createTask(log, taskRequest)
+	if createErr != nil {
+		writeJSONResponse(w, status, *createErr)
+		return
+	}
+
+	w.WriteHeader(status)
+}
+
+func (h *TaskHandler) CreateBulk(w http.ResponseWriter, r *http.Request) {
+	log := h.logger.Session("create-bulk")
+	taskRequests := []receptor.TaskCreateRequest{}
+
+	err := json.NewDecoder(r.Body).Decode(&taskRequests)
+	if err != nil {
+		log.Error("invalid-json", err)
+		writeBadRequestResponse(w, receptor.InvalidJSON, err)
+		return
+	}
+
+	log.Info("creating-tasks", lager.Data{"count": len(taskRequests)})
+
+	results := make([]receptor.TaskCreateResult, len(taskRequests))
+	throttle := make(chan struct{}, maxBulkTaskConcurrency)
+	wg := sync.WaitGroup{}
+
+	for i := range taskRequests {
+		wg.Add(1)
+		throttle <- struct{}{}
+
+		go func(i int) {
+			defer func() {
+				<-throttle
+				wg.Done()
+			}()
+
+			status, createErr := h.createTask(log, taskRequests[i])
+			results[i] = receptor.TaskCreateResult{
+				TaskGuid: taskRequests[i].TaskGuid,
+				Status:   status,
+				Error:    createErr,
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	writeJSONResponse(w, http.StatusOK, results)
+}
+
+// createTask desires a single task, returning the status code and, on
+// failure, the error with which to respond.
+func (h *TaskHandler) createTask(log lager.Logger, taskRequest receptor.TaskCreateRequest) (int, *receptor.Error) {
+	if fieldErrs := validateTaskRequest(taskRequest); len(fieldErrs) > 0 {
+		validationErr := validationError(receptor.InvalidTask, fieldErrs)
+		log.Error("task-request-invalid", validationErr)
+		return http.StatusBadRequest, &validationErr
+	}
+
+	task, err := serialization.TaskFromRequest(taskRequest)
 	if err != nil {
 		log.Error("task-request-invalid", err)
-		writeJSONResponse(w, http.StatusBadRequest, receptor.Error{
+		return http.StatusBadRequest, &receptor.Error{
 			Type:    receptor.InvalidTask,
 			Message: err.Error(),
-		})
-		return
+		}
 	}
 
 	log.Debug("creating-task", lager.Data{"task-guid": task.TaskGuid})
@@ -63,43 +141,61 @@ func (h *TaskHandler) Create(w http.ResponseWriter, r *http.Request) {
 		bbsError := models.ConvertError(err)
 		switch bbsError.Type {
 		case models.Error_InvalidRequest:
-			writeJSONResponse(w, http.StatusBadRequest, receptor.Error{
+			return http.StatusBadRequest, &receptor.Error{
 				Type:    receptor.InvalidTask,
 				Message: err.Error(),
-			})
-			return
+			}
 		case models.Error_ResourceExists:
-			writeJSONResponse(w, http.StatusConflict, receptor.Error{
+			return http.StatusConflict, &receptor.Error{
 				Type:    receptor.TaskGuidAlreadyExists,
 				Message: "task already exists",
-			})
-			return
+			}
 		default:
-			writeUnknownErrorResponse(w, err)
-			return
+			return http.StatusInternalServerError, &receptor.Error{
+				Type:    receptor.UnknownError,
+				Message: err.Error(),
+			}
 		}
 	}
 
 	log.Info("created", lager.Data{"task-guid": task.TaskGuid})
-	w.WriteHeader(http.StatusCreated)
+	return http.StatusCreated, nil
 }
 
 func (h *TaskHandler) GetAll(w http.ResponseWriter, req *http.Request) {
-	domain := req.FormValue("domain")
 	logger := h.logger.Session("get-all", lager.Data{
-		"domain": domain,
+		"domain":  req.FormValue("domain"),
+		"state":   req.FormValue("state"),
+		"cell-id": req.FormValue("cell_id"),
 	})
 
+	filter, err := taskFilterFromRequest(req)
+	if err != nil {
+		logger.Error("invalid-filter", err)
+		writeBadRequestResponse(w, receptor.InvalidRequest, err)
+		return
+	}
+
+	page, err := paginationFromRequest(req)
+	if err != nil {
+		logger.Error("invalid-pagination", err)
+		writeBadRequestResponse(w, receptor.InvalidRequest, err)
+		return
+	}
+
 	var tasks []*models.Task
-	var err error
 
-	if domain == "" {
+	if filter.Domain == "" {
 		tasks, err = h.bbs.Tasks()
 	} else {
-		tasks, err = h.bbs.TasksByDomain(domain)
+		tasks, err = h.bbs.TasksByDomain(filter.Domain)
+	}
+
+	if err == nil {
+		tasks = paginateTasks(w, req, page, filterTasks(tasks, filter))
 	}
 
-	writeTaskResponse(w, logger, tasks, err)
+	writeTaskResponse(w, logger, tasks, filter.OmitResult, err)
 }
 
 func (h *TaskHandler) GetByGuid(w http.ResponseWriter, req *http.Request) {
@@ -115,51 +211,397 @@ func (h *TaskHandler) GetByGuid(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	if waitParam := req.FormValue("wait"); waitParam != "" {
+		wait, err := time.ParseDuration(waitParam)
+		if err != nil || wait < 0 {
+			err = fmt.Errorf("invalid wait duration: %s", waitParam)
+			logger.Error("invalid-wait", err)
+			writeBadRequestResponse(w, receptor.InvalidRequest, err)
+			return
+		}
+
+		if wait > maxTaskWait {
+			wait = maxTaskWait
+		}
+
+		h.waitForTask(w, logger, guid, wait)
+		return
+	}
+
+	h.writeCurrentTask(w, logger, guid)
+}
+
+// waitForTask responds with the task once it is COMPLETED or RESOLVING, or
+// with the task as it stands once the wait expires. The hub is subscribed to
+// before the task is fetched so that no state change is missed in between.
+func (h *TaskHandler) waitForTask(w http.ResponseWriter, logger lager.Logger, guid string, wait time.Duration) {
+	source, err := h.hub.Subscribe()
+	if err != nil {
+		logger.Error("failed-to-subscribe-to-events", err)
+		writeUnknownErrorResponse(w, err)
+		return
+	}
+	defer source.Close()
+
+	task, ok := h.fetchTask(w, logger, guid)
+	if !ok {
+		return
+	}
+
+	if taskFinished(task) {
+		writeJSONResponse(w, http.StatusOK, task)
+		return
+	}
+
+	messages := make(chan event.Message)
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		defer close(messages)
+		for {
+			message, err := source.Next()
+			if err != nil {
+				return
+			}
+
+			select {
+			case messages <- message:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	for {
+		select {
+		case message, ok := <-messages:
+			if !ok {
+				logger.Info("event-source-closed")
+				h.writeCurrentTask(w, logger, guid)
+				return
+			}
+
+			if h.respondToTaskEvent(w, logger, guid, message.Event) {
+				return
+			}
+
+		case <-timer.C:
+			h.writeCurrentTask(w, logger, guid)
+			return
+		}
+	}
+}
+
+// respondToTaskEvent responds, returning true, if the event shows that the
+// waited for task has finished or been removed.
+func (h *TaskHandler) respondToTaskEvent(w http.ResponseWriter, logger lager.Logger, guid string, e receptor.Event) bool {
+	switch e := e.(type) {
+	case receptor.TaskChangedEvent:
+		if e.After.TaskGuid == guid && taskFinished(e.After) {
+			writeJSONResponse(w, http.StatusOK, e.After)
+			return true
+		}
+
+	case receptor.TaskRemovedEvent:
+		if e.TaskResponse.TaskGuid == guid {
+			writeTaskNotFoundResponse(w, guid)
+			return true
+		}
+
+	case receptor.ResyncRequiredEvent:
+		task, ok := h.fetchTask(w, logger, guid)
+		if !ok {
+			return true
+		}
+
+		if taskFinished(task) {
+			writeJSONResponse(w, http.StatusOK, task)
+			return true
+		}
+	}
+
+	return false
+}
+
+func (h *TaskHandler) writeCurrentTask(w http.ResponseWriter, logger lager.Logger, guid string) {
+	task, ok := h.fetchTask(w, logger, guid)
+	if ok {
+		writeJSONResponse(w, http.StatusOK, task)
+	}
+}
+
+// fetchTask writes the appropriate error response and returns false if the
+// task can not be fetched.
+func (h *TaskHandler) fetchTask(w http.ResponseWriter, logger lager.Logger, guid string) (receptor.TaskResponse, bool) {
 	task, err := h.bbs.TaskByGuid(guid)
 	if models.ErrResourceNotFound.Equal(err) {
 		writeTaskNotFoundResponse(w, guid)
-		return
+		return receptor.TaskResponse{}, false
 	}
 
 	if err != nil {
-		h.logger.Error("failed-to-fetch-task", err)
+		logger.Error("failed-to-fetch-task", err)
 		writeUnknownErrorResponse(w, err)
+		return receptor.TaskResponse{}, false
+	}
+
+	return serialization.TaskToResponse(task), true
+}
+
+func taskFinished(task receptor.TaskResponse) bool {
+	return task.State == receptor.TaskStateCompleted || task.State == receptor.TaskStateResolving
+}
+
+func (h *TaskHandler) GetResult(w http.ResponseWriter, req *http.Request) {
+	guid := req.FormValue(":task_guid")
+	logger := h.logger.Session("get-result", lager.Data{
+		"TaskGuid": guid,
+	})
+
+	if guid == "" {
+		err := errors.New("task_guid missing from request")
+		logger.Error("missing-task-guid", err)
+		writeBadRequestResponse(w, receptor.InvalidRequest, err)
+		return
+	}
+
+	task, ok := h.fetchTask(w, logger, guid)
+	if !ok {
+		return
+	}
+
+	if !taskFinished(task) {
+		writeJSONResponse(w, http.StatusConflict, receptor.Error{
+			Type:    receptor.TaskNotCompleted,
+			Message: fmt.Sprintf("task with guid '%s' has not completed", guid),
+		})
 		return
 	}
 
-	writeJSONResponse(w, http.StatusOK, serialization.TaskToResponse(task))
+	// the cap bounds the raw responses served, not the memory used, since
+	// the BBS has already returned the whole task
+	if len(task.Result) > maxTaskResultSize {
+		logger.Info("result-too-large", lager.Data{"size": len(task.Result)})
+		writeJSONResponse(w, http.StatusInternalServerError, receptor.Error{
+			Type:    receptor.TaskResultTooLarge,
+			Message: fmt.Sprintf("result of task with guid '%s' is larger than %d bytes", guid, maxTaskResultSize),
+		})
+		return
+	}
+
+	w.Header().Set("Content-Length", strconv.Itoa(len(task.Result)))
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.WriteHeader(http.StatusOK)
+
+	w.Write([]byte(task.Result))
 }
 
 func (h *TaskHandler) Delete(w http.ResponseWriter, req *http.Request) {
 	guid := req.FormValue(":task_guid")
 
+	status, deleteErr := h.deleteTask(h.logger, guid)
+	if deleteErr != nil {
+		writeJSONResponse(w, status, *deleteErr)
+	}
+}
+
+func (h *TaskHandler) DeleteAll(w http.ResponseWriter, req *http.Request) {
+	logger := h.logger.Session("delete-all", lager.Data{
+		"domain":           req.FormValue("domain"),
+		"state":            req.FormValue("state"),
+		"completed-before": req.FormValue("completed_before"),
+	})
+
+	filter, err := taskFilterFromRequest(req)
+	if err != nil {
+		logger.Error("invalid-filter", err)
+		writeBadRequestResponse(w, receptor.InvalidRequest, err)
+		return
+	}
+
+	if filter.Domain == "" {
+		err := errors.New("domain missing from request")
+		logger.Error("missing-domain", err)
+		writeBadRequestResponse(w, receptor.InvalidRequest, err)
+		return
+	}
+
+	if filter.State == "" {
+		filter.State = receptor.TaskStateCompleted
+	}
+
+	if filter.State != receptor.TaskStateCompleted {
+		err := errors.New("only COMPLETED tasks can be deleted")
+		logger.Error("invalid-state", err)
+		writeBadRequestResponse(w, receptor.InvalidRequest, err)
+		return
+	}
+
+	tasks, err := h.bbs.TasksByDomain(filter.Domain)
+	if err != nil {
+		logger.Error("failed-to-fetch-tasks", err)
+		writeUnknownErrorResponse(w, err)
+		return
+	}
+
+	tasks = filterTasks(tasks, filter)
+	logger.Info("deleting-tasks", lager.Data{"count": len(tasks)})
+
+	failures := make([]*receptor.Error, len(tasks))
+	throttle := make(chan struct{}, maxBulkTaskConcurrency)
+	wg := sync.WaitGroup{}
+
+	for i := range tasks {
+		wg.Add(1)
+		throttle <- struct{}{}
+
+		go func(i int) {
+			defer func() {
+				<-throttle
+				wg.Done()
+			}()
+
+			_, failures[i] = h.deleteTask(logger, tasks[i].TaskGuid)
+		}(i)
+	}
+
+	wg.Wait()
+
+	response := receptor.TasksDeleteResponse{
+		Deleted: []string{},
+		Failed:  []receptor.TaskDeleteFailure{},
+	}
+	for i, task := range tasks {
+		if failures[i] != nil {
+			response.Failed = append(response.Failed, receptor.TaskDeleteFailure{
+				TaskGuid: task.TaskGuid,
+				Error:    *failures[i],
+			})
+			continue
+		}
+		response.Deleted = append(response.Deleted, task.TaskGuid)
+	}
+
+	writeJSONResponse(w, http.StatusOK, response)
+}
+
+// deleteTask resolves and deletes a single completed task, returning the
+// status code and error with which to respond on failure.
+func (h *TaskHandler) deleteTask(logger lager.Logger, guid string) (int, *receptor.Error) {
 	err := h.bbs.ResolvingTask(guid)
 	if err != nil {
 		bbsError := models.ConvertError(err)
 		switch bbsError.Type {
 		case models.Error_ResourceNotFound:
-			h.logger.Error("task-not-found", err)
-			writeTaskNotFoundResponse(w, guid)
-			return
+			logger.Error("task-not-found", err)
+			return http.StatusNotFound, &receptor.Error{
+				Type:    receptor.TaskNotFound,
+				Message: fmt.Sprintf("task with guid '%s' not found", guid),
+			}
 		case models.Error_InvalidStateTransition:
-			h.logger.Error("invalid-task-state-transition", err)
-			writeJSONResponse(w, http.StatusConflict, receptor.Error{
+			logger.Error("invalid-task-state-transition", err)
+			return http.StatusConflict, &receptor.Error{
 				Type:    receptor.TaskNotDeletable,
 				Message: "This task has not been completed. Please retry when it is completed.",
-			})
-			return
+			}
 		default:
-			h.logger.Error("failed-to-mark-task-resolving", err)
-			writeUnknownErrorResponse(w, err)
-			return
+			logger.Error("failed-to-mark-task-resolving", err)
+			return http.StatusInternalServerError, &receptor.Error{
+				Type:    receptor.UnknownError,
+				Message: err.Error(),
+			}
 		}
 	}
 
 	err = h.bbs.DeleteTask(guid)
 	if err != nil {
-		h.logger.Error("failed-to-delete-task", err)
+		logger.Error("failed-to-delete-task", err)
+		return http.StatusInternalServerError, &receptor.Error{
+			Type:    receptor.UnknownError,
+			Message: err.Error(),
+		}
+	}
+
+	return http.StatusOK, nil
+}
+
+// Retry recreates a failed task from its definition. The new task takes the
+// guid given in the request, or the failed task's own guid once the failed
+// task has been resolved.
+func (h *TaskHandler) Retry(w http.ResponseWriter, req *http.Request) {
+	guid := req.FormValue(":task_guid")
+	logger := h.logger.Session("retry", lager.Data{
+		"TaskGuid": guid,
+	})
+
+	retryRequest := receptor.TaskRetryRequest{}
+	err := json.NewDecoder(req.Body).Decode(&retryRequest)
+	if err != nil && err != io.EOF {
+		logger.Error("invalid-json", err)
+		writeBadRequestResponse(w, receptor.InvalidJSON, err)
+		return
+	}
+
+	task, err := h.bbs.TaskByGuid(guid)
+	if models.ErrResourceNotFound.Equal(err) {
+		writeTaskNotFoundResponse(w, guid)
+		return
+	}
+	if err != nil {
+		logger.Error("failed-to-fetch-task", err)
 		writeUnknownErrorResponse(w, err)
+		return
+	}
+
+	if task.State != models.Task_Completed || !task.Failed {
+		writeJSONResponse(w, http.StatusConflict, receptor.Error{
+			Type:    receptor.TaskNotRetryable,
+			Message: fmt.Sprintf("task with guid '%s' has not failed", guid),
+		})
+		return
+	}
+
+	retryGuid := retryRequest.TaskGuid
+	if retryGuid == "" {
+		retryGuid = guid
+	}
+
+	createRequest := serialization.TaskToRequest(task)
+	createRequest.TaskGuid = retryGuid
+
+	if retryGuid == guid {
+		// validate before resolving, so that the failed task is not lost if
+		// it can not be recreated
+		if fieldErrs := validateTaskRequest(createRequest); len(fieldErrs) > 0 {
+			validationErr := validationError(receptor.InvalidTask, fieldErrs)
+			logger.Error("task-request-invalid", validationErr)
+			writeJSONResponse(w, http.StatusBadRequest, validationErr)
+			return
+		}
+
+		status, deleteErr := h.deleteTask(logger, guid)
+		if deleteErr != nil {
+			writeJSONResponse(w, status, *deleteErr)
+			return
+		}
+	}
+
+	status, createErr := h.createTask(logger, createRequest)
+	if createErr != nil {
+		writeJSONResponse(w, status, *createErr)
+		return
 	}
+
+	logger.Info("retried", lager.Data{"retry-guid": retryGuid})
+	writeJSONResponse(w, http.StatusCreated, receptor.TaskRetryResponse{
+		TaskGuid:              retryGuid,
+		RetryOf:               guid,
+		PreviousFailureReason: task.FailureReason,
+	})
 }
 
 func (h *TaskHandler) Cancel(w http.ResponseWriter, req *http.Request) {
@@ -178,7 +620,7 @@ func (h *TaskHandler) Cancel(w http.ResponseWriter, req *http.Request) {
 	}
 }
 
-func writeTaskResponse(w http.ResponseWriter, logger lager.Logger, tasks []*models.Task, err error) {
+func writeTaskResponse(w http.ResponseWriter, logger lager.Logger, tasks []*models.Task, omitResult bool, err error) {
 	if err != nil {
 		logger.Error("failed-to-fetch-tasks", err)
 		writeUnknownErrorResponse(w, err)
@@ -187,12 +629,33 @@ func writeTaskResponse(w http.ResponseWriter, logger lager.Logger, tasks []*mode
 
 	taskResponses := make([]receptor.TaskResponse, 0, len(tasks))
 	for _, task := range tasks {
-		taskResponses = append(taskResponses, serialization.TaskToResponse(task))
+		taskResponse := serialization.TaskToResponse(task)
+		if omitResult {
+			taskResponse.Result = ""
+		}
+		taskResponses = append(taskResponses, taskResponse)
 	}
 
 	writeJSONResponse(w, http.StatusOK, taskResponses)
 }
 
+func paginateTasks(w http.ResponseWriter, req *http.Request, page pagination, tasks []*models.Task) []*models.Task {
+	sort.Sort(tasksByGuid(tasks))
+
+	start, end := page.page(len(tasks), func(i int) string { return tasks[i].TaskGuid })
+	if end < len(tasks) {
+		writeNextPageLink(w, req, tasks[end-1].TaskGuid)
+	}
+
+	return tasks[start:end]
+}
+
+type tasksByGuid []*models.Task
+
+func (t tasksByGuid) Len() int           { return len(t) }
+func (t tasksByGuid) Swap(i, j int)      { t[i], t[j] = t[j], t[i] }
+func (t tasksByGuid) Less(i, j int) bool { return t[i].TaskGuid < t[j].TaskGuid }
+
 func writeTaskNotFoundResponse(w http.ResponseWriter, taskGuid string) {
 	writeJSONResponse(w, http.StatusNotFound, receptor.Error{
 		Type:    receptor.TaskNotFound,
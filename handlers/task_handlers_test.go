@@ -1,16 +1,20 @@
 package handlers_test
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 
 	"github.com/cloudfoundry-incubator/bbs/fake_bbs"
 	"github.com/cloudfoundry-incubator/bbs/models"
 	"github.com/cloudfoundry-incubator/bbs/models/test/model_helpers"
 	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/event"
 	"github.com/cloudfoundry-incubator/receptor/handlers"
 	"github.com/cloudfoundry-incubator/receptor/serialization"
 	"github.com/pivotal-golang/lager"
@@ -23,6 +27,7 @@ var _ = Describe("TaskHandler", func() {
 	var (
 		logger           lager.Logger
 		fakeClient       *fake_bbs.FakeClient
+		hub              event.Hub
 		responseRecorder *httptest.ResponseRecorder
 		handler          *handlers.TaskHandler
 		request          *http.Request
@@ -33,7 +38,8 @@ var _ = Describe("TaskHandler", func() {
 		logger = lager.NewLogger("test")
 		logger.RegisterSink(lager.NewWriterSink(GinkgoWriter, lager.DEBUG))
 		responseRecorder = httptest.NewRecorder()
-		handler = handlers.NewTaskHandler(fakeClient, logger)
+		hub = event.NewHub(16)
+		handler = handlers.NewTaskHandler(fakeClient, hub, logger)
 	})
 
 	Describe("Create", func() {
@@ -74,6 +80,70 @@ var _ = Describe("TaskHandler", func() {
 
 		})
 
+		Context("when dry_run is requested", func() {
+			var validation receptor.ValidationResponse
+
+			JustBeforeEach(func() {
+				request := newTestRequest(validCreateRequest)
+				request.URL.RawQuery = "dry_run=true"
+				handler.Create(responseRecorder, request)
+
+				validation = receptor.ValidationResponse{}
+				err := json.Unmarshal(responseRecorder.Body.Bytes(), &validation)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("does not desire the task", func() {
+				Expect(fakeClient.DesireTaskCallCount()).To(BeZero())
+			})
+
+			Context("when the request is valid", func() {
+				It("responds with 200 and no errors", func() {
+					Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+					Expect(validation.Valid).To(BeTrue())
+					Expect(validation.Errors).To(BeEmpty())
+				})
+			})
+
+			Context("when the request is invalid", func() {
+				BeforeEach(func() {
+					validCreateRequest.TaskGuid = "not a guid"
+					validCreateRequest.Domain = ""
+					validCreateRequest.Action = nil
+					validCreateRequest.MemoryMB = -1
+					validCreateRequest.CompletionCallbackURL = "ಠ_ಠ"
+				})
+
+				It("reports every invalid field", func() {
+					Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+					Expect(validation.Valid).To(BeFalse())
+
+					fields := map[string]string{}
+					for _, fieldErr := range validation.Errors {
+						fields[fieldErr.Field] = fieldErr.Code
+					}
+					Expect(fields).To(Equal(map[string]string{
+						"task_guid":               receptor.FieldInvalid,
+						"domain":                  receptor.FieldRequired,
+						"action":                  receptor.FieldRequired,
+						"memory_mb":               receptor.FieldOutOfRange,
+						"completion_callback_url": receptor.FieldInvalid,
+					}))
+				})
+			})
+
+			Context("when dry_run is not a boolean", func() {
+				It("responds with 400", func() {
+					request := newTestRequest(validCreateRequest)
+					request.URL.RawQuery = "dry_run=maybe"
+
+					responseRecorder = httptest.NewRecorder()
+					handler.Create(responseRecorder, request)
+					Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+				})
+			})
+		})
+
 		Context("when everything succeeds", func() {
 			JustBeforeEach(func() {
 				handler.Create(responseRecorder, newTestRequest(validCreateRequest))
@@ -101,7 +171,7 @@ var _ = Describe("TaskHandler", func() {
 							{Name: "var2", Value: "val2"},
 						}
 						validCreateRequest.EgressRules = []*models.SecurityGroupRule{
-							{Protocol: "tcp"},
+							{Protocol: "tcp", Destinations: []string{"0.0.0.0/0"}, Ports: []uint32{80}},
 						}
 					})
 
@@ -112,7 +182,9 @@ var _ = Describe("TaskHandler", func() {
 							{Name: "var1", Value: "val1"},
 							{Name: "var2", Value: "val2"},
 						}))
-						Expect(def.EgressRules).To(Equal([]*models.SecurityGroupRule{{Protocol: "tcp"}}))
+						Expect(def.EgressRules).To(Equal([]*models.SecurityGroupRule{
+							{Protocol: "tcp", Destinations: []string{"0.0.0.0/0"}, Ports: []uint32{80}},
+						}))
 					})
 				})
 
@@ -134,6 +206,17 @@ var _ = Describe("TaskHandler", func() {
 				It("errors", func() {
 					Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
 				})
+
+				It("identifies the invalid field", func() {
+					var receptorError receptor.Error
+					err := json.Unmarshal(responseRecorder.Body.Bytes(), &receptorError)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(receptorError.Type).To(Equal(receptor.InvalidTask))
+					Expect(receptorError.FieldErrors).To(HaveLen(1))
+					Expect(receptorError.FieldErrors[0].Field).To(Equal("completion_callback_url"))
+					Expect(receptorError.FieldErrors[0].Code).To(Equal(receptor.FieldInvalid))
+				})
 			})
 		})
 
@@ -231,6 +314,106 @@ var _ = Describe("TaskHandler", func() {
 		})
 	})
 
+	Describe("CreateBulk", func() {
+		var taskRequests []receptor.TaskCreateRequest
+
+		BeforeEach(func() {
+			taskRequests = []receptor.TaskCreateRequest{
+				{
+					TaskGuid: "task-guid-1",
+					Domain:   "test-domain",
+					RootFS:   "docker://docker",
+					Action:   models.WrapAction(&models.RunAction{User: "me", Path: "/bin/bash"}),
+				},
+				{
+					TaskGuid: "task-guid-2",
+					Domain:   "test-domain",
+					RootFS:   "docker://docker",
+					Action:   models.WrapAction(&models.RunAction{User: "me", Path: "/bin/bash"}),
+				},
+				{
+					TaskGuid: "task-guid-3",
+					Domain:   "test-domain",
+					RootFS:   "docker://docker",
+					Action:   models.WrapAction(&models.RunAction{User: "me", Path: "/bin/bash"}),
+				},
+			}
+
+			fakeClient.DesireTaskStub = func(taskGuid, domain string, def *models.TaskDefinition) error {
+				switch taskGuid {
+				case "task-guid-2":
+					return models.ErrResourceExists
+				case "task-guid-3":
+					return errors.New("ka-boom")
+				default:
+					return nil
+				}
+			}
+		})
+
+		JustBeforeEach(func() {
+			handler.CreateBulk(responseRecorder, newTestRequest(taskRequests))
+		})
+
+		It("calls DesireTask on the BBS for every task", func() {
+			Expect(fakeClient.DesireTaskCallCount()).To(Equal(3))
+		})
+
+		It("responds with 200 OK", func() {
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+		})
+
+		It("responds with a result per task, in order", func() {
+			var results []receptor.TaskCreateResult
+			err := json.Unmarshal(responseRecorder.Body.Bytes(), &results)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(results).To(Equal([]receptor.TaskCreateResult{
+				{TaskGuid: "task-guid-1", Status: http.StatusCreated},
+				{
+					TaskGuid: "task-guid-2",
+					Status:   http.StatusConflict,
+					Error:    &receptor.Error{Type: receptor.TaskGuidAlreadyExists, Message: "task already exists"},
+				},
+				{
+					TaskGuid: "task-guid-3",
+					Status:   http.StatusInternalServerError,
+					Error:    &receptor.Error{Type: receptor.UnknownError, Message: "ka-boom"},
+				},
+			}))
+		})
+
+		Context("when a task request is invalid", func() {
+			BeforeEach(func() {
+				taskRequests[0].CompletionCallbackURL = "ಠ_ಠ"
+			})
+
+			It("does not desire that task", func() {
+				Expect(fakeClient.DesireTaskCallCount()).To(Equal(2))
+			})
+
+			It("reports it as an invalid task", func() {
+				var results []receptor.TaskCreateResult
+				err := json.Unmarshal(responseRecorder.Body.Bytes(), &results)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(results[0].Status).To(Equal(http.StatusBadRequest))
+				Expect(results[0].Error.Type).To(Equal(receptor.InvalidTask))
+			})
+		})
+
+		Context("when the request is not an array of task requests", func() {
+			JustBeforeEach(func() {
+				responseRecorder = httptest.NewRecorder()
+				handler.CreateBulk(responseRecorder, newTestRequest("hello"))
+			})
+
+			It("responds with 400 BAD REQUEST", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
 	Describe("GetAll", func() {
 		Context("when reading tasks from the BBS fails", func() {
 			BeforeEach(func() {
@@ -301,6 +484,154 @@ var _ = Describe("TaskHandler", func() {
 					Expect(tasks).To(ConsistOf(expectedTasks))
 				})
 			})
+
+			Context("when state and cell_id query params are provided", func() {
+				BeforeEach(func() {
+					domain1Task.State = models.Task_Running
+					domain1Task.CellId = "cell-1"
+					domain2Task.State = models.Task_Running
+					domain2Task.CellId = "cell-2"
+				})
+
+				It("returns only the matching tasks", func() {
+					request, err := http.NewRequest("", "http://example.com?state=RUNNING&cell_id=cell-2", nil)
+					Expect(err).NotTo(HaveOccurred())
+
+					handler.GetAll(responseRecorder, request)
+					Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+
+					var tasks []receptor.TaskResponse
+					err = json.Unmarshal(responseRecorder.Body.Bytes(), &tasks)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(tasks).To(Equal([]receptor.TaskResponse{
+						serialization.TaskToResponse(domain2Task),
+					}))
+				})
+
+				It("returns no tasks when none are in the state", func() {
+					request, err := http.NewRequest("", "http://example.com?state=COMPLETED", nil)
+					Expect(err).NotTo(HaveOccurred())
+
+					handler.GetAll(responseRecorder, request)
+					Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+					Expect(responseRecorder.Body.String()).To(Equal("[]"))
+				})
+			})
+
+			Context("when omit_result is true", func() {
+				BeforeEach(func() {
+					domain1Task.Result = "some-result"
+				})
+
+				It("leaves the result out of the tasks", func() {
+					request, err := http.NewRequest("", "http://example.com?omit_result=true", nil)
+					Expect(err).NotTo(HaveOccurred())
+
+					handler.GetAll(responseRecorder, request)
+					Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+
+					var tasks []receptor.TaskResponse
+					err = json.Unmarshal(responseRecorder.Body.Bytes(), &tasks)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(tasks).To(HaveLen(2))
+					for _, task := range tasks {
+						Expect(task.Result).To(BeEmpty())
+					}
+				})
+			})
+
+			Context("when the state query param is not a task state", func() {
+				It("responds with 400 Bad Request", func() {
+					request, err := http.NewRequest("", "http://example.com?state=BOGUS", nil)
+					Expect(err).NotTo(HaveOccurred())
+
+					handler.GetAll(responseRecorder, request)
+					Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+					Expect(fakeClient.TasksCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when paginating", func() {
+				var domain3Task *models.Task
+
+				BeforeEach(func() {
+					domain3Task = model_helpers.NewValidTask("task-guid-3")
+
+					fakeClient.TasksReturns([]*models.Task{
+						domain3Task,
+						domain1Task,
+						domain2Task,
+					}, nil)
+				})
+
+				It("returns the first page sorted by guid with a link to the next page", func() {
+					request, err := http.NewRequest("", "http://example.com/v1/tasks?limit=2", nil)
+					Expect(err).NotTo(HaveOccurred())
+
+					handler.GetAll(responseRecorder, request)
+					Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+
+					var tasks []receptor.TaskResponse
+					err = json.Unmarshal(responseRecorder.Body.Bytes(), &tasks)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(tasks).To(Equal([]receptor.TaskResponse{
+						serialization.TaskToResponse(domain1Task),
+						serialization.TaskToResponse(domain2Task),
+					}))
+
+					pageToken := base64.URLEncoding.EncodeToString([]byte("task-guid-2"))
+					Expect(responseRecorder.Header().Get("Link")).To(Equal(
+						fmt.Sprintf(`</v1/tasks?limit=2&page_token=%s>; rel="next"`, url.QueryEscape(pageToken)),
+					))
+				})
+
+				It("returns the page following the page token without a link on the last page", func() {
+					pageToken := base64.URLEncoding.EncodeToString([]byte("task-guid-2"))
+					request, err := http.NewRequest("", "http://example.com/v1/tasks?limit=2&page_token="+url.QueryEscape(pageToken), nil)
+					Expect(err).NotTo(HaveOccurred())
+
+					handler.GetAll(responseRecorder, request)
+					Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+
+					var tasks []receptor.TaskResponse
+					err = json.Unmarshal(responseRecorder.Body.Bytes(), &tasks)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(tasks).To(Equal([]receptor.TaskResponse{
+						serialization.TaskToResponse(domain3Task),
+					}))
+					Expect(responseRecorder.Header().Get("Link")).To(BeEmpty())
+				})
+
+				Context("when the limit is invalid", func() {
+					It("responds with a 400 Bad Request", func() {
+						request, err := http.NewRequest("", "http://example.com/v1/tasks?limit=0", nil)
+						Expect(err).NotTo(HaveOccurred())
+
+						handler.GetAll(responseRecorder, request)
+						Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+
+						var receptorError receptor.Error
+						err = json.Unmarshal(responseRecorder.Body.Bytes(), &receptorError)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(receptorError.Type).To(Equal(receptor.InvalidRequest))
+					})
+				})
+
+				Context("when the page token is invalid", func() {
+					It("responds with a 400 Bad Request", func() {
+						request, err := http.NewRequest("", "http://example.com/v1/tasks?page_token=%25%25%25", nil)
+						Expect(err).NotTo(HaveOccurred())
+
+						handler.GetAll(responseRecorder, request)
+						Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+						Expect(fakeClient.TasksCallCount()).To(Equal(0))
+					})
+				})
+			})
 		})
 	})
 
@@ -401,6 +732,182 @@ var _ = Describe("TaskHandler", func() {
 		})
 	})
 
+	Describe("GetByGuid with wait", func() {
+		var wait string
+		var runningTask, completedTask *models.Task
+
+		BeforeEach(func() {
+			wait = "10s"
+
+			runningTask = model_helpers.NewValidTask("the-task-guid")
+			runningTask.State = models.Task_Running
+
+			completedTask = model_helpers.NewValidTask("the-task-guid")
+			completedTask.State = models.Task_Completed
+			completedTask.Result = "some-result"
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			request, err = http.NewRequest("", "http://example.com/?:task_guid=the-task-guid&wait="+wait, nil)
+			Expect(err).NotTo(HaveOccurred())
+			handler.GetByGuid(responseRecorder, request)
+		})
+
+		Context("when the task has already completed", func() {
+			BeforeEach(func() {
+				fakeClient.TaskByGuidReturns(completedTask, nil)
+			})
+
+			It("responds with the task immediately", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+
+				var actualTask receptor.TaskResponse
+				err := json.Unmarshal(responseRecorder.Body.Bytes(), &actualTask)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(actualTask).To(Equal(serialization.TaskToResponse(completedTask)))
+			})
+		})
+
+		Context("when the task completes while waiting", func() {
+			BeforeEach(func() {
+				fakeClient.TaskByGuidStub = func(guid string) (*models.Task, error) {
+					hub.Emit(receptor.NewTaskChangedEvent(
+						serialization.TaskToResponse(runningTask),
+						serialization.TaskToResponse(completedTask),
+					))
+					return runningTask, nil
+				}
+			})
+
+			It("responds with the completed task", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+
+				var actualTask receptor.TaskResponse
+				err := json.Unmarshal(responseRecorder.Body.Bytes(), &actualTask)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(actualTask).To(Equal(serialization.TaskToResponse(completedTask)))
+			})
+		})
+
+		Context("when the task is removed while waiting", func() {
+			BeforeEach(func() {
+				fakeClient.TaskByGuidStub = func(guid string) (*models.Task, error) {
+					hub.Emit(receptor.NewTaskRemovedEvent(serialization.TaskToResponse(runningTask)))
+					return runningTask, nil
+				}
+			})
+
+			It("responds with a 404 NOT FOUND", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		Context("when the wait expires", func() {
+			BeforeEach(func() {
+				wait = "10ms"
+				fakeClient.TaskByGuidReturns(runningTask, nil)
+			})
+
+			It("responds with the task as it stands", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+				Expect(fakeClient.TaskByGuidCallCount()).To(Equal(2))
+
+				var actualTask receptor.TaskResponse
+				err := json.Unmarshal(responseRecorder.Body.Bytes(), &actualTask)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(actualTask.State).To(Equal(receptor.TaskStateRunning))
+			})
+		})
+
+		Context("when the wait is not a duration", func() {
+			BeforeEach(func() {
+				wait = "forever"
+			})
+
+			It("responds with a 400 Bad Request", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(fakeClient.TaskByGuidCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("GetResult", func() {
+		var task *models.Task
+
+		BeforeEach(func() {
+			task = model_helpers.NewValidTask("the-task-guid")
+			task.State = models.Task_Completed
+			task.Result = "some-result"
+			fakeClient.TaskByGuidReturns(task, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			request, err = http.NewRequest("", "http://example.com/?:task_guid=the-task-guid", nil)
+			Expect(err).NotTo(HaveOccurred())
+			handler.GetResult(responseRecorder, request)
+		})
+
+		It("responds with the raw result", func() {
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+			Expect(responseRecorder.Header().Get("Content-Type")).To(Equal("application/octet-stream"))
+			Expect(responseRecorder.Body.String()).To(Equal("some-result"))
+		})
+
+		Context("when the task has not completed", func() {
+			BeforeEach(func() {
+				task.State = models.Task_Running
+				task.Result = ""
+			})
+
+			It("responds with 409 CONFLICT", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusConflict))
+
+				var taskError receptor.Error
+				err := json.Unmarshal(responseRecorder.Body.Bytes(), &taskError)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(taskError.Type).To(Equal(receptor.TaskNotCompleted))
+			})
+		})
+
+		Context("when the result is at the maximum size", func() {
+			BeforeEach(func() {
+				task.Result = strings.Repeat("x", 1024*1024)
+			})
+
+			It("responds with the raw result", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+				Expect(responseRecorder.Body.Len()).To(Equal(1024 * 1024))
+			})
+		})
+
+		Context("when the result is larger than the maximum size", func() {
+			BeforeEach(func() {
+				task.Result = strings.Repeat("x", 1024*1024+1)
+			})
+
+			It("responds with 500 INTERNAL SERVER ERROR", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusInternalServerError))
+
+				var taskError receptor.Error
+				err := json.Unmarshal(responseRecorder.Body.Bytes(), &taskError)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(taskError.Type).To(Equal(receptor.TaskResultTooLarge))
+			})
+		})
+
+		Context("when the task is not found", func() {
+			BeforeEach(func() {
+				fakeClient.TaskByGuidReturns(nil, models.ErrResourceNotFound)
+			})
+
+			It("responds with 404 NOT FOUND", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+
 	Describe("Delete", func() {
 		var resolvingErr error
 
@@ -484,6 +991,262 @@ var _ = Describe("TaskHandler", func() {
 		})
 	})
 
+	Describe("DeleteAll", func() {
+		var oldTask, newTask, runningTask *models.Task
+
+		BeforeEach(func() {
+			oldTask = model_helpers.NewValidTask("old-task")
+			oldTask.State = models.Task_Completed
+			oldTask.FirstCompletedAt = 100
+
+			newTask = model_helpers.NewValidTask("new-task")
+			newTask.State = models.Task_Completed
+			newTask.FirstCompletedAt = 300
+
+			runningTask = model_helpers.NewValidTask("running-task")
+			runningTask.State = models.Task_Running
+
+			fakeClient.TasksByDomainReturns([]*models.Task{oldTask, newTask, runningTask}, nil)
+		})
+
+		It("resolves and deletes the completed tasks in the domain", func() {
+			request, err := http.NewRequest("DELETE", "http://example.com?domain=some-domain", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.DeleteAll(responseRecorder, request)
+			Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+
+			Expect(fakeClient.TasksByDomainCallCount()).To(Equal(1))
+			Expect(fakeClient.TasksByDomainArgsForCall(0)).To(Equal("some-domain"))
+
+			Expect(fakeClient.ResolvingTaskCallCount()).To(Equal(2))
+			Expect(fakeClient.DeleteTaskCallCount()).To(Equal(2))
+
+			var response receptor.TasksDeleteResponse
+			err = json.Unmarshal(responseRecorder.Body.Bytes(), &response)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response.Deleted).To(Equal([]string{"old-task", "new-task"}))
+			Expect(response.Failed).To(BeEmpty())
+		})
+
+		Context("when completed_before is provided", func() {
+			It("only deletes tasks which completed before then", func() {
+				request, err := http.NewRequest("DELETE", "http://example.com?domain=some-domain&completed_before=200", nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				handler.DeleteAll(responseRecorder, request)
+				Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+
+				Expect(fakeClient.DeleteTaskCallCount()).To(Equal(1))
+				Expect(fakeClient.DeleteTaskArgsForCall(0)).To(Equal("old-task"))
+			})
+		})
+
+		Context("when some of the tasks fail to be deleted", func() {
+			BeforeEach(func() {
+				fakeClient.ResolvingTaskStub = func(guid string) error {
+					if guid == "new-task" {
+						return models.ErrResourceNotFound
+					}
+					return nil
+				}
+			})
+
+			It("reports the failures alongside the deleted tasks", func() {
+				request, err := http.NewRequest("DELETE", "http://example.com?domain=some-domain", nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				handler.DeleteAll(responseRecorder, request)
+				Expect(responseRecorder.Code).To(Equal(http.StatusOK))
+
+				var response receptor.TasksDeleteResponse
+				err = json.Unmarshal(responseRecorder.Body.Bytes(), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response.Deleted).To(Equal([]string{"old-task"}))
+				Expect(response.Failed).To(HaveLen(1))
+				Expect(response.Failed[0].TaskGuid).To(Equal("new-task"))
+				Expect(response.Failed[0].Error.Type).To(Equal(receptor.TaskNotFound))
+			})
+		})
+
+		Context("when the domain is missing", func() {
+			It("responds with a 400 without deleting anything", func() {
+				request, err := http.NewRequest("DELETE", "http://example.com", nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				handler.DeleteAll(responseRecorder, request)
+				Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(fakeClient.DeleteTaskCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when a state other than COMPLETED is requested", func() {
+			It("responds with a 400 without deleting anything", func() {
+				request, err := http.NewRequest("DELETE", "http://example.com?domain=some-domain&state=RUNNING", nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				handler.DeleteAll(responseRecorder, request)
+				Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(fakeClient.DeleteTaskCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when completed_before is invalid", func() {
+			It("responds with a 400", func() {
+				request, err := http.NewRequest("DELETE", "http://example.com?domain=some-domain&completed_before=yesterday", nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				handler.DeleteAll(responseRecorder, request)
+				Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		Context("when fetching the tasks fails", func() {
+			BeforeEach(func() {
+				fakeClient.TasksByDomainReturns(nil, errors.New("oops"))
+			})
+
+			It("responds with a 500", func() {
+				request, err := http.NewRequest("DELETE", "http://example.com?domain=some-domain", nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				handler.DeleteAll(responseRecorder, request)
+				Expect(responseRecorder.Code).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+
+	Describe("Retry", func() {
+		var failedTask *models.Task
+		var requestBody string
+
+		BeforeEach(func() {
+			failedTask = model_helpers.NewValidTask("failed-task")
+			failedTask.State = models.Task_Completed
+			failedTask.Failed = true
+			failedTask.FailureReason = "cell went away"
+
+			fakeClient.TaskByGuidReturns(failedTask, nil)
+			requestBody = ""
+		})
+
+		JustBeforeEach(func() {
+			request, err := http.NewRequest("POST", "http://example.com?:task_guid=failed-task", strings.NewReader(requestBody))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.Retry(responseRecorder, request)
+		})
+
+		Context("when no new guid is requested", func() {
+			It("resolves the failed task and recreates it under the same guid", func() {
+				Expect(fakeClient.ResolvingTaskCallCount()).To(Equal(1))
+				Expect(fakeClient.DeleteTaskCallCount()).To(Equal(1))
+				Expect(fakeClient.DeleteTaskArgsForCall(0)).To(Equal("failed-task"))
+
+				Expect(fakeClient.DesireTaskCallCount()).To(Equal(1))
+				guid, domain, def := fakeClient.DesireTaskArgsForCall(0)
+				Expect(guid).To(Equal("failed-task"))
+				Expect(domain).To(Equal(failedTask.Domain))
+				Expect(def).To(Equal(failedTask.TaskDefinition))
+			})
+
+			It("responds with the lineage of the new task", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusCreated))
+
+				var response receptor.TaskRetryResponse
+				err := json.Unmarshal(responseRecorder.Body.Bytes(), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response).To(Equal(receptor.TaskRetryResponse{
+					TaskGuid:              "failed-task",
+					RetryOf:               "failed-task",
+					PreviousFailureReason: "cell went away",
+				}))
+			})
+		})
+
+		Context("when a new guid is requested", func() {
+			BeforeEach(func() {
+				requestBody = `{"task_guid":"retried-task"}`
+			})
+
+			It("recreates the task under the new guid, leaving the failed task alone", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusCreated))
+				Expect(fakeClient.ResolvingTaskCallCount()).To(BeZero())
+
+				Expect(fakeClient.DesireTaskCallCount()).To(Equal(1))
+				guid, _, _ := fakeClient.DesireTaskArgsForCall(0)
+				Expect(guid).To(Equal("retried-task"))
+
+				var response receptor.TaskRetryResponse
+				err := json.Unmarshal(responseRecorder.Body.Bytes(), &response)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response.TaskGuid).To(Equal("retried-task"))
+				Expect(response.RetryOf).To(Equal("failed-task"))
+			})
+
+			Context("when the new guid is taken", func() {
+				BeforeEach(func() {
+					fakeClient.DesireTaskReturns(models.ErrResourceExists)
+				})
+
+				It("responds with a 409", func() {
+					Expect(responseRecorder.Code).To(Equal(http.StatusConflict))
+				})
+			})
+		})
+
+		Context("when the failed task would not pass validation", func() {
+			BeforeEach(func() {
+				failedTask.RootFs = ""
+			})
+
+			It("responds with a 400 without resolving the failed task", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(fakeClient.ResolvingTaskCallCount()).To(BeZero())
+				Expect(fakeClient.DesireTaskCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when the task has not failed", func() {
+			BeforeEach(func() {
+				failedTask.Failed = false
+			})
+
+			It("responds with a 409 without touching the task", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusConflict))
+
+				var receptorError receptor.Error
+				err := json.Unmarshal(responseRecorder.Body.Bytes(), &receptorError)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(receptorError.Type).To(Equal(receptor.TaskNotRetryable))
+
+				Expect(fakeClient.ResolvingTaskCallCount()).To(BeZero())
+				Expect(fakeClient.DesireTaskCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when the task does not exist", func() {
+			BeforeEach(func() {
+				fakeClient.TaskByGuidReturns(nil, models.ErrResourceNotFound)
+			})
+
+			It("responds with a 404", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		Context("when the request body is invalid", func() {
+			BeforeEach(func() {
+				requestBody = "{"
+			})
+
+			It("responds with a 400", func() {
+				Expect(responseRecorder.Code).To(Equal(http.StatusBadRequest))
+				Expect(fakeClient.DesireTaskCallCount()).To(BeZero())
+			})
+		})
+	})
+
 	Describe("Cancel", func() {
 		BeforeEach(func() {
 			var err error
@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/bbs/models"
+	"github.com/cloudfoundry-incubator/receptor"
+)
+
+const (
+	maxCPUWeight        = 100
+	maxAnnotationLength = 10 * 1024
+)
+
+var guidPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// fieldErrors accumulates the validation failures of a request, so that all
+// of them can be reported at once rather than one BBS round trip at a time.
+type fieldErrors []receptor.FieldError
+
+func (errs *fieldErrors) add(field, code, format string, args ...interface{}) {
+	*errs = append(*errs, receptor.FieldError{
+		Field:   field,
+		Code:    code,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func validateTaskRequest(req receptor.TaskCreateRequest) []receptor.FieldError {
+	errs := fieldErrors{}
+
+	errs.validateGuid("task_guid", req.TaskGuid)
+	errs.validateRequired("domain", req.Domain)
+	errs.validateRootFS(req.RootFS)
+	errs.validateAction("action", req.Action, true)
+	errs.validateResources(req.MemoryMB, req.DiskMB, req.CPUWeight)
+	errs.validateAnnotation(req.Annotation)
+	errs.validateEgressRules(req.EgressRules)
+
+	for i, envVar := range req.EnvironmentVariables {
+		if envVar == nil || envVar.Name == "" {
+			errs.add(fmt.Sprintf("env[%d].name", i), receptor.FieldRequired, "environment variable name is required")
+		}
+	}
+
+	if req.CompletionCallbackURL != "" {
+		if _, err := url.ParseRequestURI(req.CompletionCallbackURL); err != nil {
+			errs.add("completion_callback_url", receptor.FieldInvalid, "completion callback url is not a valid absolute URL")
+		}
+	}
+
+	return errs
+}
+
+func validateDesiredLRPRequest(req receptor.DesiredLRPCreateRequest) []receptor.FieldError {
+	errs := fieldErrors{}
+
+	errs.validateGuid("process_guid", req.ProcessGuid)
+	errs.validateRequired("domain", req.Domain)
+	errs.validateRootFS(req.RootFS)
+	errs.validateAction("setup", req.Setup, false)
+	errs.validateAction("action", req.Action, true)
+	errs.validateAction("monitor", req.Monitor, false)
+	errs.validateResources(req.MemoryMB, req.DiskMB, req.CPUWeight)
+	errs.validateAnnotation(req.Annotation)
+	errs.validateEgressRules(req.EgressRules)
+
+	if req.Instances < 0 {
+		errs.add("instances", receptor.FieldOutOfRange, "instances must not be negative")
+	}
+
+	for i, envVar := range req.EnvironmentVariables {
+		if envVar.Name == "" {
+			errs.add(fmt.Sprintf("env[%d].name", i), receptor.FieldRequired, "environment variable name is required")
+		}
+	}
+
+	seenPorts := map[uint16]bool{}
+	for i, port := range req.Ports {
+		field := fmt.Sprintf("ports[%d]", i)
+		switch {
+		case port == 0:
+			errs.add(field, receptor.FieldOutOfRange, "port must be between 1 and 65535")
+		case seenPorts[port]:
+			errs.add(field, receptor.FieldDuplicate, "port %d is listed more than once", port)
+		}
+		seenPorts[port] = true
+	}
+
+	return errs
+}
+
+func validateAutoscalingPolicy(policy receptor.AutoscalingPolicy) []receptor.FieldError {
+	errs := fieldErrors{}
+
+	if policy.MinInstances < 0 {
+		errs.add("min_instances", receptor.FieldOutOfRange, "min_instances must not be negative")
+	}
+	if policy.MaxInstances < policy.MinInstances {
+		errs.add("max_instances", receptor.FieldOutOfRange, "max_instances must not be less than min_instances")
+	}
+
+	for i, rule := range policy.Rules {
+		field := fmt.Sprintf("rules[%d]", i)
+		errs.validateRequired(field+".metric", rule.Metric)
+
+		switch rule.Comparison {
+		case receptor.AutoscalingComparisonAbove, receptor.AutoscalingComparisonBelow:
+		default:
+			errs.add(field+".comparison", receptor.FieldInvalid, "comparison must be %q or %q", receptor.AutoscalingComparisonAbove, receptor.AutoscalingComparisonBelow)
+		}
+
+		if rule.Adjustment == 0 {
+			errs.add(field+".adjustment", receptor.FieldInvalid, "adjustment must not be zero")
+		}
+	}
+
+	return errs
+}
+
+func (errs *fieldErrors) validateRequired(field, value string) {
+	if value == "" {
+		errs.add(field, receptor.FieldRequired, "%s is required", field)
+	}
+}
+
+func (errs *fieldErrors) validateGuid(field, guid string) {
+	if guid == "" {
+		errs.add(field, receptor.FieldRequired, "%s is required", field)
+	} else if !guidPattern.MatchString(guid) {
+		errs.add(field, receptor.FieldInvalid, "%s may only contain letters, digits, '-' and '_'", field)
+	}
+}
+
+func (errs *fieldErrors) validateRootFS(rootFS string) {
+	if rootFS == "" {
+		errs.add("rootfs", receptor.FieldRequired, "rootfs is required")
+		return
+	}
+
+	rootFSURL, err := url.Parse(rootFS)
+	if err != nil || rootFSURL.Scheme == "" {
+		errs.add("rootfs", receptor.FieldInvalid, "rootfs must be a URL with a scheme, e.g. preloaded:stack or docker:///image")
+	}
+}
+
+func (errs *fieldErrors) validateAction(field string, action *models.Action, required bool) {
+	if action == nil {
+		if required {
+			errs.add(field, receptor.FieldRequired, "%s is required", field)
+		}
+		return
+	}
+
+	if err := action.Validate(); err != nil {
+		errs.add(field, receptor.FieldInvalid, "%s", err.Error())
+	}
+}
+
+func (errs *fieldErrors) validateResources(memoryMB, diskMB int, cpuWeight uint) {
+	if memoryMB < 0 {
+		errs.add("memory_mb", receptor.FieldOutOfRange, "memory_mb must not be negative")
+	}
+	if diskMB < 0 {
+		errs.add("disk_mb", receptor.FieldOutOfRange, "disk_mb must not be negative")
+	}
+	if cpuWeight > maxCPUWeight {
+		errs.add("cpu_weight", receptor.FieldOutOfRange, "cpu_weight must not exceed %d", maxCPUWeight)
+	}
+}
+
+func (errs *fieldErrors) validateAnnotation(annotation string) {
+	if len(annotation) > maxAnnotationLength {
+		errs.add("annotation", receptor.FieldOutOfRange, "annotation must not exceed %d bytes", maxAnnotationLength)
+	}
+}
+
+func (errs *fieldErrors) validateEgressRules(rules []*models.SecurityGroupRule) {
+	for i, rule := range rules {
+		field := fmt.Sprintf("egress_rules[%d]", i)
+		if rule == nil {
+			errs.add(field, receptor.FieldRequired, "egress rule must not be null")
+			continue
+		}
+		if err := rule.Validate(); err != nil {
+			errs.add(field, receptor.FieldInvalid, "%s", err.Error())
+		}
+	}
+}
+
+// validationError reports the field errors of an invalid request. The message
+// repeats them for clients which only look at the name and message.
+func validationError(errorType string, errs []receptor.FieldError) receptor.Error {
+	messages := make([]string, len(errs))
+	for i, fieldErr := range errs {
+		messages[i] = fieldErr.Message
+	}
+
+	return receptor.Error{
+		Type:        errorType,
+		Message:     strings.Join(messages, "; "),
+		FieldErrors: errs,
+	}
+}
+
+// dryRunFromRequest reports whether the request only asks for validation.
+func dryRunFromRequest(req *http.Request) (bool, error) {
+	dryRun := req.FormValue("dry_run")
+	if dryRun == "" {
+		return false, nil
+	}
+
+	value, err := strconv.ParseBool(dryRun)
+	if err != nil {
+		return false, fmt.Errorf("invalid dry_run: %s", dryRun)
+	}
+	return value, nil
+}
+
+func writeValidationResponse(w http.ResponseWriter, errs []receptor.FieldError) {
+	writeJSONResponse(w, http.StatusOK, receptor.ValidationResponse{
+		Valid:  len(errs) == 0,
+		Errors: errs,
+	})
+}
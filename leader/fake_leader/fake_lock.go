@@ -0,0 +1,85 @@
+// This file was generated by counterfeiter
+package fake_leader
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry-incubator/receptor/leader"
+)
+
+type FakeLock struct {
+	LockStub        func(stopCh <-chan struct{}) (<-chan struct{}, error)
+	lockMutex       sync.RWMutex
+	lockArgsForCall []struct {
+		stopCh <-chan struct{}
+	}
+	lockReturns struct {
+		result1 <-chan struct{}
+		result2 error
+	}
+	UnlockStub        func() error
+	unlockMutex       sync.RWMutex
+	unlockArgsForCall []struct{}
+	unlockReturns     struct {
+		result1 error
+	}
+}
+
+func (fake *FakeLock) Lock(stopCh <-chan struct{}) (<-chan struct{}, error) {
+	fake.lockMutex.Lock()
+	fake.lockArgsForCall = append(fake.lockArgsForCall, struct {
+		stopCh <-chan struct{}
+	}{stopCh})
+	fake.lockMutex.Unlock()
+	if fake.LockStub != nil {
+		return fake.LockStub(stopCh)
+	} else {
+		return fake.lockReturns.result1, fake.lockReturns.result2
+	}
+}
+
+func (fake *FakeLock) LockCallCount() int {
+	fake.lockMutex.RLock()
+	defer fake.lockMutex.RUnlock()
+	return len(fake.lockArgsForCall)
+}
+
+func (fake *FakeLock) LockArgsForCall(i int) <-chan struct{} {
+	fake.lockMutex.RLock()
+	defer fake.lockMutex.RUnlock()
+	return fake.lockArgsForCall[i].stopCh
+}
+
+func (fake *FakeLock) LockReturns(result1 <-chan struct{}, result2 error) {
+	fake.LockStub = nil
+	fake.lockReturns = struct {
+		result1 <-chan struct{}
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeLock) Unlock() error {
+	fake.unlockMutex.Lock()
+	fake.unlockArgsForCall = append(fake.unlockArgsForCall, struct{}{})
+	fake.unlockMutex.Unlock()
+	if fake.UnlockStub != nil {
+		return fake.UnlockStub()
+	} else {
+		return fake.unlockReturns.result1
+	}
+}
+
+func (fake *FakeLock) UnlockCallCount() int {
+	fake.unlockMutex.RLock()
+	defer fake.unlockMutex.RUnlock()
+	return len(fake.unlockArgsForCall)
+}
+
+func (fake *FakeLock) UnlockReturns(result1 error) {
+	fake.UnlockStub = nil
+	fake.unlockReturns = struct {
+		result1 error
+	}{result1}
+}
+
+var _ leader.Lock = new(FakeLock)
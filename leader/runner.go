@@ -0,0 +1,127 @@
+package leader
+
+import (
+	"os"
+	"time"
+
+	"github.com/pivotal-golang/clock"
+	"github.com/pivotal-golang/lager"
+	"github.com/tedsuo/ifrit"
+)
+
+const DefaultRetryInterval = 5 * time.Second
+
+//go:generate counterfeiter -o fake_leader/fake_lock.go . Lock
+
+// Lock is held by at most one receptor at a time. The consul api's *Lock
+// satisfies it.
+type Lock interface {
+	// Lock blocks until the lock is acquired, or until stopCh is closed in
+	// which case it returns a nil channel. The returned channel is closed
+	// when the lock is lost, after which Unlock must still be called before
+	// it can be acquired again.
+	Lock(stopCh <-chan struct{}) (<-chan struct{}, error)
+	Unlock() error
+}
+
+type runner struct {
+	lock          Lock
+	runner        ifrit.Runner
+	clock         clock.Clock
+	retryInterval time.Duration
+	logger        lager.Logger
+}
+
+// NewRunner returns a runner which only runs runner while it holds lock, so
+// that a single receptor runs it at a time. When the lock is lost, runner is
+// interrupted until the lock is acquired again.
+func NewRunner(lock Lock, r ifrit.Runner, clock clock.Clock, retryInterval time.Duration, logger lager.Logger) ifrit.Runner {
+	return &runner{
+		lock:          lock,
+		runner:        r,
+		clock:         clock,
+		retryInterval: retryInterval,
+		logger:        logger.Session("leader"),
+	}
+}
+
+func (r *runner) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	close(ready)
+
+	for {
+		lost, signalled := r.acquire(signals)
+		if signalled {
+			return nil
+		}
+
+		r.logger.Info("acquired-lock")
+		process := ifrit.Background(r.runner)
+
+		select {
+		case signal := <-signals:
+			process.Signal(signal)
+			err := <-process.Wait()
+			r.unlock()
+			return err
+
+		case err := <-process.Wait():
+			r.unlock()
+			return err
+
+		case <-lost:
+			r.logger.Info("lost-lock")
+			process.Signal(os.Interrupt)
+			<-process.Wait()
+			r.unlock()
+		}
+	}
+}
+
+type lockResult struct {
+	lost <-chan struct{}
+	err  error
+}
+
+// acquire blocks until the lock is acquired, retrying when acquiring it
+// fails, and reports whether it was signalled first.
+func (r *runner) acquire(signals <-chan os.Signal) (<-chan struct{}, bool) {
+	for {
+		stop := make(chan struct{})
+		results := make(chan lockResult, 1)
+		go func() {
+			lost, err := r.lock.Lock(stop)
+			results <- lockResult{lost: lost, err: err}
+		}()
+
+		var result lockResult
+		select {
+		case <-signals:
+			close(stop)
+			if result = <-results; result.err == nil && result.lost != nil {
+				r.unlock()
+			}
+			return nil, true
+		case result = <-results:
+		}
+
+		if result.err == nil {
+			return result.lost, false
+		}
+
+		r.logger.Error("failed-to-acquire-lock", result.err)
+		timer := r.clock.NewTimer(r.retryInterval)
+		select {
+		case <-signals:
+			timer.Stop()
+			return nil, true
+		case <-timer.C():
+		}
+	}
+}
+
+func (r *runner) unlock() {
+	err := r.lock.Unlock()
+	if err != nil {
+		r.logger.Error("failed-to-release-lock", err)
+	}
+}
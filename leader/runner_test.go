@@ -0,0 +1,146 @@
+package leader_test
+
+import (
+	"errors"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/cloudfoundry-incubator/receptor/leader"
+	"github.com/cloudfoundry-incubator/receptor/leader/fake_leader"
+	"github.com/pivotal-golang/clock/fakeclock"
+	"github.com/pivotal-golang/lager/lagertest"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/ginkgomon"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Runner", func() {
+	const retryInterval = 5 * time.Second
+
+	var (
+		fakeLock  *fake_leader.FakeLock
+		fakeClock *fakeclock.FakeClock
+
+		acquired chan (<-chan struct{})
+		lost     chan struct{}
+
+		runs        int32
+		interrupted int32
+		running     chan struct{}
+
+		process ifrit.Process
+	)
+
+	BeforeEach(func() {
+		fakeLock = new(fake_leader.FakeLock)
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+
+		acquired = make(chan (<-chan struct{}), 1)
+		lost = make(chan struct{})
+		fakeLock.LockStub = func(stopCh <-chan struct{}) (<-chan struct{}, error) {
+			select {
+			case lost := <-acquired:
+				return lost, nil
+			case <-stopCh:
+				return nil, nil
+			}
+		}
+
+		runs, interrupted = 0, 0
+		running = make(chan struct{}, 10)
+	})
+
+	JustBeforeEach(func() {
+		inner := ifrit.RunFunc(func(signals <-chan os.Signal, ready chan<- struct{}) error {
+			atomic.AddInt32(&runs, 1)
+			close(ready)
+			running <- struct{}{}
+			<-signals
+			atomic.AddInt32(&interrupted, 1)
+			return nil
+		})
+
+		process = ginkgomon.Invoke(leader.NewRunner(fakeLock, inner, fakeClock, retryInterval, lagertest.NewTestLogger("test")))
+	})
+
+	AfterEach(func() {
+		ginkgomon.Interrupt(process)
+	})
+
+	runCount := func() int32 { return atomic.LoadInt32(&runs) }
+	interruptCount := func() int32 { return atomic.LoadInt32(&interrupted) }
+
+	It("does not run the runner until the lock is acquired", func() {
+		Eventually(fakeLock.LockCallCount).Should(Equal(1))
+		Consistently(runCount).Should(BeZero())
+	})
+
+	Context("when the lock is acquired", func() {
+		JustBeforeEach(func() {
+			acquired <- lost
+			Eventually(running).Should(Receive())
+		})
+
+		It("runs the runner", func() {
+			Expect(runCount()).To(BeEquivalentTo(1))
+		})
+
+		Context("when signalled", func() {
+			JustBeforeEach(func() {
+				ginkgomon.Interrupt(process)
+			})
+
+			It("interrupts the runner and releases the lock", func() {
+				Expect(interruptCount()).To(BeEquivalentTo(1))
+				Expect(fakeLock.UnlockCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when the lock is lost", func() {
+			JustBeforeEach(func() {
+				close(lost)
+			})
+
+			It("interrupts the runner and releases the lock until it is acquired again", func() {
+				Eventually(interruptCount).Should(BeEquivalentTo(1))
+				Eventually(fakeLock.LockCallCount).Should(Equal(2))
+				Expect(fakeLock.UnlockCallCount()).To(Equal(1))
+				Consistently(runCount).Should(BeEquivalentTo(1))
+
+				acquired <- make(chan struct{})
+				Eventually(running).Should(Receive())
+				Expect(runCount()).To(BeEquivalentTo(2))
+			})
+		})
+	})
+
+	Context("when signalled while acquiring the lock", func() {
+		It("stops acquiring it and exits", func() {
+			Eventually(fakeLock.LockCallCount).Should(Equal(1))
+			ginkgomon.Interrupt(process)
+
+			Expect(fakeLock.LockArgsForCall(0)).To(BeClosed())
+			Expect(runCount()).To(BeZero())
+			Expect(fakeLock.UnlockCallCount()).To(BeZero())
+		})
+	})
+
+	Context("when acquiring the lock fails", func() {
+		BeforeEach(func() {
+			fakeLock.LockReturns(nil, errors.New("oops"))
+		})
+
+		It("tries again after the retry interval", func() {
+			Eventually(fakeLock.LockCallCount).Should(Equal(1))
+			Eventually(fakeClock.WatcherCount).Should(Equal(1))
+			Consistently(fakeLock.LockCallCount).Should(Equal(1))
+
+			fakeClock.Increment(retryInterval)
+			Eventually(fakeLock.LockCallCount).Should(Equal(2))
+			Expect(runCount()).To(BeZero())
+		})
+	})
+})
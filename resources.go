@@ -1,6 +1,11 @@
 package receptor
 
-import "github.com/cloudfoundry-incubator/bbs/models"
+import (
+	"fmt"
+	"time"
+
+	"github.com/cloudfoundry-incubator/bbs/models"
+)
 
 const AuthorizationCookieName = "receptor_authorization"
 
@@ -22,6 +27,47 @@ const (
 	TaskStateResolving = "RESOLVING"
 )
 
+const (
+	CallbackDeliveryPending   = "PENDING"
+	CallbackDeliveryDelivered = "DELIVERED"
+	CallbackDeliveryFailed    = "FAILED"
+)
+
+// CallbackDeliveryResponse reports the progress of the receptor's delivery of
+// a task's completion callback.
+type CallbackDeliveryResponse struct {
+	TaskGuid      string `json:"task_guid"`
+	URL           string `json:"url"`
+	State         string `json:"state"`
+	Attempts      int    `json:"attempts"`
+	LastError     string `json:"last_error,omitempty"`
+	LastAttemptAt int64  `json:"last_attempt_at"`
+}
+
+// TaskFilter selects the tasks returned by GET /v1/tasks. Empty fields match
+// every task. OmitResult leaves the Result out of the returned tasks; it can
+// be fetched separately with GET /v1/tasks/:task_guid/result.
+// CompletedBefore, in nanoseconds since the epoch, matches only tasks which
+// completed before that time.
+type TaskFilter struct {
+	Domain          string
+	State           string
+	CellID          string
+	CompletedBefore int64
+	OmitResult      bool
+}
+
+// TasksDeleteResponse summarizes the outcome of DELETE /v1/tasks.
+type TasksDeleteResponse struct {
+	Deleted []string            `json:"deleted"`
+	Failed  []TaskDeleteFailure `json:"failed"`
+}
+
+type TaskDeleteFailure struct {
+	TaskGuid string `json:"task_guid"`
+	Error    Error  `json:"error"`
+}
+
 type TaskCreateRequest struct {
 	Action                *models.Action                `json:"action"`
 	Annotation            string                        `json:"annotation,omitempty"`
@@ -41,6 +87,35 @@ type TaskCreateRequest struct {
 	EgressRules           []*models.SecurityGroupRule   `json:"egress_rules,omitempty"`
 }
 
+// ValidationResponse is returned by creation endpoints called with
+// dry_run=true. Nothing is persisted by a dry run.
+type ValidationResponse struct {
+	Valid  bool         `json:"valid"`
+	Errors []FieldError `json:"errors"`
+}
+
+// TaskRetryRequest is the optional body of POST /v1/tasks/:task_guid/retry.
+// When TaskGuid is empty the failed task is resolved and recreated under its
+// own guid.
+type TaskRetryRequest struct {
+	TaskGuid string `json:"task_guid,omitempty"`
+}
+
+// TaskRetryResponse records the lineage of a retried task.
+type TaskRetryResponse struct {
+	TaskGuid              string `json:"task_guid"`
+	RetryOf               string `json:"retry_of"`
+	PreviousFailureReason string `json:"previous_failure_reason"`
+}
+
+// TaskCreateResult reports the outcome of creating one of the tasks submitted
+// to the bulk task creation endpoint.
+type TaskCreateResult struct {
+	TaskGuid string `json:"task_guid"`
+	Status   int    `json:"status"`
+	Error    *Error `json:"error,omitempty"`
+}
+
 type TaskResponse struct {
 	Action                *models.Action                `json:"action"`
 	Annotation            string                        `json:"annotation,omitempty"`
@@ -97,6 +172,80 @@ type DesiredLRPUpdateRequest struct {
 	Annotation *string     `json:"annotation,omitempty"`
 }
 
+const (
+	AutoscalingMetricCrashRate = "crash_rate"
+
+	AutoscalingComparisonAbove = "above"
+	AutoscalingComparisonBelow = "below"
+)
+
+// AutoscalingPolicy keeps the instance count of a DesiredLRP between
+// MinInstances and MaxInstances. The first rule whose metric is above or
+// below its threshold adjusts the instance count by its Adjustment.
+type AutoscalingPolicy struct {
+	ProcessGuid  string            `json:"process_guid"`
+	MinInstances int               `json:"min_instances"`
+	MaxInstances int               `json:"max_instances"`
+	Rules        []AutoscalingRule `json:"rules"`
+}
+
+type AutoscalingRule struct {
+	Metric     string  `json:"metric"`
+	Comparison string  `json:"comparison"`
+	Threshold  float64 `json:"threshold"`
+	Adjustment int     `json:"adjustment"`
+}
+
+// DesiredLRPsScaleRequest sets the instance count of every DesiredLRP in a
+// domain.
+type DesiredLRPsScaleRequest struct {
+	Instances int `json:"instances"`
+}
+
+// DesiredLRPsBulkResponse summarizes the outcome of a bulk action on the
+// DesiredLRPs of a domain.
+type DesiredLRPsBulkResponse struct {
+	Succeeded []string                `json:"succeeded"`
+	Failed    []DesiredLRPBulkFailure `json:"failed"`
+}
+
+type DesiredLRPBulkFailure struct {
+	ProcessGuid string `json:"process_guid"`
+	Error       Error  `json:"error"`
+}
+
+const (
+	RollingUpdateStateInProgress = "IN_PROGRESS"
+	RollingUpdateStateCompleted  = "COMPLETED"
+	RollingUpdateStateAborted    = "ABORTED"
+	RollingUpdateStateFailed     = "FAILED"
+)
+
+// RollingUpdateRequest replaces a DesiredLRP with Successor, moving BatchSize
+// instances at a time. The successor's process guid is generated when left
+// empty, and its instance count is taken from the DesiredLRP it replaces.
+type RollingUpdateRequest struct {
+	Successor DesiredLRPCreateRequest `json:"successor"`
+	BatchSize int                     `json:"batch_size"`
+}
+
+// RollingUpdateResponse reports the progress of a rolling update. Instances
+// is the number of instances being moved; SuccessorInstances and
+// PredecessorInstances are the instances currently desired of each LRP.
+type RollingUpdateResponse struct {
+	ProcessGuid          string `json:"process_guid"`
+	SuccessorProcessGuid string `json:"successor_process_guid"`
+	State                string `json:"state"`
+	Instances            int    `json:"instances"`
+	BatchSize            int    `json:"batch_size"`
+	SuccessorInstances   int    `json:"successor_instances"`
+	SuccessorRunning     int    `json:"successor_running"`
+	PredecessorInstances int    `json:"predecessor_instances"`
+	Error                string `json:"error,omitempty"`
+	StartedAt            int64  `json:"started_at"`
+	UpdatedAt            int64  `json:"updated_at"`
+}
+
 type DesiredLRPResponse struct {
 	ProcessGuid          string                      `json:"process_guid"`
 	Domain               string                      `json:"domain"`
@@ -121,6 +270,16 @@ type DesiredLRPResponse struct {
 	ModificationTag      ModificationTag             `json:"modification_tag"`
 }
 
+// DesiredLRPSummaryResponse is the projection of a DesiredLRP returned when
+// requesting view=summary, omitting the action trees and environment.
+type DesiredLRPSummaryResponse struct {
+	ProcessGuid     string          `json:"process_guid"`
+	Domain          string          `json:"domain"`
+	Instances       int             `json:"instances"`
+	Routes          RoutingInfo     `json:"routes,omitempty"`
+	ModificationTag ModificationTag `json:"modification_tag"`
+}
+
 type ActualLRPState string
 
 const (
@@ -148,6 +307,58 @@ type ActualLRPResponse struct {
 	ModificationTag ModificationTag `json:"modification_tag"`
 }
 
+// ActualLRPGroupResponse holds both ActualLRPs of an index. Evacuating is
+// only present while the index is being moved off an evacuating cell, and
+// Instance may be absent until the index is placed again.
+type ActualLRPGroupResponse struct {
+	Instance   *ActualLRPResponse `json:"instance,omitempty"`
+	Evacuating *ActualLRPResponse `json:"evacuating,omitempty"`
+}
+
+// ActualLRPFilter selects the ActualLRPs returned by GET /v1/actual_lrps.
+// Empty fields match every ActualLRP. CellID, State and Zone are all matched
+// against the ActualLRP which is returned for an index, so an index whose
+// evacuating and replacement ActualLRPs are on different cells only matches
+// the cell of the one returned.
+type ActualLRPFilter struct {
+	Domain string
+	CellID string
+	State  ActualLRPState
+	Zone   string
+}
+
+// ActualLRPsKillResponse summarizes the outcome of killing every ActualLRP of
+// a process.
+type ActualLRPsKillResponse struct {
+	Killed []int                  `json:"killed"`
+	Failed []ActualLRPKillFailure `json:"failed"`
+}
+
+type ActualLRPKillFailure struct {
+	Index int   `json:"index"`
+	Error Error `json:"error"`
+}
+
+// ActualLRPCrashResponse records a single crash of an ActualLRP, as seen by
+// the receptor serving it. CrashedAt is in nanoseconds since the epoch.
+type ActualLRPCrashResponse struct {
+	InstanceGuid string `json:"instance_guid"`
+	CellID       string `json:"cell_id"`
+	CrashCount   int    `json:"crash_count"`
+	CrashReason  string `json:"crash_reason,omitempty"`
+	CrashedAt    int64  `json:"crashed_at"`
+}
+
+// CrashingActualLRPResponse identifies an ActualLRP in a crash loop, with the
+// number of crashes that put it there.
+type CrashingActualLRPResponse struct {
+	ProcessGuid   string                 `json:"process_guid"`
+	Index         int                    `json:"index"`
+	Domain        string                 `json:"domain"`
+	RecentCrashes int                    `json:"recent_crashes"`
+	LastCrash     ActualLRPCrashResponse `json:"last_crash"`
+}
+
 type ModificationTag struct {
 	Epoch string `json:"epoch"`
 	Index uint   `json:"index"`
@@ -169,6 +380,12 @@ func (m *ModificationTag) SucceededBy(other ModificationTag) bool {
 	return m.Epoch != other.Epoch || m.Index < other.Index
 }
 
+// ETag returns the entity tag identifying this version of a resource, as
+// sent in ETag and If-Match headers.
+func (m *ModificationTag) ETag() string {
+	return fmt.Sprintf(`"%s:%d"`, m.Epoch, m.Index)
+}
+
 type CellResponse struct {
 	CellID          string              `json:"cell_id"`
 	Zone            string              `json:"zone"`
@@ -198,8 +415,20 @@ const (
 	EventTypeActualLRPCreated  EventType = "actual_lrp_created"
 	EventTypeActualLRPChanged  EventType = "actual_lrp_changed"
 	EventTypeActualLRPRemoved  EventType = "actual_lrp_removed"
+	EventTypeTaskCreated       EventType = "task_created"
+	EventTypeTaskChanged       EventType = "task_changed"
+	EventTypeTaskRemoved       EventType = "task_removed"
+
+	EventTypeResyncRequired EventType = "resync_required"
+	EventTypeHeartbeat      EventType = "heartbeat"
 )
 
+type EventFilter struct {
+	Types       []EventType
+	Domain      string
+	ProcessGuid string
+}
+
 type DesiredLRPCreatedEvent struct {
 	DesiredLRPResponse DesiredLRPResponse `json:"desired_lrp"`
 }
@@ -282,6 +511,78 @@ func NewActualLRPRemovedEvent(actualLRP ActualLRPResponse) ActualLRPRemovedEvent
 func (ActualLRPRemovedEvent) EventType() EventType { return EventTypeActualLRPRemoved }
 func (e ActualLRPRemovedEvent) Key() string        { return e.ActualLRPResponse.InstanceGuid }
 
+type TaskCreatedEvent struct {
+	TaskResponse TaskResponse `json:"task"`
+}
+
+func NewTaskCreatedEvent(task TaskResponse) TaskCreatedEvent {
+	return TaskCreatedEvent{
+		TaskResponse: task,
+	}
+}
+
+func (TaskCreatedEvent) EventType() EventType { return EventTypeTaskCreated }
+func (e TaskCreatedEvent) Key() string        { return e.TaskResponse.TaskGuid }
+
+type TaskChangedEvent struct {
+	Before TaskResponse `json:"task_before"`
+	After  TaskResponse `json:"task_after"`
+}
+
+func NewTaskChangedEvent(before, after TaskResponse) TaskChangedEvent {
+	return TaskChangedEvent{
+		Before: before,
+		After:  after,
+	}
+}
+
+func (TaskChangedEvent) EventType() EventType { return EventTypeTaskChanged }
+func (e TaskChangedEvent) Key() string        { return e.Before.TaskGuid }
+
+type TaskRemovedEvent struct {
+	TaskResponse TaskResponse `json:"task"`
+}
+
+func NewTaskRemovedEvent(task TaskResponse) TaskRemovedEvent {
+	return TaskRemovedEvent{
+		TaskResponse: task,
+	}
+}
+
+func (TaskRemovedEvent) EventType() EventType { return EventTypeTaskRemoved }
+func (e TaskRemovedEvent) Key() string        { return e.TaskResponse.TaskGuid }
+
+// ResyncRequiredEvent is emitted when the receptor can no longer replay the
+// events a subscriber missed. Subscribers should refetch any state they derive
+// from the event stream.
+type ResyncRequiredEvent struct{}
+
+func NewResyncRequiredEvent() ResyncRequiredEvent {
+	return ResyncRequiredEvent{}
+}
+
+func (ResyncRequiredEvent) EventType() EventType { return EventTypeResyncRequired }
+func (ResyncRequiredEvent) Key() string          { return "" }
+
+// HeartbeatEvent is sent periodically on otherwise idle event streams. It is
+// consumed by EventSource and never returned from Next.
+type HeartbeatEvent struct {
+	IntervalMS int64 `json:"interval_ms"`
+}
+
+func NewHeartbeatEvent(interval time.Duration) HeartbeatEvent {
+	return HeartbeatEvent{
+		IntervalMS: int64(interval / time.Millisecond),
+	}
+}
+
+func (HeartbeatEvent) EventType() EventType { return EventTypeHeartbeat }
+func (HeartbeatEvent) Key() string          { return "" }
+
+func (e HeartbeatEvent) Interval() time.Duration {
+	return time.Duration(e.IntervalMS) * time.Millisecond
+}
+
 type VersionResponse struct {
 	CFRelease           string `json:"cf_release,omitempty"`
 	CFRoutingRelease    string `json:"cf_routing_release,omitempty"`
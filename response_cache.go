@@ -0,0 +1,70 @@
+package receptor
+
+import (
+	"net/http"
+	"sync"
+)
+
+const (
+	maxCachedResponses     = 256
+	maxCachedBytes         = 8 * 1024 * 1024
+	maxCachedResponseBytes = 1024 * 1024
+)
+
+type cachedResponse struct {
+	etag   string
+	header http.Header
+	body   []byte
+}
+
+// responseCache retains the bodies of GET responses which carried an ETag, so
+// that requests for the same URL can be revalidated with If-None-Match. It
+// holds at most maxCachedResponses responses and maxCachedBytes of bodies, and
+// does not retain bodies larger than maxCachedResponseBytes.
+type responseCache struct {
+	lock      sync.Mutex
+	responses map[string]cachedResponse
+	bytes     int
+}
+
+func newResponseCache() *responseCache {
+	return &responseCache{
+		responses: make(map[string]cachedResponse),
+	}
+}
+
+func (c *responseCache) get(url string) (cachedResponse, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	response, ok := c.responses[url]
+	return response, ok
+}
+
+func (c *responseCache) put(url string, response cachedResponse) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.remove(url)
+	if len(response.body) > maxCachedResponseBytes {
+		return
+	}
+
+	for evicted := range c.responses {
+		if len(c.responses) < maxCachedResponses && c.bytes+len(response.body) <= maxCachedBytes {
+			break
+		}
+		c.remove(evicted)
+	}
+
+	c.responses[url] = response
+	c.bytes += len(response.body)
+}
+
+// remove must be called with the lock held.
+func (c *responseCache) remove(url string) {
+	if response, ok := c.responses[url]; ok {
+		c.bytes -= len(response.body)
+		delete(c.responses, url)
+	}
+}
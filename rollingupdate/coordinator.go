@@ -0,0 +1,517 @@
+package rollingupdate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/cloudfoundry-incubator/bbs"
+	"github.com/cloudfoundry-incubator/bbs/models"
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/event"
+	"github.com/cloudfoundry-incubator/receptor/serialization"
+	"github.com/cloudfoundry-incubator/receptor/store"
+	"github.com/pivotal-golang/clock"
+	"github.com/pivotal-golang/lager"
+	"github.com/tedsuo/ifrit"
+)
+
+const (
+	DefaultBatchTimeout = 10 * time.Minute
+	DefaultPollInterval = 5 * time.Second
+)
+
+var (
+	ErrInProgress    = errors.New("a rolling update of this desired LRP is already in progress")
+	ErrNotFound      = errors.New("no rolling update of this desired LRP was found")
+	ErrNotInProgress = errors.New("the rolling update of this desired LRP is no longer in progress")
+
+	errAborted     = errors.New("aborted")
+	errInterrupted = errors.New("interrupted by receptor shutdown")
+)
+
+type Config struct {
+	// BatchTimeout bounds how long a batch of successor instances may take
+	// to start running before the update is rolled back.
+	BatchTimeout time.Duration
+
+	// PollInterval is how often running instances are recounted, in case the
+	// events which would otherwise prompt a recount are missed.
+	PollInterval time.Duration
+}
+
+//go:generate counterfeiter -o fake_rollingupdate/fake_coordinator.go . Coordinator
+
+// Coordinator replaces desired LRPs with successors, moving their instances a
+// batch at a time. Each batch of successor instances must be running before
+// the predecessor is scaled down by the same amount, so the total number of
+// running instances never drops. Failed and aborted updates are rolled back.
+//
+// Updates are kept in a store shared by every receptor, so that any of them
+// can start, report and abort an update. Only one receptor may run the
+// Coordinator at a time, and it drives every update in progress, including
+// those interrupted when another receptor stopped running it.
+type Coordinator interface {
+	ifrit.Runner
+
+	// Start returns the successor's resolved guid along with the error when
+	// the successor can not be desired.
+	Start(processGuid string, request receptor.RollingUpdateRequest) (receptor.RollingUpdateResponse, error)
+	Get(processGuid string) (receptor.RollingUpdateResponse, error)
+	Abort(processGuid string) (receptor.RollingUpdateResponse, error)
+}
+
+type coordinator struct {
+	bbs    bbs.Client
+	hub    event.Hub
+	store  store.Store
+	clock  clock.Clock
+	config Config
+	logger lager.Logger
+
+	// started wakes the running Coordinator when an update is started on
+	// this receptor, rather than waiting for the next PollInterval
+	started chan struct{}
+
+	lock    sync.Mutex
+	driving map[string]chan struct{}
+}
+
+func NewCoordinator(bbs bbs.Client, hub event.Hub, store store.Store, clock clock.Clock, config Config, logger lager.Logger) Coordinator {
+	return &coordinator{
+		bbs:     bbs,
+		hub:     hub,
+		store:   store,
+		clock:   clock,
+		config:  config,
+		logger:  logger.Session("rolling-update-coordinator"),
+		started: make(chan struct{}, 1),
+		driving: make(map[string]chan struct{}),
+	}
+}
+
+// Run drives the updates in progress, picking up new ones every PollInterval.
+// When signalled, it interrupts them and leaves them in progress, for the
+// next receptor to run the Coordinator to resume.
+func (c *coordinator) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	logger := c.logger.Session("run")
+	stop := make(chan struct{})
+	drivers := &sync.WaitGroup{}
+
+	close(ready)
+
+	polls := c.clock.NewTicker(c.config.PollInterval)
+	defer polls.Stop()
+
+	for {
+		c.driveInProgress(logger, stop, drivers)
+
+		select {
+		case <-signals:
+			close(stop)
+			drivers.Wait()
+			return nil
+		case <-c.started:
+		case <-polls.C():
+		}
+	}
+}
+
+func (c *coordinator) Start(processGuid string, request receptor.RollingUpdateRequest) (receptor.RollingUpdateResponse, error) {
+	logger := c.logger.Session("start", lager.Data{"process-guid": processGuid})
+
+	previous, index, err := c.getRecord(processGuid)
+	if err != nil && err != ErrNotFound {
+		logger.Error("failed-to-fetch-update", err)
+		return receptor.RollingUpdateResponse{}, err
+	}
+	if err == nil && previous.inProgress() {
+		return receptor.RollingUpdateResponse{}, ErrInProgress
+	}
+
+	predecessor, err := c.bbs.DesiredLRPByProcessGuid(processGuid)
+	if err != nil {
+		logger.Error("failed-to-fetch-desired-lrp", err)
+		return receptor.RollingUpdateResponse{}, err
+	}
+
+	now := c.clock.Now()
+	successorRequest := request.Successor
+	if successorRequest.ProcessGuid == "" {
+		successorRequest.ProcessGuid = fmt.Sprintf("%s-%d", processGuid, now.UnixNano())
+	}
+	successorRequest.Instances = 0
+
+	err = c.bbs.DesireLRP(serialization.DesiredLRPFromRequest(successorRequest))
+	if err != nil {
+		logger.Error("failed-to-desire-successor", err)
+		return receptor.RollingUpdateResponse{
+			ProcessGuid:          processGuid,
+			SuccessorProcessGuid: successorRequest.ProcessGuid,
+		}, err
+	}
+
+	r := record{
+		Status: receptor.RollingUpdateResponse{
+			ProcessGuid:          processGuid,
+			SuccessorProcessGuid: successorRequest.ProcessGuid,
+			State:                receptor.RollingUpdateStateInProgress,
+			Instances:            int(predecessor.Instances),
+			BatchSize:            request.BatchSize,
+			PredecessorInstances: int(predecessor.Instances),
+			StartedAt:            now.UnixNano(),
+			UpdatedAt:            now.UnixNano(),
+		},
+	}
+	if r.Status.BatchSize <= 0 {
+		r.Status.BatchSize = 1
+	}
+
+	// the update is only recorded once the successor exists, so that it is
+	// never resumed without one. Of two updates started at once, the one
+	// recorded second gives up and removes its successor.
+	err = c.swapRecord(r, index)
+	if err != nil {
+		if err == store.ErrConflict {
+			err = ErrInProgress
+		} else {
+			logger.Error("failed-to-record-update", err)
+		}
+
+		removeErr := c.bbs.RemoveDesiredLRP(successorRequest.ProcessGuid)
+		if removeErr != nil {
+			logger.Error("failed-to-remove-successor", removeErr)
+		}
+		return receptor.RollingUpdateResponse{}, err
+	}
+
+	logger.Info("started", lager.Data{
+		"successor-process-guid": r.Status.SuccessorProcessGuid,
+		"instances":              r.Status.Instances,
+		"batch-size":             r.Status.BatchSize,
+	})
+
+	select {
+	case c.started <- struct{}{}:
+	default:
+	}
+
+	return r.Status, nil
+}
+
+func (c *coordinator) Get(processGuid string) (receptor.RollingUpdateResponse, error) {
+	r, _, err := c.getRecord(processGuid)
+	if err != nil {
+		return receptor.RollingUpdateResponse{}, err
+	}
+	return r.Status, nil
+}
+
+// Abort asks an update in progress to roll back. The update is reported as
+// ABORTED once the rollback has finished. An update driven by another
+// receptor notices within PollInterval.
+func (c *coordinator) Abort(processGuid string) (receptor.RollingUpdateResponse, error) {
+	r, err := c.updateRecord(processGuid, func(r *record) error {
+		if !r.inProgress() {
+			return ErrNotInProgress
+		}
+		r.Aborting = true
+		return nil
+	})
+	if err != nil {
+		return r.Status, err
+	}
+
+	c.lock.Lock()
+	wake, ok := c.driving[processGuid]
+	c.lock.Unlock()
+	if ok {
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}
+
+	return r.Status, nil
+}
+
+// driveInProgress starts driving the updates in progress which are not
+// driven yet.
+func (c *coordinator) driveInProgress(logger lager.Logger, stop <-chan struct{}, drivers *sync.WaitGroup) {
+	records, err := c.listRecords()
+	if err != nil {
+		logger.Error("failed-to-list-updates", err)
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, r := range records {
+		processGuid := r.Status.ProcessGuid
+		if _, ok := c.driving[processGuid]; ok || !r.inProgress() {
+			continue
+		}
+
+		wake := make(chan struct{}, 1)
+		c.driving[processGuid] = wake
+
+		drivers.Add(1)
+		go func(r record) {
+			defer drivers.Done()
+			c.drive(r, wake, stop)
+
+			c.lock.Lock()
+			delete(c.driving, r.Status.ProcessGuid)
+			c.lock.Unlock()
+		}(r)
+	}
+}
+
+// drive takes an update from where it was left, which is the start for a new
+// update. wake is signalled whenever the update may need to move on.
+func (c *coordinator) drive(r record, wake chan struct{}, stop <-chan struct{}) {
+	status := r.Status
+	logger := c.logger.Session("drive", lager.Data{
+		"process-guid":           status.ProcessGuid,
+		"successor-process-guid": status.SuccessorProcessGuid,
+	})
+
+	switch {
+	case r.RollbackCause != "":
+		c.rollBack(logger, status, errors.New(r.RollbackCause))
+		return
+	case r.Aborting:
+		c.rollBack(logger, status, errAborted)
+		return
+	}
+
+	// subscribe before scaling the successor up, so that no events about its
+	// instances are missed. Without events, running instances are only
+	// recounted every PollInterval.
+	source, err := c.hub.Subscribe()
+	if err != nil {
+		logger.Error("failed-to-subscribe-to-events", err)
+	} else {
+		go relayChanges(source, status.SuccessorProcessGuid, wake)
+		defer source.Close()
+	}
+
+	target := status.Instances
+	for moved := target - status.PredecessorInstances; moved < target; {
+		next := moved + status.BatchSize
+		if next > target {
+			next = target
+		}
+
+		logger.Info("scaling-up-successor", lager.Data{"instances": next})
+		err := c.scale(status.SuccessorProcessGuid, next)
+		if err != nil {
+			c.rollBack(logger, status, err)
+			return
+		}
+		c.setStatus(logger, status.ProcessGuid, func(status *receptor.RollingUpdateResponse) {
+			status.SuccessorInstances = next
+		})
+
+		err = c.awaitRunning(logger, status, next, wake, stop)
+		if err == errInterrupted {
+			logger.Info("interrupted")
+			return
+		}
+		if err != nil {
+			c.rollBack(logger, status, err)
+			return
+		}
+
+		logger.Info("scaling-down-predecessor", lager.Data{"instances": target - next})
+		err = c.scale(status.ProcessGuid, target-next)
+		if err != nil {
+			c.rollBack(logger, status, err)
+			return
+		}
+		c.setStatus(logger, status.ProcessGuid, func(status *receptor.RollingUpdateResponse) {
+			status.PredecessorInstances = target - next
+		})
+
+		moved = next
+	}
+
+	err = c.bbs.RemoveDesiredLRP(status.ProcessGuid)
+	if err != nil && models.ConvertError(err).Type != models.Error_ResourceNotFound {
+		// every instance has moved to the successor by now, so there is
+		// nothing left to roll back
+		logger.Error("failed-to-remove-predecessor", err)
+		c.finish(logger, status.ProcessGuid, receptor.RollingUpdateStateFailed, fmt.Errorf("failed to remove predecessor: %s", err))
+		return
+	}
+
+	logger.Info("completed")
+	c.finish(logger, status.ProcessGuid, receptor.RollingUpdateStateCompleted, nil)
+}
+
+// awaitRunning waits until count instances of the successor are running. The
+// count is rechecked whenever wake is signalled, and at least every
+// PollInterval.
+func (c *coordinator) awaitRunning(logger lager.Logger, status receptor.RollingUpdateResponse, count int, wake <-chan struct{}, stop <-chan struct{}) error {
+	deadline := c.clock.NewTimer(c.config.BatchTimeout)
+	defer deadline.Stop()
+
+	for {
+		running, countErr := c.countRunning(status.SuccessorProcessGuid)
+		if countErr != nil {
+			logger.Error("failed-to-count-running-instances", countErr)
+		}
+
+		r, err := c.updateRecord(status.ProcessGuid, func(r *record) error {
+			if countErr == nil {
+				r.Status.SuccessorRunning = running
+			}
+			return nil
+		})
+		if err != nil {
+			logger.Error("failed-to-record-progress", err)
+		} else if r.Aborting {
+			return errAborted
+		}
+
+		if countErr == nil && running >= count {
+			return nil
+		}
+
+		poll := c.clock.NewTimer(c.config.PollInterval)
+		select {
+		case <-wake:
+		case <-poll.C():
+		case <-deadline.C():
+			poll.Stop()
+			return fmt.Errorf("timed out waiting for %d instances to be running", count)
+		case <-stop:
+			poll.Stop()
+			return errInterrupted
+		}
+		poll.Stop()
+	}
+}
+
+func (c *coordinator) countRunning(processGuid string) (int, error) {
+	groups, err := c.bbs.ActualLRPGroupsByProcessGuid(processGuid)
+	if err != nil {
+		return 0, err
+	}
+
+	running := 0
+	for _, group := range groups {
+		lrp, _ := group.Resolve()
+		if lrp != nil && lrp.State == models.ActualLRPStateRunning {
+			running++
+		}
+	}
+	return running, nil
+}
+
+func (c *coordinator) scale(processGuid string, instances int) error {
+	count := int32(instances)
+	return c.bbs.UpdateDesiredLRP(processGuid, &models.DesiredLRPUpdate{Instances: &count})
+}
+
+// rollBack restores the predecessor to its original instance count and
+// removes the successor. The cause of a failed update is recorded first, so
+// that an interrupted rollback is resumed rather than the update.
+func (c *coordinator) rollBack(logger lager.Logger, status receptor.RollingUpdateResponse, cause error) {
+	logger.Error("rolling-back", cause)
+
+	state := receptor.RollingUpdateStateFailed
+	if cause == errAborted {
+		state = receptor.RollingUpdateStateAborted
+		cause = nil
+	} else {
+		_, err := c.updateRecord(status.ProcessGuid, func(r *record) error {
+			r.RollbackCause = cause.Error()
+			return nil
+		})
+		if err != nil {
+			logger.Error("failed-to-record-rollback", err)
+		}
+	}
+
+	err := c.scale(status.ProcessGuid, status.Instances)
+	if err != nil {
+		logger.Error("failed-to-restore-predecessor", err)
+		c.finish(logger, status.ProcessGuid, receptor.RollingUpdateStateFailed, fmt.Errorf("failed to restore predecessor: %s", err))
+		return
+	}
+	c.setStatus(logger, status.ProcessGuid, func(status *receptor.RollingUpdateResponse) {
+		status.PredecessorInstances = status.Instances
+	})
+
+	err = c.bbs.RemoveDesiredLRP(status.SuccessorProcessGuid)
+	if err != nil && models.ConvertError(err).Type != models.Error_ResourceNotFound {
+		logger.Error("failed-to-remove-successor", err)
+		c.finish(logger, status.ProcessGuid, receptor.RollingUpdateStateFailed, fmt.Errorf("failed to remove successor: %s", err))
+		return
+	}
+	c.setStatus(logger, status.ProcessGuid, func(status *receptor.RollingUpdateResponse) {
+		status.SuccessorInstances = 0
+		status.SuccessorRunning = 0
+	})
+
+	c.finish(logger, status.ProcessGuid, state, cause)
+}
+
+func (c *coordinator) finish(logger lager.Logger, processGuid string, state string, cause error) {
+	c.setStatus(logger, processGuid, func(status *receptor.RollingUpdateResponse) {
+		status.State = state
+		if cause != nil {
+			status.Error = cause.Error()
+		}
+	})
+}
+
+// setStatus records the progress of an update. Failing to record it is only
+// logged: the progress is recorded again as the update moves on.
+func (c *coordinator) setStatus(logger lager.Logger, processGuid string, change func(*receptor.RollingUpdateResponse)) {
+	_, err := c.updateRecord(processGuid, func(r *record) error {
+		change(&r.Status)
+		return nil
+	})
+	if err != nil {
+		logger.Error("failed-to-record-progress", err)
+	}
+}
+
+// relayChanges signals changes whenever an event may have changed the number
+// of running instances of the process, until source fails or is closed.
+func relayChanges(source event.Source, processGuid string, changes chan<- struct{}) {
+	for {
+		message, err := source.Next()
+		if err != nil {
+			return
+		}
+
+		if !affectsProcess(message.Event, processGuid) {
+			continue
+		}
+
+		select {
+		case changes <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func affectsProcess(e receptor.Event, processGuid string) bool {
+	switch e := e.(type) {
+	case receptor.ActualLRPCreatedEvent:
+		return e.ActualLRPResponse.ProcessGuid == processGuid
+	case receptor.ActualLRPChangedEvent:
+		return e.After.ProcessGuid == processGuid
+	case receptor.ActualLRPRemovedEvent:
+		return e.ActualLRPResponse.ProcessGuid == processGuid
+	case receptor.ResyncRequiredEvent:
+		return true
+	}
+	return false
+}
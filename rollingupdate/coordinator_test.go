@@ -0,0 +1,566 @@
+package rollingupdate_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudfoundry-incubator/bbs/fake_bbs"
+	"github.com/cloudfoundry-incubator/bbs/models"
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/event"
+	"github.com/cloudfoundry-incubator/receptor/rollingupdate"
+	"github.com/cloudfoundry-incubator/receptor/store"
+	"github.com/pivotal-golang/clock/fakeclock"
+	"github.com/pivotal-golang/lager/lagertest"
+	"github.com/tedsuo/ifrit"
+	"github.com/tedsuo/ifrit/ginkgomon"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type scaling struct {
+	processGuid string
+	instances   int32
+}
+
+var _ = Describe("Coordinator", func() {
+	var (
+		fakeBBS   *fake_bbs.FakeClient
+		hub       event.Hub
+		updates   *memoryStore
+		fakeClock *fakeclock.FakeClock
+		config    rollingupdate.Config
+
+		coordinator rollingupdate.Coordinator
+		process     ifrit.Process
+
+		lock             sync.Mutex
+		successorRunning int
+
+		request receptor.RollingUpdateRequest
+	)
+
+	setSuccessorRunning := func(running int) {
+		lock.Lock()
+		successorRunning = running
+		lock.Unlock()
+
+		hub.Emit(receptor.NewActualLRPChangedEvent(
+			receptor.ActualLRPResponse{ProcessGuid: "new-guid"},
+			receptor.ActualLRPResponse{ProcessGuid: "new-guid", State: receptor.ActualLRPStateRunning},
+		))
+	}
+
+	scalings := func() []scaling {
+		result := []scaling{}
+		for i := 0; i < fakeBBS.UpdateDesiredLRPCallCount(); i++ {
+			processGuid, update := fakeBBS.UpdateDesiredLRPArgsForCall(i)
+			result = append(result, scaling{processGuid, *update.Instances})
+		}
+		return result
+	}
+
+	state := func() string {
+		status, _ := coordinator.Get("old-guid")
+		return status.State
+	}
+
+	newCoordinator := func() rollingupdate.Coordinator {
+		return rollingupdate.NewCoordinator(fakeBBS, hub, updates, fakeClock, config, lagertest.NewTestLogger("test"))
+	}
+
+	BeforeEach(func() {
+		fakeBBS = new(fake_bbs.FakeClient)
+		hub = event.NewHub(10)
+		updates = newMemoryStore()
+		fakeClock = fakeclock.NewFakeClock(time.Now())
+		config = rollingupdate.Config{
+			BatchTimeout: time.Minute,
+			PollInterval: time.Hour,
+		}
+
+		successorRunning = 0
+
+		fakeBBS.DesiredLRPByProcessGuidReturns(&models.DesiredLRP{
+			ProcessGuid: "old-guid",
+			Instances:   2,
+		}, nil)
+
+		fakeBBS.ActualLRPGroupsByProcessGuidStub = func(processGuid string) ([]*models.ActualLRPGroup, error) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			groups := []*models.ActualLRPGroup{}
+			for i := 0; i < successorRunning; i++ {
+				groups = append(groups, &models.ActualLRPGroup{
+					Instance: &models.ActualLRP{
+						ActualLRPKey: models.NewActualLRPKey(processGuid, int32(i), "some-domain"),
+						State:        models.ActualLRPStateRunning,
+					},
+				})
+			}
+			return groups, nil
+		}
+
+		request = receptor.RollingUpdateRequest{
+			Successor: receptor.DesiredLRPCreateRequest{
+				ProcessGuid: "new-guid",
+				Domain:      "some-domain",
+				RootFS:      "preloaded:some-stack",
+				Instances:   5,
+			},
+			BatchSize: 1,
+		}
+	})
+
+	JustBeforeEach(func() {
+		coordinator = newCoordinator()
+		process = ginkgomon.Invoke(coordinator)
+	})
+
+	AfterEach(func() {
+		ginkgomon.Interrupt(process)
+	})
+
+	Describe("Start", func() {
+		It("desires the successor without any instances", func() {
+			status, err := coordinator.Start("old-guid", request)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(status.SuccessorProcessGuid).To(Equal("new-guid"))
+			Expect(status.Instances).To(Equal(2))
+			Expect(status.State).To(Equal(receptor.RollingUpdateStateInProgress))
+
+			Expect(fakeBBS.DesireLRPCallCount()).To(Equal(1))
+			successor := fakeBBS.DesireLRPArgsForCall(0)
+			Expect(successor.ProcessGuid).To(Equal("new-guid"))
+			Expect(successor.Instances).To(BeZero())
+		})
+
+		It("moves the instances to the successor one batch at a time", func() {
+			_, err := coordinator.Start("old-guid", request)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(scalings).Should(Equal([]scaling{{"new-guid", 1}}))
+			Consistently(scalings).Should(HaveLen(1))
+
+			setSuccessorRunning(1)
+			Eventually(scalings).Should(Equal([]scaling{
+				{"new-guid", 1},
+				{"old-guid", 1},
+				{"new-guid", 2},
+			}))
+
+			setSuccessorRunning(2)
+			Eventually(state).Should(Equal(receptor.RollingUpdateStateCompleted))
+
+			Expect(scalings()).To(Equal([]scaling{
+				{"new-guid", 1},
+				{"old-guid", 1},
+				{"new-guid", 2},
+				{"old-guid", 0},
+			}))
+			Expect(fakeBBS.RemoveDesiredLRPCallCount()).To(Equal(1))
+			Expect(fakeBBS.RemoveDesiredLRPArgsForCall(0)).To(Equal("old-guid"))
+
+			status, _ := coordinator.Get("old-guid")
+			Expect(status.SuccessorInstances).To(Equal(2))
+			Expect(status.SuccessorRunning).To(Equal(2))
+			Expect(status.PredecessorInstances).To(BeZero())
+		})
+
+		Context("when no successor guid is given", func() {
+			BeforeEach(func() {
+				request.Successor.ProcessGuid = ""
+			})
+
+			It("generates one", func() {
+				status, err := coordinator.Start("old-guid", request)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(status.SuccessorProcessGuid).To(HavePrefix("old-guid-"))
+			})
+		})
+
+		Context("when an update of the LRP is already in progress", func() {
+			It("refuses to start another", func() {
+				_, err := coordinator.Start("old-guid", request)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = coordinator.Start("old-guid", request)
+				Expect(err).To(Equal(rollingupdate.ErrInProgress))
+			})
+		})
+
+		Context("when another receptor starts an update of the LRP at the same time", func() {
+			BeforeEach(func() {
+				updates.failSwaps(1)
+			})
+
+			It("refuses to start another and removes its successor", func() {
+				_, err := coordinator.Start("old-guid", request)
+				Expect(err).To(Equal(rollingupdate.ErrInProgress))
+
+				Expect(fakeBBS.RemoveDesiredLRPCallCount()).To(Equal(1))
+				Expect(fakeBBS.RemoveDesiredLRPArgsForCall(0)).To(Equal("new-guid"))
+			})
+		})
+
+		Context("when the desired LRP does not exist", func() {
+			BeforeEach(func() {
+				fakeBBS.DesiredLRPByProcessGuidReturns(nil, models.ErrResourceNotFound)
+			})
+
+			It("returns the error without recording an update", func() {
+				_, err := coordinator.Start("old-guid", request)
+				Expect(err).To(Equal(models.ErrResourceNotFound))
+
+				_, err = coordinator.Get("old-guid")
+				Expect(err).To(Equal(rollingupdate.ErrNotFound))
+				Expect(fakeBBS.DesireLRPCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when the successor can not be desired", func() {
+			BeforeEach(func() {
+				fakeBBS.DesireLRPReturns(models.ErrResourceExists)
+			})
+
+			It("returns the error without recording an update", func() {
+				_, err := coordinator.Start("old-guid", request)
+				Expect(err).To(Equal(models.ErrResourceExists))
+
+				_, err = coordinator.Get("old-guid")
+				Expect(err).To(Equal(rollingupdate.ErrNotFound))
+			})
+
+			Context("when no successor guid is given", func() {
+				BeforeEach(func() {
+					request.Successor.ProcessGuid = ""
+				})
+
+				It("returns the generated guid along with the error", func() {
+					status, err := coordinator.Start("old-guid", request)
+					Expect(err).To(Equal(models.ErrResourceExists))
+
+					desiredLRP := fakeBBS.DesireLRPArgsForCall(0)
+					Expect(status.SuccessorProcessGuid).To(HavePrefix("old-guid-"))
+					Expect(status.SuccessorProcessGuid).To(Equal(desiredLRP.ProcessGuid))
+				})
+			})
+		})
+
+		Context("when a batch does not start running in time", func() {
+			It("rolls the update back", func() {
+				_, err := coordinator.Start("old-guid", request)
+				Expect(err).NotTo(HaveOccurred())
+				Eventually(scalings).Should(HaveLen(1))
+
+				Eventually(func() string {
+					fakeClock.Increment(time.Minute)
+					return state()
+				}).Should(Equal(receptor.RollingUpdateStateFailed))
+
+				Expect(scalings()).To(Equal([]scaling{
+					{"new-guid", 1},
+					{"old-guid", 2},
+				}))
+				Expect(fakeBBS.RemoveDesiredLRPCallCount()).To(Equal(1))
+				Expect(fakeBBS.RemoveDesiredLRPArgsForCall(0)).To(Equal("new-guid"))
+
+				status, _ := coordinator.Get("old-guid")
+				Expect(status.Error).To(ContainSubstring("timed out"))
+			})
+		})
+
+		Context("when scaling the successor fails", func() {
+			BeforeEach(func() {
+				fakeBBS.UpdateDesiredLRPStub = func(processGuid string, update *models.DesiredLRPUpdate) error {
+					if processGuid == "new-guid" {
+						return errors.New("boom")
+					}
+					return nil
+				}
+			})
+
+			It("rolls the update back", func() {
+				_, err := coordinator.Start("old-guid", request)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(state).Should(Equal(receptor.RollingUpdateStateFailed))
+				Expect(fakeBBS.RemoveDesiredLRPArgsForCall(0)).To(Equal("new-guid"))
+			})
+		})
+	})
+
+	Describe("Abort", func() {
+		It("rolls back an update in progress", func() {
+			_, err := coordinator.Start("old-guid", request)
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(scalings).Should(HaveLen(1))
+
+			_, err = coordinator.Abort("old-guid")
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(state).Should(Equal(receptor.RollingUpdateStateAborted))
+			Expect(scalings()).To(Equal([]scaling{
+				{"new-guid", 1},
+				{"old-guid", 2},
+			}))
+			Expect(fakeBBS.RemoveDesiredLRPArgsForCall(0)).To(Equal("new-guid"))
+
+			status, _ := coordinator.Get("old-guid")
+			Expect(status.PredecessorInstances).To(Equal(2))
+			Expect(status.SuccessorInstances).To(BeZero())
+			Expect(status.Error).To(BeEmpty())
+		})
+
+		It("refuses to abort an update which has finished", func() {
+			_, err := coordinator.Start("old-guid", request)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = coordinator.Abort("old-guid")
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(state).Should(Equal(receptor.RollingUpdateStateAborted))
+
+			_, err = coordinator.Abort("old-guid")
+			Expect(err).To(Equal(rollingupdate.ErrNotInProgress))
+		})
+
+		It("fails for unknown updates", func() {
+			_, err := coordinator.Abort("unknown-guid")
+			Expect(err).To(Equal(rollingupdate.ErrNotFound))
+		})
+
+		Context("when the update is aborted on another receptor", func() {
+			BeforeEach(func() {
+				config.PollInterval = time.Second
+			})
+
+			It("rolls it back once it next polls", func() {
+				_, err := coordinator.Start("old-guid", request)
+				Expect(err).NotTo(HaveOccurred())
+				Eventually(scalings).Should(HaveLen(1))
+
+				_, err = newCoordinator().Abort("old-guid")
+				Expect(err).NotTo(HaveOccurred())
+				Consistently(state).Should(Equal(receptor.RollingUpdateStateInProgress))
+
+				Eventually(func() string {
+					fakeClock.Increment(time.Second)
+					return state()
+				}).Should(Equal(receptor.RollingUpdateStateAborted))
+			})
+		})
+	})
+
+	Describe("sharing updates between receptors", func() {
+		It("reports an update started on another receptor", func() {
+			status, err := coordinator.Start("old-guid", request)
+			Expect(err).NotTo(HaveOccurred())
+
+			otherStatus, err := newCoordinator().Get("old-guid")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(otherStatus).To(Equal(status))
+		})
+
+		It("refuses to start an update in progress on another receptor", func() {
+			_, err := coordinator.Start("old-guid", request)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = newCoordinator().Start("old-guid", request)
+			Expect(err).To(Equal(rollingupdate.ErrInProgress))
+		})
+
+		Context("when the coordinator is interrupted", func() {
+			It("leaves the update in progress for the next coordinator to resume", func() {
+				_, err := coordinator.Start("old-guid", request)
+				Expect(err).NotTo(HaveOccurred())
+				Eventually(scalings).Should(Equal([]scaling{{"new-guid", 1}}))
+
+				ginkgomon.Interrupt(process)
+				Expect(state()).To(Equal(receptor.RollingUpdateStateInProgress))
+
+				coordinator = newCoordinator()
+				process = ginkgomon.Invoke(coordinator)
+
+				setSuccessorRunning(2)
+				Eventually(state).Should(Equal(receptor.RollingUpdateStateCompleted))
+				Expect(scalings()).To(Equal([]scaling{
+					{"new-guid", 1},
+					{"new-guid", 1},
+					{"old-guid", 1},
+					{"new-guid", 2},
+					{"old-guid", 0},
+				}))
+			})
+		})
+
+		Context("when an update was left part way by another coordinator", func() {
+			var left receptor.RollingUpdateResponse
+
+			BeforeEach(func() {
+				left = receptor.RollingUpdateResponse{
+					ProcessGuid:          "old-guid",
+					SuccessorProcessGuid: "new-guid",
+					State:                receptor.RollingUpdateStateInProgress,
+					Instances:            2,
+					BatchSize:            1,
+					SuccessorInstances:   1,
+					SuccessorRunning:     1,
+					PredecessorInstances: 1,
+				}
+				updates.putRecord(left, "")
+			})
+
+			It("resumes it from the batch it reached", func() {
+				Eventually(scalings).Should(Equal([]scaling{{"new-guid", 2}}))
+
+				setSuccessorRunning(2)
+				Eventually(state).Should(Equal(receptor.RollingUpdateStateCompleted))
+				Expect(scalings()).To(Equal([]scaling{
+					{"new-guid", 2},
+					{"old-guid", 0},
+				}))
+				Expect(fakeBBS.RemoveDesiredLRPArgsForCall(0)).To(Equal("old-guid"))
+			})
+
+			Context("when it was being aborted", func() {
+				BeforeEach(func() {
+					updates.putRecord(left, `"aborting":true`)
+				})
+
+				It("rolls it back", func() {
+					Eventually(state).Should(Equal(receptor.RollingUpdateStateAborted))
+					Expect(scalings()).To(Equal([]scaling{{"old-guid", 2}}))
+					Expect(fakeBBS.RemoveDesiredLRPArgsForCall(0)).To(Equal("new-guid"))
+				})
+			})
+
+			Context("when it was being rolled back after failing", func() {
+				BeforeEach(func() {
+					updates.putRecord(left, `"rollback_cause":"boom"`)
+				})
+
+				It("finishes rolling it back", func() {
+					Eventually(state).Should(Equal(receptor.RollingUpdateStateFailed))
+					Expect(scalings()).To(Equal([]scaling{{"old-guid", 2}}))
+
+					status, err := coordinator.Get("old-guid")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(status.Error).To(Equal("boom"))
+				})
+			})
+		})
+	})
+})
+
+// memoryStore is a store.Store held in memory, shared by the coordinators of
+// a test as consul is shared by receptors.
+type memoryStore struct {
+	lock      sync.Mutex
+	entries   map[string]store.Entry
+	lastIndex uint64
+	conflicts int
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{entries: map[string]store.Entry{}}
+}
+
+// failSwaps makes the next n calls to CompareAndSwap fail with ErrConflict.
+func (s *memoryStore) failSwaps(n int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.conflicts = n
+}
+
+// putRecord stores the record of an update with the given status and, unless
+// it is empty, an extra JSON field.
+func (s *memoryStore) putRecord(status receptor.RollingUpdateResponse, field string) {
+	payload, err := json.Marshal(status)
+	Expect(err).NotTo(HaveOccurred())
+
+	value := `{"status":` + string(payload) + `}`
+	if field != "" {
+		value = `{"status":` + string(payload) + `,` + field + `}`
+	}
+	Expect(s.Put("rolling-updates/"+status.ProcessGuid, []byte(value))).To(Succeed())
+}
+
+func (s *memoryStore) Get(key string) (store.Entry, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return store.Entry{}, store.ErrNotFound
+	}
+	return entry, nil
+}
+
+func (s *memoryStore) List(prefix string) ([]store.Entry, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	entries := []store.Entry{}
+	for key, entry := range s.entries {
+		if strings.HasPrefix(key, prefix) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (s *memoryStore) Put(key string, value []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.put(key, value)
+	return nil
+}
+
+func (s *memoryStore) CompareAndSwap(key string, index uint64, value []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.conflicts > 0 {
+		s.conflicts--
+		return store.ErrConflict
+	}
+	if s.entries[key].Index != index {
+		return store.ErrConflict
+	}
+
+	s.put(key, value)
+	return nil
+}
+
+func (s *memoryStore) CompareAndDelete(key string, index uint64) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok || entry.Index != index {
+		return store.ErrConflict
+	}
+
+	delete(s.entries, key)
+	return nil
+}
+
+func (s *memoryStore) Delete(key string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+func (s *memoryStore) put(key string, value []byte) {
+	s.lastIndex++
+	s.entries[key] = store.Entry{Key: key, Value: value, Index: s.lastIndex}
+}
@@ -0,0 +1,185 @@
+// This file was generated by counterfeiter
+package fake_rollingupdate
+
+import (
+	"os"
+	"sync"
+
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/rollingupdate"
+)
+
+type FakeCoordinator struct {
+	RunStub        func(signals <-chan os.Signal, ready chan<- struct{}) error
+	runMutex       sync.RWMutex
+	runArgsForCall []struct {
+		signals <-chan os.Signal
+		ready   chan<- struct{}
+	}
+	runReturns struct {
+		result1 error
+	}
+	StartStub        func(processGuid string, request receptor.RollingUpdateRequest) (receptor.RollingUpdateResponse, error)
+	startMutex       sync.RWMutex
+	startArgsForCall []struct {
+		processGuid string
+		request     receptor.RollingUpdateRequest
+	}
+	startReturns struct {
+		result1 receptor.RollingUpdateResponse
+		result2 error
+	}
+	GetStub        func(processGuid string) (receptor.RollingUpdateResponse, error)
+	getMutex       sync.RWMutex
+	getArgsForCall []struct {
+		processGuid string
+	}
+	getReturns struct {
+		result1 receptor.RollingUpdateResponse
+		result2 error
+	}
+	AbortStub        func(processGuid string) (receptor.RollingUpdateResponse, error)
+	abortMutex       sync.RWMutex
+	abortArgsForCall []struct {
+		processGuid string
+	}
+	abortReturns struct {
+		result1 receptor.RollingUpdateResponse
+		result2 error
+	}
+}
+
+func (fake *FakeCoordinator) Run(signals <-chan os.Signal, ready chan<- struct{}) error {
+	fake.runMutex.Lock()
+	fake.runArgsForCall = append(fake.runArgsForCall, struct {
+		signals <-chan os.Signal
+		ready   chan<- struct{}
+	}{signals, ready})
+	fake.runMutex.Unlock()
+	if fake.RunStub != nil {
+		return fake.RunStub(signals, ready)
+	} else {
+		return fake.runReturns.result1
+	}
+}
+
+func (fake *FakeCoordinator) RunCallCount() int {
+	fake.runMutex.RLock()
+	defer fake.runMutex.RUnlock()
+	return len(fake.runArgsForCall)
+}
+
+func (fake *FakeCoordinator) RunArgsForCall(i int) (<-chan os.Signal, chan<- struct{}) {
+	fake.runMutex.RLock()
+	defer fake.runMutex.RUnlock()
+	return fake.runArgsForCall[i].signals, fake.runArgsForCall[i].ready
+}
+
+func (fake *FakeCoordinator) RunReturns(result1 error) {
+	fake.RunStub = nil
+	fake.runReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCoordinator) Start(processGuid string, request receptor.RollingUpdateRequest) (receptor.RollingUpdateResponse, error) {
+	fake.startMutex.Lock()
+	fake.startArgsForCall = append(fake.startArgsForCall, struct {
+		processGuid string
+		request     receptor.RollingUpdateRequest
+	}{processGuid, request})
+	fake.startMutex.Unlock()
+	if fake.StartStub != nil {
+		return fake.StartStub(processGuid, request)
+	} else {
+		return fake.startReturns.result1, fake.startReturns.result2
+	}
+}
+
+func (fake *FakeCoordinator) StartCallCount() int {
+	fake.startMutex.RLock()
+	defer fake.startMutex.RUnlock()
+	return len(fake.startArgsForCall)
+}
+
+func (fake *FakeCoordinator) StartArgsForCall(i int) (string, receptor.RollingUpdateRequest) {
+	fake.startMutex.RLock()
+	defer fake.startMutex.RUnlock()
+	return fake.startArgsForCall[i].processGuid, fake.startArgsForCall[i].request
+}
+
+func (fake *FakeCoordinator) StartReturns(result1 receptor.RollingUpdateResponse, result2 error) {
+	fake.StartStub = nil
+	fake.startReturns = struct {
+		result1 receptor.RollingUpdateResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCoordinator) Get(processGuid string) (receptor.RollingUpdateResponse, error) {
+	fake.getMutex.Lock()
+	fake.getArgsForCall = append(fake.getArgsForCall, struct {
+		processGuid string
+	}{processGuid})
+	fake.getMutex.Unlock()
+	if fake.GetStub != nil {
+		return fake.GetStub(processGuid)
+	} else {
+		return fake.getReturns.result1, fake.getReturns.result2
+	}
+}
+
+func (fake *FakeCoordinator) GetCallCount() int {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	return len(fake.getArgsForCall)
+}
+
+func (fake *FakeCoordinator) GetArgsForCall(i int) string {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	return fake.getArgsForCall[i].processGuid
+}
+
+func (fake *FakeCoordinator) GetReturns(result1 receptor.RollingUpdateResponse, result2 error) {
+	fake.GetStub = nil
+	fake.getReturns = struct {
+		result1 receptor.RollingUpdateResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCoordinator) Abort(processGuid string) (receptor.RollingUpdateResponse, error) {
+	fake.abortMutex.Lock()
+	fake.abortArgsForCall = append(fake.abortArgsForCall, struct {
+		processGuid string
+	}{processGuid})
+	fake.abortMutex.Unlock()
+	if fake.AbortStub != nil {
+		return fake.AbortStub(processGuid)
+	} else {
+		return fake.abortReturns.result1, fake.abortReturns.result2
+	}
+}
+
+func (fake *FakeCoordinator) AbortCallCount() int {
+	fake.abortMutex.RLock()
+	defer fake.abortMutex.RUnlock()
+	return len(fake.abortArgsForCall)
+}
+
+func (fake *FakeCoordinator) AbortArgsForCall(i int) string {
+	fake.abortMutex.RLock()
+	defer fake.abortMutex.RUnlock()
+	return fake.abortArgsForCall[i].processGuid
+}
+
+func (fake *FakeCoordinator) AbortReturns(result1 receptor.RollingUpdateResponse, result2 error) {
+	fake.AbortStub = nil
+	fake.abortReturns = struct {
+		result1 receptor.RollingUpdateResponse
+		result2 error
+	}{result1, result2}
+}
+
+var _ rollingupdate.Coordinator = new(FakeCoordinator)
@@ -0,0 +1,99 @@
+package rollingupdate
+
+import (
+	"encoding/json"
+
+	"github.com/cloudfoundry-incubator/receptor"
+	"github.com/cloudfoundry-incubator/receptor/store"
+	"github.com/pivotal-golang/lager"
+)
+
+const updatesPrefix = "rolling-updates/"
+
+// record is how an update is kept in the store. Aborting is set by Abort on
+// any receptor, and RollbackCause once a failed update starts rolling back,
+// so that the receptor which resumes an interrupted update knows which way
+// to take it.
+type record struct {
+	Status        receptor.RollingUpdateResponse `json:"status"`
+	Aborting      bool                           `json:"aborting,omitempty"`
+	RollbackCause string                         `json:"rollback_cause,omitempty"`
+}
+
+func (r record) inProgress() bool {
+	return r.Status.State == receptor.RollingUpdateStateInProgress
+}
+
+func updateKey(processGuid string) string {
+	return updatesPrefix + processGuid
+}
+
+func (c *coordinator) getRecord(processGuid string) (record, uint64, error) {
+	entry, err := c.store.Get(updateKey(processGuid))
+	if err == store.ErrNotFound {
+		return record{}, 0, ErrNotFound
+	}
+	if err != nil {
+		return record{}, 0, err
+	}
+
+	var r record
+	err = json.Unmarshal(entry.Value, &r)
+	if err != nil {
+		return record{}, 0, err
+	}
+	return r, entry.Index, nil
+}
+
+func (c *coordinator) listRecords() ([]record, error) {
+	entries, err := c.store.List(updatesPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []record{}
+	for _, entry := range entries {
+		var r record
+		err := json.Unmarshal(entry.Value, &r)
+		if err != nil {
+			c.logger.Error("invalid-record", err, lager.Data{"key": entry.Key})
+			continue
+		}
+		records = append(records, r)
+	}
+	return records, nil
+}
+
+// swapRecord writes r when the record is still at index, which is 0 when
+// there is no record yet.
+func (c *coordinator) swapRecord(r record, index uint64) error {
+	payload, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return c.store.CompareAndSwap(updateKey(r.Status.ProcessGuid), index, payload)
+}
+
+// updateRecord applies change to the record of an update, retrying when the
+// record is modified concurrently. change may fail to leave the record as it
+// is.
+func (c *coordinator) updateRecord(processGuid string, change func(*record) error) (record, error) {
+	for {
+		r, index, err := c.getRecord(processGuid)
+		if err != nil {
+			return record{}, err
+		}
+
+		err = change(&r)
+		if err != nil {
+			return r, err
+		}
+		r.Status.UpdatedAt = c.clock.Now().UnixNano()
+
+		err = c.swapRecord(r, index)
+		if err == store.ErrConflict {
+			continue
+		}
+		return r, err
+	}
+}
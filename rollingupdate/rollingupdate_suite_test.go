@@ -0,0 +1,13 @@
+package rollingupdate_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestRollingUpdate(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Rolling Update Suite")
+}
@@ -4,11 +4,18 @@ import "github.com/tedsuo/rata"
 
 const (
 	// Tasks
-	CreateTaskRoute = "CreateTask"
-	TasksRoute      = "Tasks"
-	GetTaskRoute    = "GetTask"
-	DeleteTaskRoute = "DeleteTask"
-	CancelTaskRoute = "CancelTask"
+	CreateTaskRoute      = "CreateTask"
+	CreateTasksBulkRoute = "CreateTasksBulk"
+	TasksRoute           = "Tasks"
+	GetTaskRoute         = "GetTask"
+	GetTaskResultRoute   = "GetTaskResult"
+	DeleteTaskRoute      = "DeleteTask"
+	DeleteTasksRoute     = "DeleteTasks"
+	CancelTaskRoute      = "CancelTask"
+	RetryTaskRoute       = "RetryTask"
+
+	// Completion Callbacks
+	TaskCallbackDeliveryRoute = "TaskCallbackDelivery"
 
 	// DesiredLRPs
 	CreateDesiredLRPRoute = "CreateDesiredLRP"
@@ -16,12 +23,34 @@ const (
 	UpdateDesiredLRPRoute = "UpdateDesiredLRP"
 	DeleteDesiredLRPRoute = "DeleteDesiredLRP"
 	DesiredLRPsRoute      = "DesiredLRPs"
+	StopDesiredLRPRoute   = "StopDesiredLRP"
+	StartDesiredLRPRoute  = "StartDesiredLRP"
+
+	// DesiredLRPs by Domain
+	ScaleDesiredLRPsByDomainRoute   = "ScaleDesiredLRPsByDomain"
+	RestartDesiredLRPsByDomainRoute = "RestartDesiredLRPsByDomain"
+	DeleteDesiredLRPsByDomainRoute  = "DeleteDesiredLRPsByDomain"
+
+	// Rolling Updates
+	StartRollingUpdateRoute = "StartRollingUpdate"
+	GetRollingUpdateRoute   = "GetRollingUpdate"
+	AbortRollingUpdateRoute = "AbortRollingUpdate"
+
+	// Autoscaling Policies
+	AutoscalingPoliciesRoute     = "AutoscalingPolicies"
+	GetAutoscalingPolicyRoute    = "GetAutoscalingPolicy"
+	SetAutoscalingPolicyRoute    = "SetAutoscalingPolicy"
+	DeleteAutoscalingPolicyRoute = "DeleteAutoscalingPolicy"
 
 	// ActualLRPs
 	ActualLRPsRoute                         = "ActualLRPs"
 	ActualLRPsByProcessGuidRoute            = "ActualLRPsByProcessGuid"
 	ActualLRPByProcessGuidAndIndexRoute     = "ActualLRPByProcessGuidAndIndex"
 	KillActualLRPByProcessGuidAndIndexRoute = "KillActualLRPByProcessGuidAndIndex"
+	KillActualLRPsByProcessGuidRoute        = "KillActualLRPsByProcessGuid"
+	KillActualLRPByInstanceGuidRoute        = "KillActualLRPByInstanceGuid"
+	ActualLRPCrashesRoute                   = "ActualLRPCrashes"
+	CrashingActualLRPsRoute                 = "CrashingActualLRPs"
 
 	// Cells
 	CellsRoute = "Cells"
@@ -46,10 +75,17 @@ const (
 var Routes = rata.Routes{
 	// Tasks
 	{Path: "/v1/tasks", Method: "POST", Name: CreateTaskRoute},
+	{Path: "/v1/tasks/bulk", Method: "POST", Name: CreateTasksBulkRoute},
 	{Path: "/v1/tasks", Method: "GET", Name: TasksRoute},
 	{Path: "/v1/tasks/:task_guid", Method: "GET", Name: GetTaskRoute},
+	{Path: "/v1/tasks/:task_guid/result", Method: "GET", Name: GetTaskResultRoute},
 	{Path: "/v1/tasks/:task_guid", Method: "DELETE", Name: DeleteTaskRoute},
+	{Path: "/v1/tasks", Method: "DELETE", Name: DeleteTasksRoute},
 	{Path: "/v1/tasks/:task_guid/cancel", Method: "POST", Name: CancelTaskRoute},
+	{Path: "/v1/tasks/:task_guid/retry", Method: "POST", Name: RetryTaskRoute},
+
+	// Completion Callbacks
+	{Path: "/v1/tasks/:task_guid/callback_delivery", Method: "GET", Name: TaskCallbackDeliveryRoute},
 
 	// DesiredLRPs
 	{Path: "/v1/desired_lrps", Method: "GET", Name: DesiredLRPsRoute},
@@ -57,12 +93,34 @@ var Routes = rata.Routes{
 	{Path: "/v1/desired_lrps/:process_guid", Method: "GET", Name: GetDesiredLRPRoute},
 	{Path: "/v1/desired_lrps/:process_guid", Method: "PUT", Name: UpdateDesiredLRPRoute},
 	{Path: "/v1/desired_lrps/:process_guid", Method: "DELETE", Name: DeleteDesiredLRPRoute},
+	{Path: "/v1/desired_lrps/:process_guid/stop", Method: "POST", Name: StopDesiredLRPRoute},
+	{Path: "/v1/desired_lrps/:process_guid/start", Method: "POST", Name: StartDesiredLRPRoute},
+
+	// DesiredLRPs by Domain
+	{Path: "/v1/domains/:domain/desired_lrps/scale", Method: "POST", Name: ScaleDesiredLRPsByDomainRoute},
+	{Path: "/v1/domains/:domain/desired_lrps/restart", Method: "POST", Name: RestartDesiredLRPsByDomainRoute},
+	{Path: "/v1/domains/:domain/desired_lrps", Method: "DELETE", Name: DeleteDesiredLRPsByDomainRoute},
+
+	// Rolling Updates
+	{Path: "/v1/desired_lrps/:process_guid/rolling_update", Method: "POST", Name: StartRollingUpdateRoute},
+	{Path: "/v1/desired_lrps/:process_guid/rolling_update", Method: "GET", Name: GetRollingUpdateRoute},
+	{Path: "/v1/desired_lrps/:process_guid/rolling_update/abort", Method: "POST", Name: AbortRollingUpdateRoute},
+
+	// Autoscaling Policies
+	{Path: "/v1/autoscaling_policies", Method: "GET", Name: AutoscalingPoliciesRoute},
+	{Path: "/v1/autoscaling_policies/:process_guid", Method: "GET", Name: GetAutoscalingPolicyRoute},
+	{Path: "/v1/autoscaling_policies/:process_guid", Method: "PUT", Name: SetAutoscalingPolicyRoute},
+	{Path: "/v1/autoscaling_policies/:process_guid", Method: "DELETE", Name: DeleteAutoscalingPolicyRoute},
 
 	// ActualLRPs
 	{Path: "/v1/actual_lrps", Method: "GET", Name: ActualLRPsRoute},
+	{Path: "/v1/actual_lrps/crashing", Method: "GET", Name: CrashingActualLRPsRoute},
 	{Path: "/v1/actual_lrps/:process_guid", Method: "GET", Name: ActualLRPsByProcessGuidRoute},
 	{Path: "/v1/actual_lrps/:process_guid/index/:index", Method: "GET", Name: ActualLRPByProcessGuidAndIndexRoute},
 	{Path: "/v1/actual_lrps/:process_guid/index/:index", Method: "DELETE", Name: KillActualLRPByProcessGuidAndIndexRoute},
+	{Path: "/v1/actual_lrps/:process_guid", Method: "DELETE", Name: KillActualLRPsByProcessGuidRoute},
+	{Path: "/v1/actual_lrps/:process_guid/instances/:instance_guid", Method: "DELETE", Name: KillActualLRPByInstanceGuidRoute},
+	{Path: "/v1/actual_lrps/:process_guid/index/:index/crashes", Method: "GET", Name: ActualLRPCrashesRoute},
 
 	// Cells
 	{Path: "/v1/cells", Method: "GET", Name: CellsRoute},
@@ -14,7 +14,7 @@ func ActualLRPProtoToResponse(actualLRP *models.ActualLRP, evacuating bool) rece
 		Index:           int(actualLRP.Index),
 		Address:         actualLRP.Address,
 		Ports:           PortMappingFromProto(actualLRP.Ports),
-		State:           actualLRPProtoStateToResponseState(actualLRP.State),
+		State:           ActualLRPStateToResponseState(actualLRP.State),
 		PlacementError:  actualLRP.PlacementError,
 		Since:           actualLRP.Since,
 		CrashCount:      int(actualLRP.CrashCount),
@@ -24,7 +24,20 @@ func ActualLRPProtoToResponse(actualLRP *models.ActualLRP, evacuating bool) rece
 	}
 }
 
-func actualLRPProtoStateToResponseState(state string) receptor.ActualLRPState {
+func ActualLRPGroupProtoToResponse(actualLRPGroup *models.ActualLRPGroup) receptor.ActualLRPGroupResponse {
+	response := receptor.ActualLRPGroupResponse{}
+	if actualLRPGroup.Instance != nil {
+		instance := ActualLRPProtoToResponse(actualLRPGroup.Instance, false)
+		response.Instance = &instance
+	}
+	if actualLRPGroup.Evacuating != nil {
+		evacuating := ActualLRPProtoToResponse(actualLRPGroup.Evacuating, true)
+		response.Evacuating = &evacuating
+	}
+	return response
+}
+
+func ActualLRPStateToResponseState(state string) receptor.ActualLRPState {
 	switch state {
 	case models.ActualLRPStateUnclaimed:
 		return receptor.ActualLRPStateUnclaimed
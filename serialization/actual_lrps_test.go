@@ -107,4 +107,41 @@ var _ = Describe("ActualLRP Serialization", func() {
 			})
 		})
 	})
+
+	Describe("ActualLRPGroupProtoToResponse", func() {
+		var instance, evacuating *models.ActualLRP
+
+		BeforeEach(func() {
+			instance = &models.ActualLRP{
+				ActualLRPKey:         models.NewActualLRPKey("process-guid-0", 1, "some-domain"),
+				ActualLRPInstanceKey: models.NewActualLRPInstanceKey("instance-guid-0", "cell-id-0"),
+				State:                models.ActualLRPStateClaimed,
+			}
+			evacuating = &models.ActualLRP{
+				ActualLRPKey:         models.NewActualLRPKey("process-guid-0", 1, "some-domain"),
+				ActualLRPInstanceKey: models.NewActualLRPInstanceKey("instance-guid-1", "cell-id-1"),
+				State:                models.ActualLRPStateRunning,
+			}
+		})
+
+		It("serializes both the instance and the evacuating actual LRP", func() {
+			response := serialization.ActualLRPGroupProtoToResponse(&models.ActualLRPGroup{
+				Instance:   instance,
+				Evacuating: evacuating,
+			})
+
+			expectedInstance := serialization.ActualLRPProtoToResponse(instance, false)
+			expectedEvacuating := serialization.ActualLRPProtoToResponse(evacuating, true)
+			Expect(response).To(Equal(receptor.ActualLRPGroupResponse{
+				Instance:   &expectedInstance,
+				Evacuating: &expectedEvacuating,
+			}))
+		})
+
+		It("leaves out whichever is missing", func() {
+			response := serialization.ActualLRPGroupProtoToResponse(&models.ActualLRPGroup{Evacuating: evacuating})
+			Expect(response.Instance).To(BeNil())
+			Expect(response.Evacuating.InstanceGuid).To(Equal("instance-guid-1"))
+		})
+	})
 })
@@ -31,6 +31,16 @@ func DesiredLRPProtoToResponse(lrp *models.DesiredLRP) receptor.DesiredLRPRespon
 	}
 }
 
+func DesiredLRPProtoToSummaryResponse(lrp *models.DesiredLRP) receptor.DesiredLRPSummaryResponse {
+	return receptor.DesiredLRPSummaryResponse{
+		ProcessGuid:     lrp.ProcessGuid,
+		Domain:          lrp.Domain,
+		Instances:       int(lrp.Instances),
+		Routes:          RoutingInfoFromProto(lrp.Routes),
+		ModificationTag: desiredLRPModificationTagProtoToResponseModificationTag(lrp.ModificationTag),
+	}
+}
+
 func RoutingInfoFromProto(routes *models.Routes) receptor.RoutingInfo {
 	if routes == nil {
 		return nil
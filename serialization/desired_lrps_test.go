@@ -85,4 +85,30 @@ var _ = Describe("DesiredLRP Serialization", func() {
 		})
 	})
 
+	Describe("DesiredLRPProtoToSummaryResponse", func() {
+		It("keeps only the scheduling attributes of the DesiredLRP", func() {
+			protoRoutes := models.Routes(routes)
+			desiredLRP := &models.DesiredLRP{
+				ProcessGuid: "the-process-guid",
+				Domain:      "the-domain",
+				RootFs:      "the-rootfs",
+				Instances:   3,
+				Action: models.WrapAction(&models.RunAction{
+					User: "me",
+					Path: "the-path",
+				}),
+				Routes:          &protoRoutes,
+				ModificationTag: &models.ModificationTag{Epoch: "some-epoch", Index: 2},
+			}
+
+			Expect(serialization.DesiredLRPProtoToSummaryResponse(desiredLRP)).To(Equal(receptor.DesiredLRPSummaryResponse{
+				ProcessGuid:     "the-process-guid",
+				Domain:          "the-domain",
+				Instances:       3,
+				Routes:          routingInfo,
+				ModificationTag: receptor.ModificationTag{Epoch: "some-epoch", Index: 2},
+			}))
+		})
+	})
+
 })
@@ -31,6 +31,28 @@ func TaskFromRequest(req receptor.TaskCreateRequest) (*models.Task, error) {
 	return task, nil
 }
 
+// TaskToRequest rebuilds the request which would recreate the task.
+func TaskToRequest(task *models.Task) receptor.TaskCreateRequest {
+	return receptor.TaskCreateRequest{
+		Action:                task.Action,
+		Annotation:            task.Annotation,
+		CompletionCallbackURL: task.CompletionCallbackUrl,
+		CPUWeight:             uint(task.CpuWeight),
+		DiskMB:                int(task.DiskMb),
+		Domain:                task.Domain,
+		LogGuid:               task.LogGuid,
+		LogSource:             task.LogSource,
+		MetricsGuid:           task.MetricsGuid,
+		MemoryMB:              int(task.MemoryMb),
+		ResultFile:            task.ResultFile,
+		TaskGuid:              task.TaskGuid,
+		RootFS:                task.RootFs,
+		Privileged:            task.Privileged,
+		EnvironmentVariables:  task.EnvironmentVariables,
+		EgressRules:           task.EgressRules,
+	}
+}
+
 func TaskToResponse(task *models.Task) receptor.TaskResponse {
 	return receptor.TaskResponse{
 		Action:                task.Action,
@@ -53,12 +75,12 @@ func TaskToResponse(task *models.Task) receptor.TaskResponse {
 		Failed:        task.Failed,
 		FailureReason: task.FailureReason,
 		Result:        task.Result,
-		State:         taskStateToResponseState(task.State),
+		State:         TaskStateToResponseState(task.State),
 		EgressRules:   task.EgressRules,
 	}
 }
 
-func taskStateToResponseState(state models.Task_State) string {
+func TaskStateToResponseState(state models.Task_State) string {
 	switch state {
 	case models.Task_Invalid:
 		return receptor.TaskStateInvalid
@@ -128,6 +128,38 @@ var _ = Describe("Task Serialization", func() {
 		})
 	})
 
+	Describe("TaskToRequest", func() {
+		It("rebuilds the request which created the task", func() {
+			request := receptor.TaskCreateRequest{
+				TaskGuid: "the-task-guid",
+				Domain:   "the-domain",
+				RootFS:   "the-rootfs",
+				Action: models.WrapAction(&models.RunAction{
+					User: "me",
+					Path: "the-path",
+				}),
+				MemoryMB:    100,
+				DiskMB:      100,
+				CPUWeight:   50,
+				Privileged:  true,
+				LogGuid:     "the-log-guid",
+				LogSource:   "the-source-name",
+				MetricsGuid: "the-metrics-guid",
+				ResultFile:  "the/result/file",
+				Annotation:  "the-annotation",
+				EnvironmentVariables: []*models.EnvironmentVariable{
+					{Name: "var1", Value: "val1"},
+				},
+				CompletionCallbackURL: "http://stager.service.discovery.thing/endpoint",
+			}
+
+			task, err := serialization.TaskFromRequest(request)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(serialization.TaskToRequest(task)).To(Equal(request))
+		})
+	})
+
 	Describe("TaskFromRequest", func() {
 		var request receptor.TaskCreateRequest
 		var expectedTask *models.Task
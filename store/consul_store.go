@@ -0,0 +1,96 @@
+package store
+
+import (
+	"strings"
+
+	"github.com/hashicorp/consul/api"
+)
+
+type consulStore struct {
+	kv     *api.KV
+	prefix string
+}
+
+// NewConsulStore returns a Store which keeps its entries in the consul
+// key-value store, under prefix.
+func NewConsulStore(client *api.Client, prefix string) Store {
+	return &consulStore{
+		kv:     client.KV(),
+		prefix: strings.TrimSuffix(prefix, "/") + "/",
+	}
+}
+
+func (s *consulStore) Get(key string) (Entry, error) {
+	pair, _, err := s.kv.Get(s.prefix+key, nil)
+	if err != nil {
+		return Entry{}, err
+	}
+	if pair == nil {
+		return Entry{}, ErrNotFound
+	}
+
+	return s.entry(pair), nil
+}
+
+func (s *consulStore) List(prefix string) ([]Entry, error) {
+	pairs, _, err := s.kv.List(s.prefix+prefix, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(pairs))
+	for _, pair := range pairs {
+		entries = append(entries, s.entry(pair))
+	}
+	return entries, nil
+}
+
+func (s *consulStore) Put(key string, value []byte) error {
+	_, err := s.kv.Put(&api.KVPair{
+		Key:   s.prefix + key,
+		Value: value,
+	}, nil)
+	return err
+}
+
+func (s *consulStore) CompareAndSwap(key string, index uint64, value []byte) error {
+	ok, _, err := s.kv.CAS(&api.KVPair{
+		Key:         s.prefix + key,
+		Value:       value,
+		ModifyIndex: index,
+	}, nil)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrConflict
+	}
+	return nil
+}
+
+func (s *consulStore) CompareAndDelete(key string, index uint64) error {
+	ok, _, err := s.kv.DeleteCAS(&api.KVPair{
+		Key:         s.prefix + key,
+		ModifyIndex: index,
+	}, nil)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrConflict
+	}
+	return nil
+}
+
+func (s *consulStore) Delete(key string) error {
+	_, err := s.kv.Delete(s.prefix+key, nil)
+	return err
+}
+
+func (s *consulStore) entry(pair *api.KVPair) Entry {
+	return Entry{
+		Key:   strings.TrimPrefix(pair.Key, s.prefix),
+		Value: pair.Value,
+		Index: pair.ModifyIndex,
+	}
+}
@@ -0,0 +1,180 @@
+// This file was generated by counterfeiter
+package fake_store
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry-incubator/bbs/models"
+	"github.com/cloudfoundry-incubator/receptor/store"
+)
+
+type FakeStoppedLRPStore struct {
+	InstancesStub        func(desiredLRP *models.DesiredLRP) (int32, error)
+	instancesMutex       sync.RWMutex
+	instancesArgsForCall []struct {
+		desiredLRP *models.DesiredLRP
+	}
+	instancesReturns struct {
+		result1 int32
+		result2 error
+	}
+	RecordStub        func(desiredLRP *models.DesiredLRP, instances int32) error
+	recordMutex       sync.RWMutex
+	recordArgsForCall []struct {
+		desiredLRP *models.DesiredLRP
+		instances  int32
+	}
+	recordReturns struct {
+		result1 error
+	}
+	ClaimStub        func(desiredLRP *models.DesiredLRP) (int32, error)
+	claimMutex       sync.RWMutex
+	claimArgsForCall []struct {
+		desiredLRP *models.DesiredLRP
+	}
+	claimReturns struct {
+		result1 int32
+		result2 error
+	}
+	ForgetStub        func(processGuid string) error
+	forgetMutex       sync.RWMutex
+	forgetArgsForCall []struct {
+		processGuid string
+	}
+	forgetReturns struct {
+		result1 error
+	}
+}
+
+func (fake *FakeStoppedLRPStore) Instances(desiredLRP *models.DesiredLRP) (int32, error) {
+	fake.instancesMutex.Lock()
+	fake.instancesArgsForCall = append(fake.instancesArgsForCall, struct {
+		desiredLRP *models.DesiredLRP
+	}{desiredLRP})
+	fake.instancesMutex.Unlock()
+	if fake.InstancesStub != nil {
+		return fake.InstancesStub(desiredLRP)
+	} else {
+		return fake.instancesReturns.result1, fake.instancesReturns.result2
+	}
+}
+
+func (fake *FakeStoppedLRPStore) InstancesCallCount() int {
+	fake.instancesMutex.RLock()
+	defer fake.instancesMutex.RUnlock()
+	return len(fake.instancesArgsForCall)
+}
+
+func (fake *FakeStoppedLRPStore) InstancesArgsForCall(i int) *models.DesiredLRP {
+	fake.instancesMutex.RLock()
+	defer fake.instancesMutex.RUnlock()
+	return fake.instancesArgsForCall[i].desiredLRP
+}
+
+func (fake *FakeStoppedLRPStore) InstancesReturns(result1 int32, result2 error) {
+	fake.InstancesStub = nil
+	fake.instancesReturns = struct {
+		result1 int32
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStoppedLRPStore) Record(desiredLRP *models.DesiredLRP, instances int32) error {
+	fake.recordMutex.Lock()
+	fake.recordArgsForCall = append(fake.recordArgsForCall, struct {
+		desiredLRP *models.DesiredLRP
+		instances  int32
+	}{desiredLRP, instances})
+	fake.recordMutex.Unlock()
+	if fake.RecordStub != nil {
+		return fake.RecordStub(desiredLRP, instances)
+	} else {
+		return fake.recordReturns.result1
+	}
+}
+
+func (fake *FakeStoppedLRPStore) RecordCallCount() int {
+	fake.recordMutex.RLock()
+	defer fake.recordMutex.RUnlock()
+	return len(fake.recordArgsForCall)
+}
+
+func (fake *FakeStoppedLRPStore) RecordArgsForCall(i int) (*models.DesiredLRP, int32) {
+	fake.recordMutex.RLock()
+	defer fake.recordMutex.RUnlock()
+	return fake.recordArgsForCall[i].desiredLRP, fake.recordArgsForCall[i].instances
+}
+
+func (fake *FakeStoppedLRPStore) RecordReturns(result1 error) {
+	fake.RecordStub = nil
+	fake.recordReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStoppedLRPStore) Claim(desiredLRP *models.DesiredLRP) (int32, error) {
+	fake.claimMutex.Lock()
+	fake.claimArgsForCall = append(fake.claimArgsForCall, struct {
+		desiredLRP *models.DesiredLRP
+	}{desiredLRP})
+	fake.claimMutex.Unlock()
+	if fake.ClaimStub != nil {
+		return fake.ClaimStub(desiredLRP)
+	} else {
+		return fake.claimReturns.result1, fake.claimReturns.result2
+	}
+}
+
+func (fake *FakeStoppedLRPStore) ClaimCallCount() int {
+	fake.claimMutex.RLock()
+	defer fake.claimMutex.RUnlock()
+	return len(fake.claimArgsForCall)
+}
+
+func (fake *FakeStoppedLRPStore) ClaimArgsForCall(i int) *models.DesiredLRP {
+	fake.claimMutex.RLock()
+	defer fake.claimMutex.RUnlock()
+	return fake.claimArgsForCall[i].desiredLRP
+}
+
+func (fake *FakeStoppedLRPStore) ClaimReturns(result1 int32, result2 error) {
+	fake.ClaimStub = nil
+	fake.claimReturns = struct {
+		result1 int32
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStoppedLRPStore) Forget(processGuid string) error {
+	fake.forgetMutex.Lock()
+	fake.forgetArgsForCall = append(fake.forgetArgsForCall, struct {
+		processGuid string
+	}{processGuid})
+	fake.forgetMutex.Unlock()
+	if fake.ForgetStub != nil {
+		return fake.ForgetStub(processGuid)
+	} else {
+		return fake.forgetReturns.result1
+	}
+}
+
+func (fake *FakeStoppedLRPStore) ForgetCallCount() int {
+	fake.forgetMutex.RLock()
+	defer fake.forgetMutex.RUnlock()
+	return len(fake.forgetArgsForCall)
+}
+
+func (fake *FakeStoppedLRPStore) ForgetArgsForCall(i int) string {
+	fake.forgetMutex.RLock()
+	defer fake.forgetMutex.RUnlock()
+	return fake.forgetArgsForCall[i].processGuid
+}
+
+func (fake *FakeStoppedLRPStore) ForgetReturns(result1 error) {
+	fake.ForgetStub = nil
+	fake.forgetReturns = struct {
+		result1 error
+	}{result1}
+}
+
+var _ store.StoppedLRPStore = new(FakeStoppedLRPStore)
@@ -0,0 +1,265 @@
+// This file was generated by counterfeiter
+package fake_store
+
+import (
+	"sync"
+
+	"github.com/cloudfoundry-incubator/receptor/store"
+)
+
+type FakeStore struct {
+	GetStub        func(key string) (store.Entry, error)
+	getMutex       sync.RWMutex
+	getArgsForCall []struct {
+		key string
+	}
+	getReturns struct {
+		result1 store.Entry
+		result2 error
+	}
+	ListStub        func(prefix string) ([]store.Entry, error)
+	listMutex       sync.RWMutex
+	listArgsForCall []struct {
+		prefix string
+	}
+	listReturns struct {
+		result1 []store.Entry
+		result2 error
+	}
+	PutStub        func(key string, value []byte) error
+	putMutex       sync.RWMutex
+	putArgsForCall []struct {
+		key   string
+		value []byte
+	}
+	putReturns struct {
+		result1 error
+	}
+	CompareAndSwapStub        func(key string, index uint64, value []byte) error
+	compareAndSwapMutex       sync.RWMutex
+	compareAndSwapArgsForCall []struct {
+		key   string
+		index uint64
+		value []byte
+	}
+	compareAndSwapReturns struct {
+		result1 error
+	}
+	CompareAndDeleteStub        func(key string, index uint64) error
+	compareAndDeleteMutex       sync.RWMutex
+	compareAndDeleteArgsForCall []struct {
+		key   string
+		index uint64
+	}
+	compareAndDeleteReturns struct {
+		result1 error
+	}
+	DeleteStub        func(key string) error
+	deleteMutex       sync.RWMutex
+	deleteArgsForCall []struct {
+		key string
+	}
+	deleteReturns struct {
+		result1 error
+	}
+}
+
+func (fake *FakeStore) Get(key string) (store.Entry, error) {
+	fake.getMutex.Lock()
+	fake.getArgsForCall = append(fake.getArgsForCall, struct {
+		key string
+	}{key})
+	fake.getMutex.Unlock()
+	if fake.GetStub != nil {
+		return fake.GetStub(key)
+	} else {
+		return fake.getReturns.result1, fake.getReturns.result2
+	}
+}
+
+func (fake *FakeStore) GetCallCount() int {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	return len(fake.getArgsForCall)
+}
+
+func (fake *FakeStore) GetArgsForCall(i int) string {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	return fake.getArgsForCall[i].key
+}
+
+func (fake *FakeStore) GetReturns(result1 store.Entry, result2 error) {
+	fake.GetStub = nil
+	fake.getReturns = struct {
+		result1 store.Entry
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) List(prefix string) ([]store.Entry, error) {
+	fake.listMutex.Lock()
+	fake.listArgsForCall = append(fake.listArgsForCall, struct {
+		prefix string
+	}{prefix})
+	fake.listMutex.Unlock()
+	if fake.ListStub != nil {
+		return fake.ListStub(prefix)
+	} else {
+		return fake.listReturns.result1, fake.listReturns.result2
+	}
+}
+
+func (fake *FakeStore) ListCallCount() int {
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	return len(fake.listArgsForCall)
+}
+
+func (fake *FakeStore) ListArgsForCall(i int) string {
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	return fake.listArgsForCall[i].prefix
+}
+
+func (fake *FakeStore) ListReturns(result1 []store.Entry, result2 error) {
+	fake.ListStub = nil
+	fake.listReturns = struct {
+		result1 []store.Entry
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeStore) Put(key string, value []byte) error {
+	fake.putMutex.Lock()
+	fake.putArgsForCall = append(fake.putArgsForCall, struct {
+		key   string
+		value []byte
+	}{key, value})
+	fake.putMutex.Unlock()
+	if fake.PutStub != nil {
+		return fake.PutStub(key, value)
+	} else {
+		return fake.putReturns.result1
+	}
+}
+
+func (fake *FakeStore) PutCallCount() int {
+	fake.putMutex.RLock()
+	defer fake.putMutex.RUnlock()
+	return len(fake.putArgsForCall)
+}
+
+func (fake *FakeStore) PutArgsForCall(i int) (string, []byte) {
+	fake.putMutex.RLock()
+	defer fake.putMutex.RUnlock()
+	return fake.putArgsForCall[i].key, fake.putArgsForCall[i].value
+}
+
+func (fake *FakeStore) PutReturns(result1 error) {
+	fake.PutStub = nil
+	fake.putReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) CompareAndSwap(key string, index uint64, value []byte) error {
+	fake.compareAndSwapMutex.Lock()
+	fake.compareAndSwapArgsForCall = append(fake.compareAndSwapArgsForCall, struct {
+		key   string
+		index uint64
+		value []byte
+	}{key, index, value})
+	fake.compareAndSwapMutex.Unlock()
+	if fake.CompareAndSwapStub != nil {
+		return fake.CompareAndSwapStub(key, index, value)
+	} else {
+		return fake.compareAndSwapReturns.result1
+	}
+}
+
+func (fake *FakeStore) CompareAndSwapCallCount() int {
+	fake.compareAndSwapMutex.RLock()
+	defer fake.compareAndSwapMutex.RUnlock()
+	return len(fake.compareAndSwapArgsForCall)
+}
+
+func (fake *FakeStore) CompareAndSwapArgsForCall(i int) (string, uint64, []byte) {
+	fake.compareAndSwapMutex.RLock()
+	defer fake.compareAndSwapMutex.RUnlock()
+	return fake.compareAndSwapArgsForCall[i].key, fake.compareAndSwapArgsForCall[i].index, fake.compareAndSwapArgsForCall[i].value
+}
+
+func (fake *FakeStore) CompareAndSwapReturns(result1 error) {
+	fake.CompareAndSwapStub = nil
+	fake.compareAndSwapReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) CompareAndDelete(key string, index uint64) error {
+	fake.compareAndDeleteMutex.Lock()
+	fake.compareAndDeleteArgsForCall = append(fake.compareAndDeleteArgsForCall, struct {
+		key   string
+		index uint64
+	}{key, index})
+	fake.compareAndDeleteMutex.Unlock()
+	if fake.CompareAndDeleteStub != nil {
+		return fake.CompareAndDeleteStub(key, index)
+	} else {
+		return fake.compareAndDeleteReturns.result1
+	}
+}
+
+func (fake *FakeStore) CompareAndDeleteCallCount() int {
+	fake.compareAndDeleteMutex.RLock()
+	defer fake.compareAndDeleteMutex.RUnlock()
+	return len(fake.compareAndDeleteArgsForCall)
+}
+
+func (fake *FakeStore) CompareAndDeleteArgsForCall(i int) (string, uint64) {
+	fake.compareAndDeleteMutex.RLock()
+	defer fake.compareAndDeleteMutex.RUnlock()
+	return fake.compareAndDeleteArgsForCall[i].key, fake.compareAndDeleteArgsForCall[i].index
+}
+
+func (fake *FakeStore) CompareAndDeleteReturns(result1 error) {
+	fake.CompareAndDeleteStub = nil
+	fake.compareAndDeleteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeStore) Delete(key string) error {
+	fake.deleteMutex.Lock()
+	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
+		key string
+	}{key})
+	fake.deleteMutex.Unlock()
+	if fake.DeleteStub != nil {
+		return fake.DeleteStub(key)
+	} else {
+		return fake.deleteReturns.result1
+	}
+}
+
+func (fake *FakeStore) DeleteCallCount() int {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return len(fake.deleteArgsForCall)
+}
+
+func (fake *FakeStore) DeleteArgsForCall(i int) string {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return fake.deleteArgsForCall[i].key
+}
+
+func (fake *FakeStore) DeleteReturns(result1 error) {
+	fake.DeleteStub = nil
+	fake.deleteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+var _ store.Store = new(FakeStore)
@@ -0,0 +1,143 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/cloudfoundry-incubator/bbs/models"
+)
+
+const stoppedLRPsPrefix = "stopped-lrps/"
+
+var (
+	ErrAlreadyStopped = errors.New("desired LRP is already stopped")
+	ErrNotStopped     = errors.New("desired LRP is not stopped")
+)
+
+//go:generate counterfeiter -o fake_store/fake_stopped_lrp_store.go . StoppedLRPStore
+
+// StoppedLRPStore records the instance count of stopped DesiredLRPs so that
+// they can be started again. A record only applies to the DesiredLRP it was
+// made for: once a DesiredLRP is removed and desired again, which gives it a
+// new ModificationTag epoch, the record of the removed one is ignored.
+type StoppedLRPStore interface {
+	// Instances returns the instance count recorded for desiredLRP. It fails
+	// with ErrNotStopped when there is none.
+	Instances(desiredLRP *models.DesiredLRP) (int32, error)
+
+	// Record records the instance count of desiredLRP. It fails with
+	// ErrAlreadyStopped when desiredLRP already has a record.
+	Record(desiredLRP *models.DesiredLRP, instances int32) error
+
+	// Claim removes the record of desiredLRP and returns the instance count
+	// it held. Only one of several concurrent claims succeeds, the others fail
+	// with ErrNotStopped.
+	Claim(desiredLRP *models.DesiredLRP) (int32, error)
+
+	// Forget removes the record of a DesiredLRP, if any.
+	Forget(processGuid string) error
+}
+
+type stoppedLRP struct {
+	Epoch     string `json:"epoch"`
+	Instances int32  `json:"instances"`
+}
+
+type stoppedLRPStore struct {
+	store Store
+}
+
+func NewStoppedLRPStore(store Store) StoppedLRPStore {
+	return &stoppedLRPStore{store: store}
+}
+
+func (s *stoppedLRPStore) Instances(desiredLRP *models.DesiredLRP) (int32, error) {
+	record, _, err := s.get(desiredLRP)
+	if err != nil {
+		return 0, err
+	}
+	return record.Instances, nil
+}
+
+func (s *stoppedLRPStore) Record(desiredLRP *models.DesiredLRP, instances int32) error {
+	_, entry, err := s.get(desiredLRP)
+	if err == nil {
+		return ErrAlreadyStopped
+	}
+	if err != ErrNotStopped {
+		return err
+	}
+
+	payload, err := json.Marshal(stoppedLRP{
+		Epoch:     Epoch(desiredLRP),
+		Instances: instances,
+	})
+	if err != nil {
+		return err
+	}
+
+	// entry.Index is that of a record left by a removed DesiredLRP, if any
+	err = s.store.CompareAndSwap(stoppedLRPKey(desiredLRP.ProcessGuid), entry.Index, payload)
+	if err == ErrConflict {
+		return ErrAlreadyStopped
+	}
+	return err
+}
+
+func (s *stoppedLRPStore) Claim(desiredLRP *models.DesiredLRP) (int32, error) {
+	record, entry, err := s.get(desiredLRP)
+	if err != nil {
+		return 0, err
+	}
+
+	err = s.store.CompareAndDelete(entry.Key, entry.Index)
+	if err == ErrConflict || err == ErrNotFound {
+		return 0, ErrNotStopped
+	}
+	if err != nil {
+		return 0, err
+	}
+	return record.Instances, nil
+}
+
+func (s *stoppedLRPStore) Forget(processGuid string) error {
+	return s.store.Delete(stoppedLRPKey(processGuid))
+}
+
+// get returns the record of desiredLRP along with its entry. The entry is
+// also returned when it holds the record of a removed DesiredLRP, in which
+// case get fails with ErrNotStopped.
+func (s *stoppedLRPStore) get(desiredLRP *models.DesiredLRP) (stoppedLRP, Entry, error) {
+	entry, err := s.store.Get(stoppedLRPKey(desiredLRP.ProcessGuid))
+	if err == ErrNotFound {
+		return stoppedLRP{}, Entry{}, ErrNotStopped
+	}
+	if err != nil {
+		return stoppedLRP{}, Entry{}, err
+	}
+
+	var record stoppedLRP
+	err = json.Unmarshal(entry.Value, &record)
+	if err != nil {
+		return stoppedLRP{}, Entry{}, err
+	}
+
+	if record.Epoch != Epoch(desiredLRP) {
+		return stoppedLRP{}, entry, ErrNotStopped
+	}
+	return record, entry, nil
+}
+
+func stoppedLRPKey(processGuid string) string {
+	return stoppedLRPsPrefix + processGuid
+}
+
+// Epoch identifies a DesiredLRP across its updates, changing only when it is
+// removed and desired again. State kept about a DesiredLRP records its epoch
+// so that it is not applied to a new DesiredLRP with the same process guid.
+func Epoch(desiredLRP *models.DesiredLRP) string {
+	if desiredLRP.ModificationTag == nil {
+		return ""
+	}
+	return desiredLRP.ModificationTag.Epoch
+}
@@ -0,0 +1,193 @@
+package store_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/bbs/models"
+	"github.com/cloudfoundry-incubator/receptor/store"
+	"github.com/cloudfoundry-incubator/receptor/store/fake_store"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("StoppedLRPStore", func() {
+	var (
+		fakeStore   *fake_store.FakeStore
+		stoppedLRPs store.StoppedLRPStore
+		desiredLRP  *models.DesiredLRP
+	)
+
+	BeforeEach(func() {
+		fakeStore = new(fake_store.FakeStore)
+		fakeStore.GetReturns(store.Entry{}, store.ErrNotFound)
+		stoppedLRPs = store.NewStoppedLRPStore(fakeStore)
+
+		desiredLRP = &models.DesiredLRP{
+			ProcessGuid:     "process-guid",
+			Instances:       3,
+			ModificationTag: &models.ModificationTag{Epoch: "epoch", Index: 2},
+		}
+	})
+
+	Describe("Record", func() {
+		It("creates a record of the instance count", func() {
+			err := stoppedLRPs.Record(desiredLRP, 3)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeStore.CompareAndSwapCallCount()).To(Equal(1))
+			key, index, value := fakeStore.CompareAndSwapArgsForCall(0)
+			Expect(key).To(Equal("stopped-lrps/process-guid"))
+			Expect(index).To(BeZero())
+			Expect(value).To(MatchJSON(`{"epoch":"epoch","instances":3}`))
+		})
+
+		Context("when the desired LRP already has a record", func() {
+			BeforeEach(func() {
+				fakeStore.GetReturns(store.Entry{
+					Key:   "stopped-lrps/process-guid",
+					Value: []byte(`{"epoch":"epoch","instances":5}`),
+					Index: 7,
+				}, nil)
+			})
+
+			It("fails with ErrAlreadyStopped", func() {
+				err := stoppedLRPs.Record(desiredLRP, 3)
+				Expect(err).To(Equal(store.ErrAlreadyStopped))
+				Expect(fakeStore.CompareAndSwapCallCount()).To(BeZero())
+			})
+		})
+
+		Context("when the record belongs to a removed desired LRP", func() {
+			BeforeEach(func() {
+				fakeStore.GetReturns(store.Entry{
+					Key:   "stopped-lrps/process-guid",
+					Value: []byte(`{"epoch":"old-epoch","instances":5}`),
+					Index: 7,
+				}, nil)
+			})
+
+			It("replaces it", func() {
+				err := stoppedLRPs.Record(desiredLRP, 3)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, index, value := fakeStore.CompareAndSwapArgsForCall(0)
+				Expect(index).To(BeEquivalentTo(7))
+				Expect(value).To(MatchJSON(`{"epoch":"epoch","instances":3}`))
+			})
+		})
+
+		Context("when another record is created concurrently", func() {
+			BeforeEach(func() {
+				fakeStore.CompareAndSwapReturns(store.ErrConflict)
+			})
+
+			It("fails with ErrAlreadyStopped", func() {
+				err := stoppedLRPs.Record(desiredLRP, 3)
+				Expect(err).To(Equal(store.ErrAlreadyStopped))
+			})
+		})
+	})
+
+	Describe("Instances", func() {
+		Context("when the desired LRP has a record", func() {
+			BeforeEach(func() {
+				fakeStore.GetReturns(store.Entry{
+					Key:   "stopped-lrps/process-guid",
+					Value: []byte(`{"epoch":"epoch","instances":5}`),
+					Index: 7,
+				}, nil)
+			})
+
+			It("returns the recorded instance count", func() {
+				instances, err := stoppedLRPs.Instances(desiredLRP)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(instances).To(BeEquivalentTo(5))
+				Expect(fakeStore.GetArgsForCall(0)).To(Equal("stopped-lrps/process-guid"))
+			})
+		})
+
+		Context("when the desired LRP has no record", func() {
+			It("fails with ErrNotStopped", func() {
+				_, err := stoppedLRPs.Instances(desiredLRP)
+				Expect(err).To(Equal(store.ErrNotStopped))
+			})
+		})
+
+		Context("when the record belongs to a removed desired LRP", func() {
+			BeforeEach(func() {
+				fakeStore.GetReturns(store.Entry{
+					Value: []byte(`{"epoch":"old-epoch","instances":5}`),
+				}, nil)
+			})
+
+			It("fails with ErrNotStopped", func() {
+				_, err := stoppedLRPs.Instances(desiredLRP)
+				Expect(err).To(Equal(store.ErrNotStopped))
+			})
+		})
+
+		Context("when the store fails", func() {
+			BeforeEach(func() {
+				fakeStore.GetReturns(store.Entry{}, errors.New("oops"))
+			})
+
+			It("fails", func() {
+				_, err := stoppedLRPs.Instances(desiredLRP)
+				Expect(err).To(MatchError("oops"))
+			})
+		})
+	})
+
+	Describe("Claim", func() {
+		BeforeEach(func() {
+			fakeStore.GetReturns(store.Entry{
+				Key:   "stopped-lrps/process-guid",
+				Value: []byte(`{"epoch":"epoch","instances":5}`),
+				Index: 7,
+			}, nil)
+		})
+
+		It("removes the record it read and returns its instance count", func() {
+			instances, err := stoppedLRPs.Claim(desiredLRP)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(instances).To(BeEquivalentTo(5))
+
+			Expect(fakeStore.CompareAndDeleteCallCount()).To(Equal(1))
+			key, index := fakeStore.CompareAndDeleteArgsForCall(0)
+			Expect(key).To(Equal("stopped-lrps/process-guid"))
+			Expect(index).To(BeEquivalentTo(7))
+		})
+
+		Context("when another claim removes the record first", func() {
+			BeforeEach(func() {
+				fakeStore.CompareAndDeleteReturns(store.ErrConflict)
+			})
+
+			It("fails with ErrNotStopped", func() {
+				_, err := stoppedLRPs.Claim(desiredLRP)
+				Expect(err).To(Equal(store.ErrNotStopped))
+			})
+		})
+
+		Context("when the desired LRP has no record", func() {
+			BeforeEach(func() {
+				fakeStore.GetReturns(store.Entry{}, store.ErrNotFound)
+			})
+
+			It("fails with ErrNotStopped", func() {
+				_, err := stoppedLRPs.Claim(desiredLRP)
+				Expect(err).To(Equal(store.ErrNotStopped))
+				Expect(fakeStore.CompareAndDeleteCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	Describe("Forget", func() {
+		It("deletes the record", func() {
+			err := stoppedLRPs.Forget("process-guid")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeStore.DeleteArgsForCall(0)).To(Equal("stopped-lrps/process-guid"))
+		})
+	})
+})
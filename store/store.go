@@ -0,0 +1,42 @@
+package store
+
+import "errors"
+
+var (
+	ErrNotFound = errors.New("key not found")
+	ErrConflict = errors.New("key was modified concurrently")
+)
+
+// Entry is a value held by a Store. Index changes every time the value is
+// written, so that writers can detect concurrent modifications.
+type Entry struct {
+	Key   string
+	Value []byte
+	Index uint64
+}
+
+//go:generate counterfeiter -o fake_store/fake_store.go . Store
+
+// Store is a key-value store shared by every receptor. It holds the state
+// the receptor keeps about DesiredLRPs besides what the BBS stores.
+type Store interface {
+	Get(key string) (Entry, error)
+
+	// List returns the entries whose key starts with prefix.
+	List(prefix string) ([]Entry, error)
+
+	// Put writes value, whatever the entry held.
+	Put(key string, value []byte) error
+
+	// CompareAndSwap writes value when the entry is still at index. An index
+	// of 0 only writes the value when the key does not exist yet. It fails
+	// with ErrConflict otherwise.
+	CompareAndSwap(key string, index uint64, value []byte) error
+
+	// CompareAndDelete deletes the entry when it is still at index, failing
+	// with ErrConflict otherwise.
+	CompareAndDelete(key string, index uint64) error
+
+	// Delete deletes the entry, if any.
+	Delete(key string) error
+}